@@ -3,6 +3,7 @@ package app_service
 import (
 	"context"
 
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
@@ -14,9 +15,14 @@ type CreateCommand struct {
 	FeedURL            string `validate:"required,url,startswith=http"`
 	SourceLanguageCode string `validate:"required,oneof=af sq am ar hy az bn bs bg ca zh zh-TW hr cs da fa-AF nl en et fa tl fi fr fr-CA ka de el gu ht ha he hi hu is id ga it ja kn kk ko lv lt mk ms ml mt mr mn no ps pl pt pt-PT pa ro ru sr si sk sl so es es-MX sw sv ta te th tr uk ur uz vi cy"`
 	ItemFilter         struct {
-		IncludeKeywords []string `json:"include_keywords"`
-		ExcludeKeywords []string `json:"exclude_keywords"`
+		IncludeKeywords []string `json:"include_keywords" validate:"dive,keyword"`
+		ExcludeKeywords []string `json:"exclude_keywords" validate:"dive,keyword"`
+		Expression      string   `json:"expression"`
 	} `json:"item_filter"`
+	Retention struct {
+		MaxAgeDays int `json:"max_age_days" validate:"min=0,max=3650"`
+		MaxItems   int `json:"max_items" validate:"min=0,max=10000"`
+	} `json:"retention"`
 }
 
 func Execute(ctx context.Context, logger infrastructure.Logger, publisher publisher.SubscribeMessagePublisher, command CreateCommand) error {
@@ -36,10 +42,18 @@ func Trigger(ctx context.Context, logger infrastructure.Logger, publisher publis
 		return err
 	}
 
+	itemFilter, err := rss.NewItemFilterWithExpression(command.ItemFilter.IncludeKeywords, command.ItemFilter.ExcludeKeywords, command.ItemFilter.Expression)
+	if err != nil {
+		return validation_error.New(map[string]string{
+			"item_filter.expression": err.Error(),
+		})
+	}
+
 	message := message.Subscribe{
 		FeedURL:    command.FeedURL,
 		Language:   command.SourceLanguageCode,
-		ItemFilter: rss.NewItemFilter(command.ItemFilter.IncludeKeywords, command.ItemFilter.ExcludeKeywords),
+		ItemFilter: itemFilter,
+		Retention:  rss.NewRetention(command.Retention.MaxAgeDays, command.Retention.MaxItems),
 	}
 
 	return publisher.Publish(ctx, message)
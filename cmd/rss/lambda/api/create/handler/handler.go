@@ -21,9 +21,14 @@ type requestBody struct {
 	FeedURL            string `json:"feed_url"`
 	SourceLanguageCode string `json:"source_language_code"`
 	ItemFilter         struct {
-		IncludeKeywords []string `json:"include_keywords"`
-		ExcludeKeywords []string `json:"exclude_keywords"`
+		IncludeKeywords []string `json:"include_keywords" validate:"dive,keyword"`
+		ExcludeKeywords []string `json:"exclude_keywords" validate:"dive,keyword"`
+		Expression      string   `json:"expression"`
 	} `json:"item_filter"`
+	Retention struct {
+		MaxAgeDays int `json:"max_age_days" validate:"min=0,max=3650"`
+		MaxItems   int `json:"max_items" validate:"min=0,max=10000"`
+	} `json:"retention"`
 }
 
 type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.CreateCommand) error
@@ -37,11 +42,19 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
 
+	logger.Info("finish")
+	return NewProxyHandler(publisher)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(publisher *publisher.SubscribeMessagePublisher) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
 	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.CreateCommand) error {
 		return app_service.Execute(ctx, logger, *publisher, command)
 	}
-	logger.Info("finish")
-	return processRecord(ctx, logger, executer, request), nil
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(shared.WithRequestTrace(ctx, request), logger, executer, request)
+	}
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
@@ -55,6 +68,7 @@ func processRecord(ctx context.Context, logger infrastructure.Logger, executer e
 		FeedURL:            requestBody.FeedURL,
 		SourceLanguageCode: requestBody.SourceLanguageCode,
 		ItemFilter:         requestBody.ItemFilter,
+		Retention:          requestBody.Retention,
 	}
 
 	err := executer(ctx, logger, cmd)
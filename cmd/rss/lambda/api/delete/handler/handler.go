@@ -27,11 +27,19 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
 
+	logger.Info("finish")
+	return NewProxyHandler(publisher)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(publisher *publisher.DeleteMessagePublisher) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
 	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.DeleteCommand) error {
 		return app_service.Execute(ctx, logger, *publisher, command)
 	}
-	logger.Info("finish")
-	return processRecord(ctx, logger, executer, request), nil
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(shared.WithRequestTrace(ctx, request), logger, executer, request)
+	}
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
@@ -25,6 +25,11 @@ type RssResponse struct {
 	Language      string            `json:"language"`
 	LastBuildDate time.Time         `json:"last_build_date"`
 	ItemFilter    rss.ItemFilter    `json:"item_filter"`
+	Retention     rss.Retention     `json:"retention"`
+	Status        rss.Status        `json:"status"`
+	FetchStatus   rss.FetchStatus   `json:"fetch_status"`
+	TrashedAt     *time.Time        `json:"trashed_at,omitempty"`
+	PurgeAt       *time.Time        `json:"purge_at,omitempty"`
 	CreatedBy     metadata.CreateBy `json:"create_by"`
 	CreatedAt     metadata.CreateAt `json:"create_at"`
 	UpdatedBy     metadata.UpdateBy `json:"update_by"`
@@ -69,11 +74,19 @@ func GetRssFeed(ctx context.Context, logger infrastructure.Logger, rssRepository
 		Language:      feed.Language,
 		LastBuildDate: feed.LastBuildDate,
 		ItemFilter:    feed.ItemFilter,
+		Retention:     feed.Retention,
+		Status:        feed.Status,
+		FetchStatus:   feed.FetchStatus,
 		CreatedBy:     feed.CreatedBy,
 		CreatedAt:     feed.CreatedAt,
 		UpdatedBy:     feed.UpdatedBy,
 		UpdatedAt:     feed.UpdatedAt,
 	}
 
+	if feed.IsTrashed() {
+		response.TrashedAt = &feed.TrashedAt
+		response.PurgeAt = &feed.PurgeAt
+	}
+
 	return response, nil
 }
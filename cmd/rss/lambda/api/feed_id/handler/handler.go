@@ -19,18 +19,27 @@ import (
 type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.GetCommand) (app_service.RssResponse, error)
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	cfg := awsConfig.LoadConfig(ctx)
 	dynamodbClient := cfg.NewDynamodbClient()
-	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient)
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
 
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
 	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.GetCommand) (app_service.RssResponse, error) {
 		return app_service.Execute(ctx, logger, rssRepository, command)
 	}
-	logger.Info("finish")
-	return processRecord(ctx, logger, executer, request), nil
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(ctx, logger, executer, request)
+	}
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
@@ -14,6 +14,7 @@ type RssResponse struct {
 	Source        string    `json:"source"`
 	Title         string    `json:"title"`
 	Link          string    `json:"link"`
+	Status        string    `json:"status"`
 	LastBuildDate time.Time `json:"lastBuildDate"`
 	CreatedAt     time.Time `json:"createdAt"`
 	UpdatedAt     time.Time `json:"updatedAt"`
@@ -35,17 +36,22 @@ func AllRssFeeds(ctx context.Context, logger infrastructure.Logger, rssRepositor
 		return nil, err
 	}
 
-	response := make([]RssResponse, len(rssFeeds))
-	for i, feed := range rssFeeds {
-		response[i] = RssResponse{
+	response := make([]RssResponse, 0, len(rssFeeds))
+	for _, feed := range rssFeeds {
+		// Trashed feeds are listed by GET /api/v1/rss/trash instead.
+		if feed.IsTrashed() {
+			continue
+		}
+		response = append(response, RssResponse{
 			ID:            feed.ID,
 			Source:        feed.Source,
 			Title:         feed.Title,
 			Link:          feed.Link,
+			Status:        string(feed.Status),
 			LastBuildDate: feed.LastBuildDate,
 			CreatedAt:     feed.CreatedAt,
 			UpdatedAt:     feed.UpdatedAt,
-		}
+		})
 	}
 
 	return response, nil
@@ -0,0 +1,166 @@
+package app_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
+	subscribeService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/subscribe/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
+)
+
+var ErrFetchFeed = errors.New("failed to fetch the feed")
+
+const DefaultLimit = 100
+
+type PreviewCommand struct {
+	Source string `validate:"required"`
+	// Limit and Cursor page the stored items, newest first, like the items API.
+	Limit      int `validate:"min=1,max=500"`
+	Cursor     string
+	ItemFilter struct {
+		IncludeKeywords []string `validate:"dive,keyword"`
+		ExcludeKeywords []string `validate:"dive,keyword"`
+		Expression      string
+	}
+	// Live also evaluates the items the feed serves now, including the ones the
+	// current filter has kept from being stored, on the first page.
+	Live bool
+}
+
+type ItemResponse struct {
+	Guid    string    `json:"guid"`
+	Title   string    `json:"title"`
+	Link    string    `json:"link"`
+	PubDate time.Time `json:"pub_date"`
+	Stored  bool      `json:"stored"`
+	Kept    bool      `json:"kept"`
+	Reason  string    `json:"reason"`
+	Pattern string    `json:"pattern,omitempty"`
+}
+
+type PreviewResponse struct {
+	Kept       int            `json:"kept"`
+	Dropped    int            `json:"dropped"`
+	Items      []ItemResponse `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, httpClient *http.Client, command PreviewCommand) (PreviewResponse, error) {
+	response, err := Preview(ctx, logger, rssRepository, httpClient, command)
+	if err != nil {
+		return PreviewResponse{}, err
+	}
+
+	logger.Info("Message Preview successfully", "source", command.Source, "kept", response.Kept, "dropped", response.Dropped)
+	return response, nil
+}
+
+// Preview evaluates a candidate item filter against a page of the stored items of
+// a feed, and also against the items fetched from its origin when command.Live is
+// set. Nothing is saved or published.
+//
+// Stored items hold their translated text, while the subscribe stage filters the
+// original text. A live item therefore replaces the stored one with the same GUID,
+// so that its verdict is the one the next fetch would reach.
+func Preview(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, httpClient *http.Client, command PreviewCommand) (PreviewResponse, error) {
+	err := validator.Validate(ctx, command)
+	if err != nil {
+		return PreviewResponse{}, err
+	}
+
+	itemFilter, err := rss.NewItemFilterWithExpression(command.ItemFilter.IncludeKeywords, command.ItemFilter.ExcludeKeywords, command.ItemFilter.Expression)
+	if err != nil {
+		return PreviewResponse{}, validation_error.New(map[string]string{
+			"item_filter.expression": err.Error(),
+		})
+	}
+
+	feed, err := rssRepository.FindBySource(ctx, command.Source)
+	if err != nil {
+		return PreviewResponse{}, err
+	}
+
+	if feed.ID == uuid.Nil {
+		return PreviewResponse{}, validation_error.New(map[string]string{
+			"source": "not found source: " + command.Source,
+		})
+	}
+
+	page, err := rssRepository.FindItemsPage(ctx, feed, rss.ItemQuery{Limit: command.Limit, Cursor: command.Cursor})
+	if err != nil {
+		if errors.Is(err, rss.ErrInvalidCursor) {
+			return PreviewResponse{}, validation_error.New(map[string]string{
+				"cursor": err.Error(),
+			})
+		}
+		return PreviewResponse{}, err
+	}
+
+	items := make(map[rss.Guid]rss.Item, len(page.Items))
+	stored := make(map[rss.Guid]bool, len(page.Items))
+	for _, item := range page.Items {
+		items[item.Guid] = item
+		stored[item.Guid] = true
+	}
+
+	if command.Live && command.Cursor == "" {
+		liveFeed, err := fetchLive(ctx, logger, httpClient, feed)
+		if err != nil {
+			return PreviewResponse{}, err
+		}
+		for guid, item := range liveFeed.Items {
+			items[guid] = item
+		}
+	}
+
+	response := PreviewResponse{Items: make([]ItemResponse, 0, len(items)), NextCursor: page.NextCursor}
+	for guid, item := range items {
+		verdict := itemFilter.Explain(item)
+		if verdict.Kept {
+			response.Kept++
+		} else {
+			response.Dropped++
+		}
+		response.Items = append(response.Items, ItemResponse{
+			Guid:    guid.Value,
+			Title:   item.Title,
+			Link:    item.Link,
+			PubDate: item.PubDate,
+			Stored:  stored[guid],
+			Kept:    verdict.Kept,
+			Reason:  verdict.Reason,
+			Pattern: verdict.Pattern,
+		})
+	}
+
+	sort.Slice(response.Items, func(i, j int) bool {
+		a, b := response.Items[i], response.Items[j]
+		if !a.PubDate.Equal(b.PubDate) {
+			return a.PubDate.After(b.PubDate)
+		}
+		return a.Guid < b.Guid
+	})
+
+	return response, nil
+}
+
+// fetchLive fetches the feed like the subscribe function does, but without a filter
+// and cache validators, so that every item the feed serves now is returned.
+func fetchLive(ctx context.Context, logger infrastructure.Logger, httpClient *http.Client, feed rss.Rss) (rss.Rss, error) {
+	repository := subscribeService.NewFeedRepository(httpClient, feed.Link, feed.Language, rss.NewItemFilter(nil, nil))
+	repository.SetSource(feed.Source)
+
+	liveFeed, err := subscribeService.Subscribe(ctx, logger, &repository)
+	if err != nil {
+		return rss.Rss{}, fmt.Errorf("%w: %s: %w", ErrFetchFeed, feed.Link, err)
+	}
+	return liveFeed, nil
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/filter_preview/app_service"
+	apiGatewayResponse "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/response"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// liveFetchTimeout is shorter than the 10 seconds the function may run, so that a
+// slow origin is answered with 502 instead of a timeout.
+const liveFetchTimeout = 8 * time.Second
+
+type requestBody struct {
+	ItemFilter struct {
+		IncludeKeywords []string `json:"include_keywords"`
+		ExcludeKeywords []string `json:"exclude_keywords"`
+		Expression      string   `json:"expression"`
+	} `json:"item_filter"`
+	Live   bool   `json:"live"`
+	Limit  int    `json:"limit"`
+	Cursor string `json:"cursor"`
+}
+
+type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.PreviewCommand) (app_service.PreviewResponse, error)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+	httpClient := &http.Client{Timeout: liveFetchTimeout}
+
+	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
+
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository, httpClient)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository, httpClient *http.Client) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.PreviewCommand) (app_service.PreviewResponse, error) {
+		return app_service.Execute(ctx, logger, rssRepository, httpClient, command)
+	}
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(shared.WithRequestTrace(ctx, request), logger, executer, request)
+	}
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	requestBody := requestBody{}
+	if err := json.Unmarshal([]byte(request.Body), &requestBody); err != nil {
+		logger.Error("Failed", "error", "Invalid JSON body")
+		return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, "Invalid JSON body")
+	}
+
+	cmd := app_service.PreviewCommand{
+		Source: request.PathParameters["source"],
+		Limit:  app_service.DefaultLimit,
+		Cursor: requestBody.Cursor,
+		Live:   requestBody.Live,
+	}
+	if requestBody.Limit != 0 {
+		cmd.Limit = requestBody.Limit
+	}
+	cmd.ItemFilter.IncludeKeywords = requestBody.ItemFilter.IncludeKeywords
+	cmd.ItemFilter.ExcludeKeywords = requestBody.ItemFilter.ExcludeKeywords
+	cmd.ItemFilter.Expression = requestBody.ItemFilter.Expression
+
+	preview, err := executer(ctx, logger, cmd)
+
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		if _, ok := err.(*validation_error.ValidationError); ok {
+			return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, app_service.ErrFetchFeed) {
+			return apiGatewayResponse.ErrorResponse(http.StatusBadGateway, err.Error())
+		} else {
+			return apiGatewayResponse.ErrorResponse(http.StatusInternalServerError, err.Error())
+		}
+	}
+	return apiGatewayResponse.OKResponse(preview)
+}
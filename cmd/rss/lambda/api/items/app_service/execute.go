@@ -0,0 +1,114 @@
+package app_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	OrderAsc     = "asc"
+	OrderDesc    = "desc"
+)
+
+type ListCommand struct {
+	Source string `validate:"required"`
+	Limit  int    `validate:"min=1,max=100"`
+	Cursor string
+	Order  string `validate:"oneof=asc desc"`
+	Since  time.Time
+	Until  time.Time
+	Tags   []string
+}
+
+type ItemResponse struct {
+	Guid        string    `json:"guid"`
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Description string    `json:"description"`
+	Author      string    `json:"author"`
+	PubDate     time.Time `json:"pub_date"`
+	Tags        []string  `json:"tags"`
+}
+
+type ItemsResponse struct {
+	Items      []ItemResponse `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, command ListCommand) (ItemsResponse, error) {
+	items, err := ListItems(ctx, logger, rssRepository, command)
+	if err != nil {
+		return ItemsResponse{}, err
+	}
+
+	logger.Info("Message ListItems successfully", "source", command.Source, "count", len(items.Items))
+	return items, nil
+}
+
+func ListItems(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, command ListCommand) (ItemsResponse, error) {
+	err := validator.Validate(ctx, command)
+	if err != nil {
+		return ItemsResponse{}, err
+	}
+
+	if !command.Since.IsZero() && !command.Until.IsZero() && command.Since.After(command.Until) {
+		return ItemsResponse{}, validation_error.New(map[string]string{
+			"since": "since must be before until",
+		})
+	}
+
+	feed, err := rssRepository.FindBySource(ctx, command.Source)
+	if err != nil {
+		return ItemsResponse{}, err
+	}
+
+	if feed.ID == uuid.Nil {
+		return ItemsResponse{}, validation_error.New(map[string]string{
+			"source": "not found source: " + command.Source,
+		})
+	}
+
+	query := rss.ItemQuery{
+		Since:     command.Since,
+		Until:     command.Until,
+		Tags:      command.Tags,
+		Ascending: command.Order == OrderAsc,
+		Limit:     command.Limit,
+		Cursor:    command.Cursor,
+	}
+	page, err := rssRepository.FindItemsPage(ctx, feed, query)
+	if err != nil {
+		if errors.Is(err, rss.ErrInvalidCursor) {
+			return ItemsResponse{}, validation_error.New(map[string]string{
+				"cursor": err.Error(),
+			})
+		}
+		return ItemsResponse{}, err
+	}
+
+	response := ItemsResponse{
+		Items:      make([]ItemResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, item := range page.Items {
+		response.Items = append(response.Items, ItemResponse{
+			Guid:        item.Guid.Value,
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Author:      item.Author,
+			PubDate:     item.PubDate,
+			Tags:        item.Tags,
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,3 @@
+module github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/items
+
+go 1.22.2
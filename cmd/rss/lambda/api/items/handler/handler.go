@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/items/app_service"
+	apiGatewayResponse "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/response"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.ListCommand) (app_service.ItemsResponse, error)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+
+	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
+
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.ListCommand) (app_service.ItemsResponse, error) {
+		return app_service.Execute(ctx, logger, rssRepository, command)
+	}
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(ctx, logger, executer, request)
+	}
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	command, err := buildCommand(request)
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := executer(ctx, logger, command)
+
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		if _, ok := err.(*validation_error.ValidationError); ok {
+			return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error())
+		} else {
+			return apiGatewayResponse.ErrorResponse(http.StatusInternalServerError, err.Error())
+		}
+	}
+	return apiGatewayResponse.OKResponse(items)
+}
+
+func buildCommand(request events.APIGatewayProxyRequest) (app_service.ListCommand, error) {
+	query := request.QueryStringParameters
+	errMap := make(map[string]string)
+
+	command := app_service.ListCommand{
+		Source: request.PathParameters["source"],
+		Limit:  app_service.DefaultLimit,
+		Cursor: query["cursor"],
+		Order:  app_service.OrderDesc,
+		Tags:   request.MultiValueQueryStringParameters["tag"],
+	}
+
+	if command.Tags == nil && query["tag"] != "" {
+		command.Tags = []string{query["tag"]}
+	}
+	if order := query["order"]; order != "" {
+		command.Order = order
+	}
+	if limit := query["limit"]; limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			errMap["limit"] = "limit must be an integer"
+		}
+		command.Limit = value
+	}
+	if since := query["since"]; since != "" {
+		value, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			errMap["since"] = "since must be RFC3339 format"
+		}
+		command.Since = value
+	}
+	if until := query["until"]; until != "" {
+		value, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			errMap["until"] = "until must be RFC3339 format"
+		}
+		command.Until = value
+	}
+
+	if len(errMap) > 0 {
+		return app_service.ListCommand{}, validation_error.New(errMap)
+	}
+	return command, nil
+}
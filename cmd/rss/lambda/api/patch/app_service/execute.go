@@ -5,6 +5,7 @@ import (
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
@@ -12,13 +13,23 @@ import (
 	"github.com/google/uuid"
 )
 
+var patchUser = metadata.UserMeta{ID: "api", Name: "patch-api"}
+
 type PatchCommand struct {
 	Source             string `validate:"required"`
 	SourceLanguageCode string `validate:"required,oneof=af sq am ar hy az bn bs bg ca zh zh-TW hr cs da fa-AF nl en et fa tl fi fr fr-CA ka de el gu ht ha he hi hu is id ga it ja kn kk ko lv lt mk ms ml mt mr mn no ps pl pt pt-PT pa ro ru sr si sk sl so es es-MX sw sv ta te th tr uk ur uz vi cy"`
 	ItemFilter         struct {
-		IncludeKeywords []string
-		ExcludeKeywords []string
+		IncludeKeywords []string `validate:"dive,keyword"`
+		ExcludeKeywords []string `validate:"dive,keyword"`
+		Expression      string
 	}
+	// Retention is left unchanged when nil.
+	Retention *RetentionSetting
+}
+
+type RetentionSetting struct {
+	MaxAgeDays int `validate:"min=0,max=3650"`
+	MaxItems   int `validate:"min=0,max=10000"`
 }
 
 func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, publisher publisher.SubscribeMessagePublisher, command PatchCommand) error {
@@ -49,10 +60,33 @@ func Update(ctx context.Context, logger infrastructure.Logger, rssRepository rss
 		})
 	}
 
+	itemFilter, err := rss.NewItemFilterWithExpression(command.ItemFilter.IncludeKeywords, command.ItemFilter.ExcludeKeywords, command.ItemFilter.Expression)
+	if err != nil {
+		return validation_error.New(map[string]string{
+			"item_filter.expression": err.Error(),
+		})
+	}
+
+	retention := feed.Retention
+	if command.Retention != nil {
+		retention = rss.NewRetention(command.Retention.MaxAgeDays, command.Retention.MaxItems)
+	}
+
+	// The write stage keeps the stored settings, so they are saved here rather than
+	// carried to the row by the fetch the message below starts.
+	feed.SetLanguage(command.SourceLanguageCode)
+	feed.ItemFilter = itemFilter
+	feed.SetRetention(retention)
+	if _, err := rssRepository.Save(ctx, feed, patchUser); err != nil {
+		return err
+	}
+
 	message := message.Subscribe{
+		Source:     feed.Source,
 		FeedURL:    feed.Link,
 		Language:   command.SourceLanguageCode,
-		ItemFilter: rss.NewItemFilter(command.ItemFilter.IncludeKeywords, command.ItemFilter.ExcludeKeywords),
+		ItemFilter: itemFilter,
+		Retention:  retention,
 	}
 
 	return publisher.Publish(ctx, message)
@@ -22,29 +22,43 @@ type requestBody struct {
 	source             string
 	SourceLanguageCode string `json:"source_language_code"`
 	ItemFilter         struct {
-		IncludeKeywords []string `json:"include_keywords"`
-		ExcludeKeywords []string `json:"exclude_keywords"`
+		IncludeKeywords []string `json:"include_keywords" validate:"dive,keyword"`
+		ExcludeKeywords []string `json:"exclude_keywords" validate:"dive,keyword"`
+		Expression      string   `json:"expression"`
 	} `json:"item_filter"`
+	Retention *struct {
+		MaxAgeDays int `json:"max_age_days"`
+		MaxItems   int `json:"max_items"`
+	} `json:"retention"`
 }
 
 type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.PatchCommand) error
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	cfg := awsConfig.LoadConfig(ctx)
 	dynamodbClient := cfg.NewDynamodbClient()
-	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient)
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
 	snsClient := cfg.NewSnsClient()
 	snsTopicClient := awsConfig.NewSnsTopicClient(snsClient, os.Getenv("OUTPUT_TOPIC_RSS_ARN"))
 	publisher := publisher.NewSubscribeMessagePublisher(snsTopicClient)
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
 
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository, publisher)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository, publisher *publisher.SubscribeMessagePublisher) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
 	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.PatchCommand) error {
 		return app_service.Execute(ctx, logger, rssRepository, *publisher, command)
 	}
-	logger.Info("finish")
-	return processRecord(ctx, logger, executer, request), nil
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(shared.WithRequestTrace(ctx, request), logger, executer, request)
+	}
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
@@ -59,13 +73,21 @@ func processRecord(ctx context.Context, logger infrastructure.Logger, executer e
 		Source:             source,
 		SourceLanguageCode: requestBody.SourceLanguageCode,
 		ItemFilter: struct {
-			IncludeKeywords []string
-			ExcludeKeywords []string
+			IncludeKeywords []string `validate:"dive,keyword"`
+			ExcludeKeywords []string `validate:"dive,keyword"`
+			Expression      string
 		}{
 			IncludeKeywords: requestBody.ItemFilter.IncludeKeywords,
 			ExcludeKeywords: requestBody.ItemFilter.ExcludeKeywords,
+			Expression:      requestBody.ItemFilter.Expression,
 		},
 	}
+	if requestBody.Retention != nil {
+		cmd.Retention = &app_service.RetentionSetting{
+			MaxAgeDays: requestBody.Retention.MaxAgeDays,
+			MaxItems:   requestBody.Retention.MaxItems,
+		}
+	}
 
 	err := executer(ctx, logger, cmd)
 
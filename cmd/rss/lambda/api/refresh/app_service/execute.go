@@ -0,0 +1,82 @@
+package app_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/google/uuid"
+)
+
+var ErrTooManyRequests = errors.New("too many refresh requests")
+
+type RefreshCommand struct {
+	Source string `validate:"required"`
+}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, publisher publisher.SubscribeMessagePublisher, minInterval time.Duration, command RefreshCommand) error {
+	err := Refresh(ctx, logger, rssRepository, publisher, minInterval, time.Now().UTC(), command)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Message Refresh successfully", "source", command.Source)
+	return nil
+}
+
+// Refresh publishes a subscribe message for a single feed without waiting for the trigger.
+// A feed can be refreshed at most once per minInterval so that the endpoint cannot be
+// used to hammer the origin site.
+func Refresh(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, publisher publisher.SubscribeMessagePublisher, minInterval time.Duration, now time.Time, command RefreshCommand) error {
+	err := validator.Validate(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	feed, err := rssRepository.FindBySource(ctx, command.Source)
+	if err != nil {
+		return err
+	}
+
+	if feed.ID == uuid.Nil {
+		return validation_error.New(map[string]string{
+			"source": "not found source: " + command.Source,
+		})
+	}
+	if feed.IsTrashed() {
+		return validation_error.New(map[string]string{
+			"source": "the feed is in the trash, restore it first: " + command.Source,
+		})
+	}
+	if feed.IsPaused() {
+		return validation_error.New(map[string]string{
+			"source": "the feed is paused, resume it first: " + command.Source,
+		})
+	}
+
+	reserved, err := rssRepository.ReserveRefresh(ctx, feed, now, minInterval)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return fmt.Errorf("%w: %s can be refreshed once every %s", ErrTooManyRequests, feed.Source, minInterval)
+	}
+
+	message := message.Subscribe{
+		Source:       feed.Source,
+		FeedURL:      feed.Link,
+		Language:     feed.Language,
+		ItemFilter:   feed.ItemFilter,
+		Retention:    feed.Retention,
+		ETag:         feed.ETag,
+		LastModified: feed.LastModified,
+	}
+	return publisher.Publish(ctx, message)
+}
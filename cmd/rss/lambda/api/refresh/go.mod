@@ -0,0 +1,3 @@
+module github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/refresh
+
+go 1.22.2
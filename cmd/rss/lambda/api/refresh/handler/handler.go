@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/refresh/app_service"
+	apiGatewayResponse "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/response"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const defaultRefreshMinIntervalMinutes = 5
+
+type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.RefreshCommand) error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+	snsClient := cfg.NewSnsClient()
+	snsTopicClient := awsConfig.NewSnsTopicClient(snsClient, os.Getenv("OUTPUT_TOPIC_RSS_ARN"))
+	publisher := publisher.NewSubscribeMessagePublisher(snsTopicClient)
+
+	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
+
+	minIntervalMinutes, err := strconv.Atoi(os.Getenv("REFRESH_MIN_INTERVAL_MINUTES"))
+	if err != nil {
+		minIntervalMinutes = defaultRefreshMinIntervalMinutes
+	}
+	minInterval := time.Duration(minIntervalMinutes) * time.Minute
+
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository, publisher, minInterval)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository, publisher *publisher.SubscribeMessagePublisher, minInterval time.Duration) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.RefreshCommand) error {
+		return app_service.Execute(ctx, logger, rssRepository, *publisher, minInterval, command)
+	}
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(shared.WithRequestTrace(ctx, request), logger, executer, request)
+	}
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	cmd := app_service.RefreshCommand{
+		Source: request.PathParameters["source"],
+	}
+
+	err := executer(ctx, logger, cmd)
+
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		if _, ok := err.(*validation_error.ValidationError); ok {
+			return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error())
+		} else if errors.Is(err, app_service.ErrTooManyRequests) {
+			return apiGatewayResponse.ErrorResponse(http.StatusTooManyRequests, err.Error())
+		} else {
+			return apiGatewayResponse.ErrorResponse(http.StatusInternalServerError, err.Error())
+		}
+	}
+	return apiGatewayResponse.NoContentResponse()
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/refresh/handler"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func main() {
+	lambda.Start(handler.Handler)
+}
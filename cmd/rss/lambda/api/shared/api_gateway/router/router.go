@@ -0,0 +1,181 @@
+package router
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	apiGatewayResponse "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/response"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+)
+
+// ProxyHandler handles one API Gateway proxy request, like the processRecord of an API function.
+type ProxyHandler func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse
+
+type route struct {
+	method   string
+	resource string
+	segments []string
+	handler  ProxyHandler
+}
+
+// Router serves ProxyHandlers over net/http. It translates each http.Request into
+// the APIGatewayProxyRequest API Gateway would have sent for the matching resource,
+// and writes the returned APIGatewayProxyResponse back.
+type Router struct {
+	logger *slog.Logger
+	routes []route
+}
+
+func New(logger *slog.Logger) *Router {
+	return &Router{logger: logger}
+}
+
+// Handle registers handler for method on resource, which is written the way API
+// Gateway writes it, e.g. "/api/v1/rss/{source}/items". A {name} segment matches
+// any single path segment and is passed as a path parameter.
+func (r *Router) Handle(method string, resource string, handler ProxyHandler) {
+	r.routes = append(r.routes, route{
+		method:   method,
+		resource: resource,
+		segments: splitPath(resource),
+		handler:  handler,
+	})
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	segments := splitPath(req.URL.EscapedPath())
+
+	matched, pathParameters, methodAllowed := r.match(req.Method, segments)
+	if matched == nil {
+		switch {
+		case req.Method == http.MethodOptions && methodAllowed:
+			writeResponse(w, apiGatewayResponse.NoContentResponse())
+		case methodAllowed:
+			writeResponse(w, apiGatewayResponse.ErrorResponse(http.StatusMethodNotAllowed, "Method not allowed"))
+		default:
+			writeResponse(w, apiGatewayResponse.ErrorResponse(http.StatusNotFound, "Not found"))
+		}
+		return
+	}
+
+	request, err := newProxyRequest(req, matched.resource, pathParameters)
+	if err != nil {
+		writeResponse(w, apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	logger := r.logger.With("requestID", request.RequestContext.RequestID)
+	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
+	writeResponse(w, matched.handler(req.Context(), logger, request))
+}
+
+// match returns the route for method and segments. Like API Gateway, a literal
+// segment takes precedence over a path parameter, so /api/v1/rss/trash is not
+// taken for a source. methodAllowed reports whether the path exists at all.
+func (r *Router) match(method string, segments []string) (matched *route, pathParameters map[string]string, methodAllowed bool) {
+	bestLiterals := -1
+	for i := range r.routes {
+		candidate := &r.routes[i]
+		parameters, literals, ok := matchSegments(candidate.segments, segments)
+		if !ok {
+			continue
+		}
+		methodAllowed = true
+		if candidate.method != method || literals <= bestLiterals {
+			continue
+		}
+		matched, pathParameters, bestLiterals = candidate, parameters, literals
+	}
+	return matched, pathParameters, methodAllowed
+}
+
+func matchSegments(resource []string, path []string) (pathParameters map[string]string, literals int, ok bool) {
+	if len(resource) != len(path) {
+		return nil, 0, false
+	}
+
+	pathParameters = map[string]string{}
+	for i, segment := range resource {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			value, err := url.PathUnescape(path[i])
+			if err != nil || value == "" {
+				return nil, 0, false
+			}
+			pathParameters[segment[1:len(segment)-1]] = value
+			continue
+		}
+		if segment != path[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+	return pathParameters, literals, true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func newProxyRequest(req *http.Request, resource string, pathParameters map[string]string) (events.APIGatewayProxyRequest, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return events.APIGatewayProxyRequest{}, err
+	}
+
+	headers := map[string]string{}
+	for name, values := range req.Header {
+		headers[name] = values[len(values)-1]
+	}
+
+	query := req.URL.Query()
+	queryStringParameters := map[string]string{}
+	for name, values := range query {
+		queryStringParameters[name] = values[len(values)-1]
+	}
+
+	return events.APIGatewayProxyRequest{
+		Resource:                        resource,
+		Path:                            req.URL.Path,
+		HTTPMethod:                      req.Method,
+		Headers:                         headers,
+		MultiValueHeaders:               req.Header,
+		QueryStringParameters:           queryStringParameters,
+		MultiValueQueryStringParameters: query,
+		PathParameters:                  pathParameters,
+		Body:                            string(body),
+		RequestContext: events.APIGatewayProxyRequestContext{
+			RequestID:  uuid.NewString(),
+			Path:       req.URL.Path,
+			HTTPMethod: req.Method,
+		},
+	}, nil
+}
+
+func writeResponse(w http.ResponseWriter, response events.APIGatewayProxyResponse) {
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+	for name, values := range response.MultiValueHeaders {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.WriteHeader(response.StatusCode)
+
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err == nil {
+			body = decoded
+		}
+	}
+	w.Write(body)
+}
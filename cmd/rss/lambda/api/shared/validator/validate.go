@@ -6,11 +6,18 @@ import (
 	"strings"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/go-playground/validator"
 )
 
+// Validate validates s with the validate tags of its fields. Besides the built-in
+// tags, "keyword" checks an item filter keyword with rss.CompileKeyword.
 func Validate(ctx context.Context, s interface{}) error {
 	validate := validator.New()
+	validate.RegisterValidation("keyword", func(fl validator.FieldLevel) bool {
+		_, err := rss.CompileKeyword(fl.Field().String())
+		return err == nil
+	})
 	errMap := make(map[string]string)
 
 	if err := validate.StructCtx(ctx, s); err != nil {
@@ -25,6 +32,9 @@ func Validate(ctx context.Context, s interface{}) error {
 				values = fmt.Sprintf("value must be %s %s", tag, param)
 			case "oneof":
 				values = fmt.Sprintf("value must be one of [%s]", strings.ReplaceAll(param, " ", ", "))
+			case "keyword":
+				_, keywordErr := rss.CompileKeyword(fmt.Sprint(err.Value()))
+				values = keywordErr.Error()
 			default:
 				if param == "" {
 					values = "invalid value"
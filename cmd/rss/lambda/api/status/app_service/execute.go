@@ -0,0 +1,85 @@
+package app_service
+
+import (
+	"context"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
+)
+
+const (
+	ActionPause   = "pause"
+	ActionResume  = "resume"
+	ActionRestore = "restore"
+)
+
+var statusUser = metadata.UserMeta{ID: "api", Name: "status-api"}
+
+type StatusCommand struct {
+	Source string `validate:"required"`
+	Action string `validate:"required,oneof=pause resume restore"`
+}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, command StatusCommand) error {
+	err := ChangeStatus(ctx, logger, rssRepository, command)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Message ChangeStatus successfully", "source", command.Source, "action", command.Action)
+	return nil
+}
+
+func ChangeStatus(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, command StatusCommand) error {
+	err := validator.Validate(ctx, command)
+	if err != nil {
+		return err
+	}
+
+	feed, err := rssRepository.FindBySource(ctx, command.Source)
+	if err != nil {
+		return err
+	}
+
+	if feed.ID == uuid.Nil {
+		return validation_error.New(map[string]string{
+			"source": "not found source: " + command.Source,
+		})
+	}
+
+	if command.Action == ActionRestore {
+		if !feed.IsTrashed() {
+			logger.Info("The feed is not in the trash", "source", feed.Source)
+			return nil
+		}
+		feed.Restore()
+		_, err = rssRepository.Save(ctx, feed, statusUser)
+		return err
+	}
+
+	if feed.IsTrashed() {
+		return validation_error.New(map[string]string{
+			"source": "the feed is in the trash, restore it first: " + command.Source,
+		})
+	}
+
+	before := feed.Status
+	switch command.Action {
+	case ActionPause:
+		feed.Pause()
+	case ActionResume:
+		feed.Resume()
+	}
+
+	if feed.Status == before {
+		logger.Info("The feed already has the requested status", "source", feed.Source, "status", feed.Status)
+		return nil
+	}
+
+	_, err = rssRepository.Save(ctx, feed, statusUser)
+	return err
+}
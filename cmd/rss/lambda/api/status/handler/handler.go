@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	apiGatewayResponse "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/response"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/status/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type executer func(ctx context.Context, logger infrastructure.Logger, command app_service.StatusCommand) error
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+
+	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
+
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	executer := func(ctx context.Context, logger infrastructure.Logger, command app_service.StatusCommand) error {
+		return app_service.Execute(ctx, logger, rssRepository, command)
+	}
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(ctx, logger, executer, request)
+	}
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	command, err := buildCommand(request)
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error())
+	}
+
+	err = executer(ctx, logger, command)
+
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		if _, ok := err.(*validation_error.ValidationError); ok {
+			return apiGatewayResponse.ErrorResponse(http.StatusBadRequest, err.Error())
+		} else {
+			return apiGatewayResponse.ErrorResponse(http.StatusInternalServerError, err.Error())
+		}
+	}
+	return apiGatewayResponse.NoContentResponse()
+}
+
+// buildCommand splits the custom method of POST /api/v1/rss/{source}:pause,
+// {source}:resume and {source}:restore. API Gateway passes the whole segment as
+// the source parameter.
+func buildCommand(request events.APIGatewayProxyRequest) (app_service.StatusCommand, error) {
+	pathParameter := request.PathParameters["source"]
+
+	index := strings.LastIndex(pathParameter, ":")
+	if index < 0 {
+		return app_service.StatusCommand{}, validation_error.New(map[string]string{
+			"action": "action must be specified as {source}:pause, {source}:resume or {source}:restore",
+		})
+	}
+
+	return app_service.StatusCommand{
+		Source: pathParameter[:index],
+		Action: pathParameter[index+1:],
+	}, nil
+}
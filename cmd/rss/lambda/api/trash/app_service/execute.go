@@ -0,0 +1,58 @@
+package app_service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
+)
+
+type TrashedRssResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Source    string    `json:"source"`
+	Title     string    `json:"title"`
+	Link      string    `json:"link"`
+	TrashedAt time.Time `json:"trashedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository) ([]TrashedRssResponse, error) {
+	rssFeeds, err := TrashedRssFeeds(ctx, logger, rssRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Message TrashedRssFeeds successfully", "count", len(rssFeeds))
+	return rssFeeds, nil
+}
+
+// TrashedRssFeeds returns the feeds in the trash, the ones purged soonest first.
+func TrashedRssFeeds(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository) ([]TrashedRssResponse, error) {
+	rssFeeds, err := rssRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []TrashedRssResponse{}
+	for _, feed := range rssFeeds {
+		if !feed.IsTrashed() {
+			continue
+		}
+		response = append(response, TrashedRssResponse{
+			ID:        feed.ID,
+			Source:    feed.Source,
+			Title:     feed.Title,
+			Link:      feed.Link,
+			TrashedAt: feed.TrashedAt,
+			PurgeAt:   feed.PurgeAt,
+		})
+	}
+
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].PurgeAt.Before(response[j].PurgeAt)
+	})
+	return response, nil
+}
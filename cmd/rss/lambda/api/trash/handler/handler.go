@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+
+	apiGatewayResponse "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/response"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/trash/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type executer func(ctx context.Context, logger infrastructure.Logger) ([]app_service.TrashedRssResponse, error)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+
+	logger.Info("APIGatewayProxyRequest Event", "event", shared.APIGatewayProxyRequestToJson(request))
+
+	logger.Info("finish")
+	return NewProxyHandler(rssRepository)(ctx, logger, request), nil
+}
+
+// NewProxyHandler returns the handler of the function with its dependencies given,
+// so that it can also be served outside API Gateway.
+func NewProxyHandler(rssRepository rss.IRssRepository) func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	executer := func(ctx context.Context, logger infrastructure.Logger) ([]app_service.TrashedRssResponse, error) {
+		return app_service.Execute(ctx, logger, rssRepository)
+	}
+	return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+		return processRecord(ctx, logger, executer, request)
+	}
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, _ events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+	rssFeeds, err := executer(ctx, logger)
+
+	if err != nil {
+		logger.Error("Failed", "error", err)
+		return apiGatewayResponse.ErrorResponse(http.StatusInternalServerError, err.Error())
+	}
+	return apiGatewayResponse.OKResponse(rssFeeds)
+}
@@ -2,6 +2,8 @@ package app_service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
@@ -23,16 +25,26 @@ func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rs
 }
 
 func Clean(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, rssEntry rss.Rss) (cleansingRss rss.Rss, err error) {
-	exists, existingRss := rss.Exists(ctx, rssRepository, rssEntry)
+	now := time.Now().UTC()
+	if removed := rssEntry.ApplyRetention(now); removed > 0 {
+		logger.Info("Items out of the retention will not be added", "source", rssEntry.Source, "count", removed)
+	}
+
+	exists, existingRss, err := rss.Exists(ctx, rssRepository, rssEntry)
+	if err != nil {
+		return rss.Rss{}, err
+	}
 	logger.Info("Checking existence of RSS entry", "exists", exists, "source", rssEntry.Source)
 
 	if exists == false {
 		return rssEntry, nil
 	}
 
-	existingRss.SetLanguage(rssEntry.Language)
+	// The settings of the feed are saved by the API, so the stored ones are passed on
+	// and only the fetched data is taken from rssEntry, as write does.
 	existingRss.SetLastBuildDate(rssEntry.LastBuildDate)
-	existingRss.SetItemFilter(rssEntry.ItemFilter.IncludeKeywords, rssEntry.ItemFilter.ExcludeKeywords)
+	existingRss.SetCacheValidators(rssEntry.ETag, rssEntry.LastModified)
+	existingRss.Parts = rssEntry.Parts
 	for _, item := range rssEntry.Items {
 		existingRss.AddOrUpdateItem(item)
 	}
@@ -43,12 +55,19 @@ func Clean(ctx context.Context, logger infrastructure.Logger, rssRepository rss.
 	for key, item := range rssEntry.Items {
 		findItem, err := rss.GetItem(ctx, rssRepository, rssEntry, key)
 		if err != nil {
-			logger.Error("Error retrieving item", "error", err, "source", rssEntry.Source, "guid", key)
+			return rss.Rss{}, fmt.Errorf("failed to retrieve the item %s: %w", key.Value, err)
+		}
+
+		storedItem, found := findItem.Items[key]
+		if !found {
+			cleansingRss.Items[key] = item
 			continue
 		}
 
-		if len(findItem.Items) == 0 {
+		if item.HasChangedFrom(storedItem) {
+			item.Revise(storedItem, now)
 			cleansingRss.Items[key] = item
+			logger.Info("Item content has changed and will be updated", "source", rssEntry.Source, "guid", key, "revisions", len(item.Revisions))
 		} else {
 			logger.Info("Item already exists and will not be added", "source", rssEntry.Source, "guid", key)
 		}
@@ -0,0 +1,18 @@
+package app_service
+
+import (
+	"context"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+)
+
+func Execute(ctx context.Context, logger infrastructure.Logger, store deadletter.Store, deadLetter message.DeadLetter) error {
+	if err := store.Save(ctx, deadLetter); err != nil {
+		return err
+	}
+
+	logger.Info("Dead letter stored", "stage", deadLetter.Stage, "failedMessageID", deadLetter.MessageID, "reason", deadLetter.Reason)
+	return nil
+}
@@ -0,0 +1,3 @@
+module github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/dead_letter
+
+go 1.22.2
\ No newline at end of file
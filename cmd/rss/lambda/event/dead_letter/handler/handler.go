@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/dead_letter/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type executer func(ctx context.Context, logger infrastructure.Logger, deadLetter message.DeadLetter) error
+
+// Handler stores the messages published to the dead-letter topic, so that
+// `workdayctl dlq` can inspect and replay them. They are the dead letters the stages
+// publish for permanent failures, and the records Lambda publishes as the OnFailure
+// destination of a stage whose retries ran out. Its own failures are returned for
+// Lambda to retry; there is no dead-letter topic behind this one.
+func Handler(ctx context.Context, event events.SNSEvent) error {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	store := deadletter.NewDynamoDBStore(dynamodbClient, logger)
+
+	logger.Info("SNS Event", "event", shared.SnsEventToJson(event))
+
+	executer := func(ctx context.Context, logger infrastructure.Logger, deadLetter message.DeadLetter) error {
+		return app_service.Execute(ctx, logger, store, deadLetter)
+	}
+
+	var failures error
+	for _, record := range event.Records {
+		recordCtx, recordLogger := shared.WithTrace(ctx, logger.With("messageID", record.SNS.MessageID), "", record.SNS.Message)
+		err := processRecord(recordCtx, recordLogger, executer, record)
+
+		if err != nil {
+			recordLogger.Error("Failed to store the dead letter", "error", err)
+			failures = errors.Join(failures, err)
+		}
+		logger.Info("finish")
+	}
+
+	return failures
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, record events.SNSEventRecord) error {
+	deadLetter, err := getMessage(record)
+	if err != nil {
+		return err
+	}
+	return executer(ctx, logger, deadLetter)
+}
+
+func getMessage(record events.SNSEventRecord) (deadLetter message.DeadLetter, err error) {
+	if deadLetter, ok := shared.DeadLetterFromDestination(record.SNS.Message); ok {
+		return deadLetter, nil
+	}
+	_, err = message.Decode(record.SNS.Message, message.TypeDeadLetter, &deadLetter)
+	return deadLetter, err
+}
@@ -2,31 +2,49 @@ package app_service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
 )
 
-func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, source string) error {
-	err := Delete(ctx, logger, rssRepository, source)
+var deleteUser = metadata.UserMeta{ID: "api", Name: "delete-api"}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, source string, retention time.Duration) error {
+	err := Delete(ctx, logger, rssRepository, source, retention, time.Now().UTC())
 	if err != nil {
 		return err
 	}
 
-	logger.Info("RSS entry deleted successfully", "source", source)
+	logger.Info("RSS entry moved to the trash successfully", "source", source)
 	return nil
 }
 
-func Delete(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, source string) error {
+// Delete moves the feed to the trash. The feed and its items are deleted by the
+// purge job once retention has passed.
+func Delete(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, source string, retention time.Duration, now time.Time) error {
 	rssEntry, err := rssRepository.FindBySource(ctx, source)
 	if err != nil {
 		logger.Error("Failed to retrieve RSS entry", "error", err, "source", source)
 		return err
 	}
 
-	err = rssRepository.Delete(ctx, rssEntry)
+	if rssEntry.ID == uuid.Nil {
+		return errors.New("not found source: " + source)
+	}
+
+	if rssEntry.IsTrashed() {
+		logger.Info("RSS entry is already in the trash", "source", source, "purgeAt", rssEntry.PurgeAt)
+		return nil
+	}
+
+	rssEntry.Trash(now, retention)
+	_, err = rssRepository.Save(ctx, rssEntry, deleteUser)
 	if err != nil {
-		logger.Error("Failed to delete RSS entry", "error", err, "source", source)
+		logger.Error("Failed to move RSS entry to the trash", "error", err, "source", source)
 		return err
 	}
 	return nil
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/delete/app_service"
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
@@ -12,34 +14,49 @@ import (
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/aws/aws-lambda-go/events"
 )
 
+const defaultTrashRetentionDays = 30
+
 type executer func(ctx context.Context, logger infrastructure.Logger, source string) error
 
 func Handler(ctx context.Context, event events.SNSEvent) error {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	cfg := awsConfig.LoadConfig(ctx)
+	snsClient := cfg.NewSnsClient()
+	deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(awsConfig.NewSnsTopicClient(snsClient, os.Getenv("DEAD_LETTER_TOPIC_ARN")))
+	claimCheckStore := cfg.NewClaimCheckStore()
 	dynamodbClient := cfg.NewDynamodbClient()
-	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient)
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("SNS Event", "event", shared.SnsEventToJson(event))
 
+	retentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil {
+		retentionDays = defaultTrashRetentionDays
+	}
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+
 	executer := func(ctx context.Context, logger infrastructure.Logger, source string) error {
-		return app_service.Execute(ctx, logger, rssRepository, source)
+		return app_service.Execute(ctx, logger, rssRepository, source, retention)
 	}
 
+	var failures error
 	for _, record := range event.Records {
-		recordLogger := logger.With("messageID", record.SNS.MessageID)
-		err := processRecord(ctx, recordLogger, executer, record)
+		recordCtx, recordLogger := shared.WithTrace(ctx, logger.With("messageID", record.SNS.MessageID), message.StageDelete, record.SNS.Message)
+		err := processRecord(recordCtx, recordLogger, executer, record)
 
 		if err != nil {
-			recordLogger.Error("Failed", "error", err)
+			err = shared.HandleFailure(recordCtx, recordLogger, *deadLetterPublisher, claimCheckStore, message.StageDelete, record.SNS.MessageID, record.SNS.Message, err)
+			failures = errors.Join(failures, err)
 		}
 		logger.Info("finish")
 	}
 
-	return nil
+	return failures
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, record events.SNSEventRecord) error {
@@ -51,6 +68,6 @@ func processRecord(ctx context.Context, logger infrastructure.Logger, executer e
 }
 
 func getMessage(record events.SNSEventRecord) (receiveMessage message.Delete, err error) {
-	err = json.Unmarshal([]byte(record.SNS.Message), &receiveMessage)
+	_, err = message.Decode(record.SNS.Message, message.TypeDelete, &receiveMessage)
 	return receiveMessage, err
 }
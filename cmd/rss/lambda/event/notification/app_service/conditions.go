@@ -0,0 +1,78 @@
+package app_service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+)
+
+const UpdateTimeThreshold = 30 * time.Minute
+
+// SavedFeed is what the stream tells about a saved rss row.
+type SavedFeed struct {
+	Source    string
+	Inserted  bool
+	CreatedAt time.Time
+	Version   int
+	Parts     *rss.FetchParts
+	// UpdatedBy is the ID of the user the row was saved as.
+	UpdatedBy string
+}
+
+// IsAnnounced reports whether the save of a feed is announced at all. Only the saves
+// of a fetch are, since announcing after a pause, a resume or a change of settings
+// would post the items of the last fetch again. The saves of a fetch in parts are
+// announced once, by the save that writes the last missing part.
+func IsAnnounced(saved SavedFeed) bool {
+	return saved.UpdatedBy == rss.FetchUser(saved.Source).ID && saved.Parts.IsComplete()
+}
+
+// IsNewFeed reports whether a saved rss row is a newly registered feed: it was
+// inserted rather than updated, and not by the source migration, which re-inserts
+// rows with their original CreatedAt. For a fetch in parts, the save that writes the
+// last missing part stands for the insert made by the first one.
+func IsNewFeed(now time.Time, saved SavedFeed) bool {
+	inserted := saved.Inserted
+	if saved.Parts != nil && saved.Parts.CompletedVersion == saved.Version {
+		inserted = saved.Parts.NewFeed
+	}
+	return inserted && now.Sub(saved.CreatedAt) <= UpdateTimeThreshold
+}
+
+// NewRssConditions returns the conditions a saved feed is announced under.
+// isNew tells whether it is a newly registered feed; see IsNewFeed.
+// Updated articles keep their PubDate, so they are only announced when notifyUpdatedItems is set.
+func NewRssConditions(logger infrastructure.Logger, now time.Time, isNew bool, notifyUpdatedItems bool) RssConditions {
+	return RssConditions{
+		Target: func(r rss.Rss) bool {
+			if r.IsPaused() || r.IsTrashed() {
+				logger.Info("The feed is paused or trashed. Skipping processing.", "ID", r.ID, "source", r.Source, "status", r.Status, "trashedAt", r.TrashedAt)
+				return false
+			}
+			if isNew || now.Sub(r.LastBuildDate) <= UpdateTimeThreshold {
+				return true
+			}
+			// A feed may keep its LastBuildDate when an article is revised, so whether
+			// one was revised within the threshold is left to ItemFilter.
+			if notifyUpdatedItems {
+				return true
+			}
+			logger.Info("The LastBuildDate is not within the last update time threshold. Skipping processing.", "ID", r.ID, "UpdateTimeThreshold", UpdateTimeThreshold)
+			return false
+		},
+		ItemFilter: func(item rss.Item) bool {
+			if isNew {
+				return true
+			}
+			result := now.Sub(item.PubDate) <= UpdateTimeThreshold
+			if !result && notifyUpdatedItems && !item.RevisedAt().IsZero() {
+				result = now.Sub(item.RevisedAt()) <= UpdateTimeThreshold
+			}
+			logger.Info(fmt.Sprintf("Checking item with GUID: %s, PubDate: %s, Current time: %s, Update time threshold: %v, Result: %t",
+				item.Guid.Value, item.PubDate.Format(time.RFC3339), now.Format(time.RFC3339), UpdateTimeThreshold, result))
+			return result
+		},
+	}
+}
@@ -85,8 +85,13 @@ func makeMessage(r rss.Rss, itemFilter func(item rss.Item) bool) string {
 	for _, key := range keys {
 		item := filteredItems[rss.Guid{Value: key}]
 		truncatedDescription := truncate(item.Description)
-		messageBuilder.WriteString(fmt.Sprintf("%d. *記事タイトル:* <%s|%s>\n    *公開日:* %s\n    *概要:* %s\n\n",
-			i, item.Link, item.Title, item.PubDate.Format(time.RFC3339), truncatedDescription))
+		if revisedAt := item.RevisedAt(); !revisedAt.IsZero() {
+			messageBuilder.WriteString(fmt.Sprintf("%d. *記事タイトル(更新):* <%s|%s>\n    *公開日:* %s\n    *更新日:* %s\n    *概要:* %s\n\n",
+				i, item.Link, item.Title, item.PubDate.Format(time.RFC3339), revisedAt.Format(time.RFC3339), truncatedDescription))
+		} else {
+			messageBuilder.WriteString(fmt.Sprintf("%d. *記事タイトル:* <%s|%s>\n    *公開日:* %s\n    *概要:* %s\n\n",
+				i, item.Link, item.Title, item.PubDate.Format(time.RFC3339), truncatedDescription))
+		}
 		i++
 	}
 
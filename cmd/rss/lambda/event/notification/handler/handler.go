@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -12,13 +11,13 @@ import (
 	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/slack-go/slack"
 )
 
-const updateTimeThreshold = 30 * time.Minute
-
-type executer func(ctx context.Context, logger infrastructure.Logger, isNew bool, source string) error
+type executer func(ctx context.Context, logger infrastructure.Logger, saved app_service.SavedFeed) error
 
 type slackChannelClient struct {
 	client    *slack.Client
@@ -29,10 +28,17 @@ func (s *slackChannelClient) PostMessageContext(ctx context.Context, text string
 	return s.client.PostMessageContext(ctx, s.channelId, slack.MsgOptionText(text, false), slack.MsgOptionUsername(username))
 }
 
-func Handler(ctx context.Context, event events.DynamoDBEvent) error {
+// Handler reports the records that failed with a retryable error as BatchItemFailures,
+// so that Lambda retries them from the stream; the others go to the dead-letter topic.
+func Handler(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	cfg := awsConfig.LoadConfig(ctx)
+	snsClient := cfg.NewSnsClient()
+	deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(awsConfig.NewSnsTopicClient(snsClient, os.Getenv("DEAD_LETTER_TOPIC_ARN")))
+	claimCheckStore := cfg.NewClaimCheckStore()
 	dynamodbClient := cfg.NewDynamodbClient()
-	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient)
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
 
 	slackClient := slack.New(os.Getenv("SLACK_TOKEN"))
 	slackChannelClient := &slackChannelClient{
@@ -40,45 +46,32 @@ func Handler(ctx context.Context, event events.DynamoDBEvent) error {
 		channelId: os.Getenv("SLACK_CHANNEL_ID"),
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("DynamoDBEvent Event", "event", shared.DynamoDBEventToJson(event))
 
-	executer := func(ctx context.Context, logger infrastructure.Logger, isNew bool, source string) error {
-		now := time.Now()
-		conditions := app_service.RssConditions{
-			Target: func(r rss.Rss) bool {
-				if isNew {
-					return true
-				}
-				shouldProcess := now.Sub(r.LastBuildDate) <= updateTimeThreshold
-				logger.Info("The LastBuildDate is not within the last update time threshold. Skipping processing.", "ID", r.ID, "UpdateTimeThreshold", updateTimeThreshold, "isOutdated", shouldProcess)
-				return shouldProcess
-			},
-			ItemFilter: func(item rss.Item) bool {
-				if isNew {
-					return true
-				}
-				result := now.Sub(item.PubDate) <= updateTimeThreshold
-				logger.Info(fmt.Sprintf("Checking item with GUID: %s, PubDate: %s, Current time: %s, Update time threshold: %v, Result: %t",
-					item.Guid.Value, item.PubDate.Format(time.RFC3339), now.Format(time.RFC3339), updateTimeThreshold, result))
-				return result
-			},
-		}
+	// Updated articles keep their PubDate, so they are only announced when enabled.
+	notifyUpdatedItems := os.Getenv("NOTIFY_UPDATED_ITEMS") == "true"
 
-		return app_service.Execute(ctx, logger, rssRepository, slackChannelClient, conditions, source)
+	executer := func(ctx context.Context, logger infrastructure.Logger, saved app_service.SavedFeed) error {
+		now := time.Now()
+		conditions := app_service.NewRssConditions(logger, now, app_service.IsNewFeed(now, saved), notifyUpdatedItems)
+		return app_service.Execute(ctx, logger, rssRepository, slackChannelClient, conditions, saved.Source)
 	}
 
+	response := events.DynamoDBEventResponse{}
 	for _, record := range event.Records {
-		recordLogger := logger.With("eventID", record.EventID)
-		err := processRecord(ctx, recordLogger, executer, record)
+		recordCtx, recordLogger := shared.WithTrace(ctx, logger.With("eventID", record.EventID), message.StageNotification, "")
+		err := processRecord(recordCtx, recordLogger, executer, record)
 
 		if err != nil {
-			recordLogger.Error("Failed", "error", err)
+			err = shared.HandleFailure(recordCtx, recordLogger, *deadLetterPublisher, claimCheckStore, message.StageNotification, record.EventID, shared.DynamoDBEventRecordToJson(record), err)
+			if err != nil {
+				response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{ItemIdentifier: record.Change.SequenceNumber})
+			}
 		}
 		logger.Info("finish")
 	}
 
-	return nil
+	return response, nil
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, record events.DynamoDBEventRecord) error {
@@ -94,6 +87,70 @@ func processRecord(ctx context.Context, logger infrastructure.Logger, executer e
 		return nil
 	}
 
-	source := record.Change.NewImage["source"].String()
-	return executer(ctx, logger, record.EventName == "INSERT", source)
+	image := record.Change.NewImage
+	saved := app_service.SavedFeed{
+		Source:   image["source"].String(),
+		Inserted: record.EventName == "INSERT",
+		Version:  int(integerAttribute(image, "version")),
+		Parts:    fetchPartsAttribute(image),
+	}
+	if updateBy, ok := image["update_by"]; ok && updateBy.DataType() == events.DataTypeMap {
+		if id, ok := updateBy.Map()["id"]; ok && id.DataType() == events.DataTypeString {
+			saved.UpdatedBy = id.String()
+		}
+	}
+	if unix := integerAttribute(image, "create_at"); unix != 0 {
+		saved.CreatedAt = time.Unix(unix, 0).UTC()
+	}
+
+	if !app_service.IsAnnounced(saved) {
+		logger.Info("The save stores no complete fetch. Skipping processing.", "source", saved.Source, "updatedBy", saved.UpdatedBy, "parts", saved.Parts)
+		return nil
+	}
+	return executer(ctx, logger, saved)
+}
+
+// integerAttribute returns the number attribute name of image, or 0 when it is missing.
+func integerAttribute(image map[string]events.DynamoDBAttributeValue, name string) int64 {
+	value, ok := image[name]
+	if !ok || value.DataType() != events.DataTypeNumber {
+		return 0
+	}
+	integer, err := value.Integer()
+	if err != nil {
+		return 0
+	}
+	return integer
+}
+
+// fetchPartsAttribute returns the FetchParts the write stage stores on a feed fetched
+// in parts, or nil when the feed was not.
+func fetchPartsAttribute(image map[string]events.DynamoDBAttributeValue) *rss.FetchParts {
+	value, ok := image["parts"]
+	if !ok || value.DataType() != events.DataTypeMap {
+		return nil
+	}
+	attributes := value.Map()
+
+	parts := &rss.FetchParts{
+		Parts:            int(integerAttribute(attributes, "parts")),
+		CompletedVersion: int(integerAttribute(attributes, "completed_version")),
+	}
+	if fetchID, ok := attributes["fetch_id"]; ok && fetchID.DataType() == events.DataTypeString {
+		parts.FetchID = fetchID.String()
+	}
+	if newFeed, ok := attributes["new_feed"]; ok && newFeed.DataType() == events.DataTypeBoolean {
+		parts.NewFeed = newFeed.Boolean()
+	}
+	if written, ok := attributes["written"]; ok && written.DataType() == events.DataTypeList {
+		for _, part := range written.List() {
+			if part.DataType() != events.DataTypeNumber {
+				continue
+			}
+			if number, err := part.Integer(); err == nil {
+				parts.Written = append(parts.Written, int(number))
+			}
+		}
+	}
+	return parts
 }
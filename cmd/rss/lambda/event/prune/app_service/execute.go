@@ -0,0 +1,86 @@
+package app_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+)
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository) error {
+	pruned, err := Prune(ctx, logger, rssRepository, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Items pruned successfully", "count", pruned)
+	return nil
+}
+
+// Prune deletes the items that are out of the retention of their feed.
+// MaxItems is enforced only here; items past MaxAgeDays are normally removed by the
+// DynamoDB TTL but are deleted as well, since items saved before the retention was
+// set have no TTL.
+// A failure on one feed does not stop the others; the first error is returned after
+// all feeds have been tried.
+func Prune(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, now time.Time) (int, error) {
+	feeds, err := rssRepository.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	var firstErr error
+	for _, feed := range feeds {
+		if feed.IsTrashed() || feed.Retention == (rss.Retention{}) {
+			continue
+		}
+
+		count, err := pruneFeed(ctx, rssRepository, feed, now)
+		if err != nil {
+			logger.Error("Failed to prune items", "error", err, "source", feed.Source)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if count > 0 {
+			logger.Info("Items pruned", "source", feed.Source, "count", count, "maxAgeDays", feed.Retention.MaxAgeDays, "maxItems", feed.Retention.MaxItems)
+		}
+		pruned += count
+	}
+
+	return pruned, firstErr
+}
+
+func pruneFeed(ctx context.Context, rssRepository rss.IRssRepository, feed rss.Rss, now time.Time) (int, error) {
+	var kept []rss.Item
+	var guids []rss.Guid
+	err := rssRepository.FindItemsPages(ctx, feed, func(items []rss.Item) bool {
+		for _, item := range items {
+			if feed.Retention.IsExpired(item, now) {
+				guids = append(guids, item.Guid)
+				continue
+			}
+			kept = append(kept, item)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range feed.Retention.Overflow(kept) {
+		guids = append(guids, item.Guid)
+	}
+	if len(guids) == 0 {
+		return 0, nil
+	}
+
+	err = rssRepository.DeleteItems(ctx, feed, guids)
+	if err != nil {
+		return 0, err
+	}
+	return len(guids), nil
+}
@@ -0,0 +1,50 @@
+package app_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+)
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository) error {
+	purged, err := Purge(ctx, logger, rssRepository, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Trashed RSS entries purged successfully", "count", purged)
+	return nil
+}
+
+// Purge deletes the trashed feeds whose retention has expired together with their items.
+// A failure on one feed does not stop the others; the first error is returned after all
+// feeds have been tried, so the feed is retried on the next run.
+func Purge(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, now time.Time) (int, error) {
+	feeds, err := rssRepository.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var firstErr error
+	for _, feed := range feeds {
+		if !feed.ShouldPurge(now) {
+			continue
+		}
+
+		err := rssRepository.Delete(ctx, feed)
+		if err != nil {
+			logger.Error("Failed to purge RSS entry", "error", err, "source", feed.Source)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logger.Info("RSS entry purged", "source", feed.Source, "trashedAt", feed.TrashedAt, "purgeAt", feed.PurgeAt)
+		purged++
+	}
+
+	return purged, firstErr
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/purge/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type executer func(ctx context.Context, logger infrastructure.Logger) error
+
+func Handler(ctx context.Context, event events.EventBridgeEvent) error {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil)).With("EventBridgeID", event.ID)
+
+	cfg := awsConfig.LoadConfig(ctx)
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+
+	logger.Info("EventBridgeEvent Event", "event", shared.EventBridgeEventToJson(event))
+
+	executer := func(ctx context.Context, logger infrastructure.Logger) error {
+		return app_service.Execute(ctx, logger, rssRepository)
+	}
+
+	err := processRecord(ctx, logger, event, executer)
+	if err != nil {
+		logger.Error("ProcessRecord function execution failed", "error", err)
+		return err
+	}
+
+	logger.Info("finish")
+	return nil
+}
+
+func processRecord(ctx context.Context, logger infrastructure.Logger, _ events.EventBridgeEvent, executer executer) error {
+	return executer(ctx, logger)
+}
@@ -27,9 +27,12 @@ func (c *AwsConfig) NewDynamodbClient() *dynamodb.Client {
 
 func (c *AwsConfig) NewS3Client() *s3.Client {
 	client := s3.NewFromConfig(c.cfg, func(o *s3.Options) {
-		o.Credentials = credentials.NewStaticCredentialsProvider("8o2RS265xUkhAQPsmpYy", "qpfrBNwoBSs92UtAMtblncGVsvQyrMyylWEjfHRo", "")
-		o.UsePathStyle = true
-		o.BaseEndpoint = aws.String(os.Getenv("S3_ENDPOINT"))
+		// S3_ENDPOINT points to the local S3-compatible storage, which takes its own credentials.
+		if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+			o.Credentials = credentials.NewStaticCredentialsProvider("8o2RS265xUkhAQPsmpYy", "qpfrBNwoBSs92UtAMtblncGVsvQyrMyylWEjfHRo", "")
+			o.UsePathStyle = true
+			o.BaseEndpoint = aws.String(endpoint)
+		}
 	})
 	return client
 }
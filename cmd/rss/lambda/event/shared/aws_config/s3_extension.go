@@ -0,0 +1,17 @@
+package awsConfig
+
+import (
+	"os"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+)
+
+// NewClaimCheckStore returns the store for the Write messages and dead letters too
+// large for SNS, in the bucket given by CLAIM_CHECK_BUCKET, or nil when it is not set.
+func (c *AwsConfig) NewClaimCheckStore() claimcheck.Store {
+	bucket := os.Getenv("CLAIM_CHECK_BUCKET")
+	if bucket == "" {
+		return nil
+	}
+	return claimcheck.NewS3Store(c.NewS3Client(), bucket)
+}
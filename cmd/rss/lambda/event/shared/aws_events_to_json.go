@@ -42,3 +42,12 @@ func APIGatewayProxyRequestToJson(event events.APIGatewayProxyRequest) string {
 	}
 	return string(eventJSON)
 }
+
+func DynamoDBEventRecordToJson(record events.DynamoDBEventRecord) string {
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("Failed to marshal DynamoDB event record: %v", err)
+		return ""
+	}
+	return string(recordJSON)
+}
@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+)
+
+// HandleFailure decides what becomes of a message the stage failed to process with err.
+// A retryable failure is returned, so that Lambda invokes the function with the
+// message again. A permanent one is published to the dead-letter topic with the
+// original message and the reason, and nil is returned unless that publish fails too.
+// A message too large to be dead-lettered with SNS is stored in claimCheckStore and
+// only its claim check is published.
+func HandleFailure(ctx context.Context, logger infrastructure.Logger, deadLetterPublisher publisher.DeadLetterMessagePublisher, claimCheckStore claimcheck.Store, stage string, messageID string, receiveMessage string, err error) error {
+	if infrastructure.IsRetryable(err) {
+		logger.Warn("Failed, the message will be retried", "error", err)
+		return err
+	}
+
+	logger.Error("Failed permanently, sending the message to the dead-letter topic", "error", err)
+	deadLetter := message.DeadLetter{
+		Stage:     stage,
+		MessageID: messageID,
+		Message:   receiveMessage,
+		Reason:    err.Error(),
+		FailedAt:  time.Now().UTC(),
+	}
+	deadLetter, storeErr := message.NewDeadLetterWithClaimCheck(ctx, deadLetter, claimCheckStore)
+	if storeErr != nil {
+		return fmt.Errorf("failed to store the message of the dead letter: %w (reason: %v)", storeErr, err)
+	}
+	if publishErr := deadLetterPublisher.Publish(ctx, deadLetter); publishErr != nil {
+		return fmt.Errorf("failed to publish to the dead-letter topic: %w (reason: %v)", publishErr, err)
+	}
+	return nil
+}
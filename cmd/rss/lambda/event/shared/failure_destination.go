@@ -0,0 +1,88 @@
+package shared
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// stageOfFunction maps the functions whose OnFailure destination is the dead-letter
+// topic to the stage their messages are replayed to.
+var stageOfFunction = map[string]string{
+	"RssSubscribeFunction":    message.StageSubscribe,
+	"RssCleanFunction":        message.StageClean,
+	"RssTranslateFunction":    message.StageTranslate,
+	"RssWriteFunction":        message.StageWrite,
+	"RssDeleteFunction":       message.StageDelete,
+	"RssNotificationFunction": message.StageNotification,
+}
+
+// destinationRecord is what Lambda publishes to an OnFailure destination once an
+// asynchronous invocation or a stream batch has used up its retries.
+type destinationRecord struct {
+	Timestamp      time.Time `json:"timestamp"`
+	RequestContext struct {
+		RequestID   string `json:"requestId"`
+		FunctionArn string `json:"functionArn"`
+		Condition   string `json:"condition"`
+	} `json:"requestContext"`
+	RequestPayload  *events.SNSEvent `json:"requestPayload"`
+	ResponsePayload json.RawMessage  `json:"responsePayload"`
+}
+
+// DeadLetterFromDestination returns the DeadLetter for a record Lambda published to
+// the dead-letter topic after the retries of a function ran out, and false when
+// receiveMessage is not such a record.
+//
+// The record of an SNS invocation holds the message the function failed on, so that
+// it can be replayed. The record of a stream batch only holds the position of the
+// batch in the stream, and is kept as the message as is.
+func DeadLetterFromDestination(receiveMessage string) (message.DeadLetter, bool) {
+	var record destinationRecord
+	if err := json.Unmarshal([]byte(receiveMessage), &record); err != nil || record.RequestContext.Condition == "" {
+		return message.DeadLetter{}, false
+	}
+
+	deadLetter := message.DeadLetter{
+		Stage:     functionStage(record.RequestContext.FunctionArn),
+		MessageID: record.RequestContext.RequestID,
+		Message:   receiveMessage,
+		Reason:    record.RequestContext.Condition,
+		FailedAt:  record.Timestamp.UTC(),
+	}
+	if deadLetter.FailedAt.IsZero() {
+		deadLetter.FailedAt = time.Now().UTC()
+	}
+
+	var response struct {
+		ErrorMessage string `json:"errorMessage"`
+	}
+	if json.Unmarshal(record.ResponsePayload, &response) == nil && response.ErrorMessage != "" {
+		deadLetter.Reason += ": " + response.ErrorMessage
+	}
+
+	if record.RequestPayload != nil && len(record.RequestPayload.Records) > 0 {
+		sns := record.RequestPayload.Records[0].SNS
+		deadLetter.MessageID = sns.MessageID
+		deadLetter.Message = sns.Message
+	}
+	return deadLetter, true
+}
+
+// functionStage returns the stage of the function of functionArn
+// ("arn:aws:lambda:<region>:<account>:function:<name>[:<qualifier>]"), or its name
+// when it is not one of the stages.
+func functionStage(functionArn string) string {
+	parts := strings.Split(functionArn, ":")
+	name := functionArn
+	if len(parts) >= 7 {
+		name = parts[6]
+	}
+	if stage, ok := stageOfFunction[name]; ok {
+		return stage
+	}
+	return name
+}
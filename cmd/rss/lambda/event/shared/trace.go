@@ -0,0 +1,33 @@
+package shared
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+)
+
+// WithTrace returns ctx and logger carrying the correlation ID of receiveMessage,
+// with stage as the origin of the messages published while it is handled.
+// A legacy message without an envelope starts a new correlation ID; one that cannot
+// be read at all does too, and fails later when its payload is decoded.
+func WithTrace(ctx context.Context, logger *slog.Logger, stage string, receiveMessage string) (context.Context, *slog.Logger) {
+	envelope, _ := message.PeekEnvelope(receiveMessage)
+
+	correlationID := envelope.CorrelationID
+	if correlationID == "" {
+		correlationID = uuid.NewString()
+	}
+
+	ctx = message.ContextWithTrace(ctx, message.Trace{CorrelationID: correlationID, Origin: stage})
+	return ctx, logger.With("correlationID", correlationID)
+}
+
+// WithRequestTrace returns ctx whose messages carry the API Gateway request ID as
+// their correlation ID, so that a refresh requested through the API can be followed
+// from the request log.
+func WithRequestTrace(ctx context.Context, request events.APIGatewayProxyRequest) context.Context {
+	return message.ContextWithTrace(ctx, message.Trace{CorrelationID: request.RequestContext.RequestID, Origin: message.OriginAPI})
+}
@@ -2,17 +2,32 @@ package app_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/mmcdole/gofeed"
 )
 
-func Execute(ctx context.Context, logger infrastructure.Logger, feedRepository *FeedRepository, publisher publisher.WriterMessagePublisher) error {
+func Execute(ctx context.Context, logger infrastructure.Logger, feedRepository *FeedRepository, publisher publisher.WriterMessagePublisher, fetchStatusRecorder *FetchStatusRecorder) error {
 	entryRss, err := Subscribe(ctx, logger, feedRepository)
+	if source, sourceErr := feedRepository.Source(); sourceErr == nil {
+		if recordErr := fetchStatusRecorder.Record(ctx, logger, source, err); recordErr != nil {
+			logger.Error("Failed to record fetch status", "source", source, "error", recordErr)
+		}
+	}
+
+	if errors.Is(err, ErrNotModified) {
+		logger.Info("Feed not modified, skipping publish", "feedURL", feedRepository.FeedURL())
+		return nil
+	}
 	if err != nil {
-		return err
+		return classifyFetchError(err)
 	}
 
 	err = publisher.Publish(ctx, entryRss)
@@ -23,17 +38,46 @@ func Execute(ctx context.Context, logger infrastructure.Logger, feedRepository *
 	return nil
 }
 
+// classifyFetchError tells the failures the origin may recover from by the next
+// attempt, such as timeouts, broken connections, 408, 429 and 5xx, from the ones it
+// will not, such as the other 4xx or a feed that cannot be parsed. The former are
+// retried, and counted in the fetch status on every attempt. The latter are
+// dead-lettered at once, as the next trigger fetches the feed again anyway.
+func classifyFetchError(err error) error {
+	var httpErr gofeed.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusRequestTimeout,
+			httpErr.StatusCode == http.StatusTooManyRequests,
+			httpErr.StatusCode >= http.StatusInternalServerError:
+			return infrastructure.Retryable(err)
+		default:
+			return infrastructure.Permanent(err)
+		}
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || infrastructure.IsRetryable(err) {
+		return infrastructure.Retryable(err)
+	}
+	return infrastructure.Permanent(err)
+}
+
 func Subscribe(ctx context.Context, logger infrastructure.Logger, feedRepository *FeedRepository) (rssEntry rss.Rss, err error) {
-	source := getFQDN(feedRepository.FeedURL())
-	if source == "" {
-		return rss.Rss{}, fmt.Errorf("invalid Feed URL: %s", feedRepository.FeedURL())
+	source, err := feedRepository.Source()
+	if err != nil {
+		return rss.Rss{}, fmt.Errorf("invalid Feed URL: %s: %w", feedRepository.FeedURL(), err)
 	}
 
-	feed, err := feedRepository.GetFeed(ctx)
+	response, err := feedRepository.GetFeed(ctx)
+	if errors.Is(err, ErrNotModified) {
+		return rss.Rss{}, err
+	}
 	if err != nil {
 		logger.Error("Failed to retrieve RSS feed", "URL", feedRepository.FeedURL(), "error", err)
 		return rss.Rss{}, err
 	}
+	feed := response.Feed
 
 	lastBuildDate := getLastBuildDate(*feed)
 	rssEntry, err = rss.New(feed.Title, source, feedRepository.FeedURL(), feed.Description, feedRepository.Language(), lastBuildDate.UTC())
@@ -42,6 +86,11 @@ func Subscribe(ctx context.Context, logger infrastructure.Logger, feedRepository
 	}
 
 	rssEntry.SetItemFilter(feedRepository.ItemFilter().IncludeKeywords, feedRepository.ItemFilter().ExcludeKeywords)
+	if err := rssEntry.SetItemFilterExpression(feedRepository.ItemFilter().Expression); err != nil {
+		return rss.Rss{}, fmt.Errorf("invalid item filter expression: %w", err)
+	}
+	rssEntry.SetRetention(feedRepository.Retention())
+	rssEntry.SetCacheValidators(response.ETag, response.LastModified)
 
 	for _, item := range feed.Items {
 		guid, err := getGuid(*item)
@@ -62,6 +111,13 @@ func Subscribe(ctx context.Context, logger infrastructure.Logger, feedRepository
 			logger.Error("Validation error when creating RSS item", "error", err, "item", item.Title)
 			continue
 		}
+		// The categories become the tags the item filter and the items API match on,
+		// so they are added before the item filter is applied.
+		for _, category := range item.Categories {
+			if tag := strings.TrimSpace(category); tag != "" {
+				entryItem.AddTag(tag)
+			}
+		}
 		rssEntry.AddOrUpdateItem(entryItem)
 	}
 
@@ -2,24 +2,64 @@ package app_service
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/mmcdole/gofeed"
 )
 
+// ErrNotModified is returned by GetFeed when the server answered 304 Not Modified
+// to the conditional request.
+var ErrNotModified = errors.New("feed not modified")
+
+// FetchTimeout bounds a fetch well within the 30 seconds the subscribe function may
+// run, so that a hanging origin is recorded as a retryable fetch failure.
+const FetchTimeout = 20 * time.Second
+
 type FeedRepository struct {
-	goParser   *gofeed.Parser
-	feedURL    string
-	language   string
-	itemFilter rss.ItemFilter
+	httpClient   *http.Client
+	goParser     *gofeed.Parser
+	source       string
+	feedURL      string
+	language     string
+	itemFilter   rss.ItemFilter
+	retention    rss.Retention
+	etag         string
+	lastModified string
+}
+
+type FeedResponse struct {
+	Feed         *gofeed.Feed
+	ETag         string
+	LastModified string
 }
 
 func NewFeedRepository(httpClient *http.Client, feedURL, language string, itemFilter rss.ItemFilter) FeedRepository {
 	fp := gofeed.NewParser()
-	fp.Client = httpClient
 
-	return FeedRepository{goParser: fp, feedURL: feedURL, language: language, itemFilter: itemFilter}
+	return FeedRepository{httpClient: httpClient, goParser: fp, feedURL: feedURL, language: language, itemFilter: itemFilter}
+}
+
+// SetSource pins the source of the feed. Feeds that are already stored keep the
+// source they were registered with instead of deriving it from the URL again.
+func (r *FeedRepository) SetSource(source string) {
+	r.source = source
+}
+
+func (r *FeedRepository) Source() (string, error) {
+	if r.source != "" {
+		return r.source, nil
+	}
+	return rss.NewSource(r.feedURL)
+}
+
+// SetCacheValidators sets the ETag and Last-Modified values of the previous fetch.
+// GetFeed sends them as If-None-Match and If-Modified-Since.
+func (r *FeedRepository) SetCacheValidators(etag, lastModified string) {
+	r.etag = etag
+	r.lastModified = lastModified
 }
 
 func (r *FeedRepository) FeedURL() string {
@@ -34,7 +74,48 @@ func (r *FeedRepository) ItemFilter() rss.ItemFilter {
 	return r.itemFilter
 }
 
-func (r *FeedRepository) GetFeed(ctx context.Context) (feed *gofeed.Feed, err error) {
-	fp := r.goParser
-	return fp.ParseURLWithContext(r.feedURL, ctx)
+func (r *FeedRepository) SetRetention(retention rss.Retention) {
+	r.retention = retention
+}
+
+func (r *FeedRepository) Retention() rss.Retention {
+	return r.retention
+}
+
+func (r *FeedRepository) GetFeed(ctx context.Context) (FeedResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.feedURL, nil)
+	if err != nil {
+		return FeedResponse{}, err
+	}
+	req.Header.Set("User-Agent", "Gofeed/1.0")
+	if r.etag != "" {
+		req.Header.Set("If-None-Match", r.etag)
+	}
+	if r.lastModified != "" {
+		req.Header.Set("If-Modified-Since", r.lastModified)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return FeedResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return FeedResponse{}, ErrNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return FeedResponse{}, gofeed.HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	feed, err := r.goParser.Parse(resp.Body)
+	if err != nil {
+		return FeedResponse{}, err
+	}
+
+	return FeedResponse{
+		Feed:         feed,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, nil
 }
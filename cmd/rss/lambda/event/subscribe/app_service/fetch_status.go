@@ -0,0 +1,90 @@
+package app_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
+	"github.com/mmcdole/gofeed"
+)
+
+type SlackSender interface {
+	PostMessageContext(ctx context.Context, text string, username string) (respChannel string, respTimestamp string, err error)
+}
+
+// FetchStatusRecorder keeps the fetch status of stored feeds up to date and alerts
+// Slack once when a feed gets disabled by consecutive failures.
+type FetchStatusRecorder struct {
+	rssRepository          rss.IRssRepository
+	slackSender            SlackSender
+	maxConsecutiveFailures int
+	now                    func() time.Time
+}
+
+func NewFetchStatusRecorder(rssRepository rss.IRssRepository, slackSender SlackSender, maxConsecutiveFailures int) *FetchStatusRecorder {
+	return &FetchStatusRecorder{
+		rssRepository:          rssRepository,
+		slackSender:            slackSender,
+		maxConsecutiveFailures: maxConsecutiveFailures,
+		now:                    time.Now,
+	}
+}
+
+// Record stores the outcome of a fetch of the feed identified by source.
+// Feeds that are not stored yet, e.g. the first fetch of a newly created feed, are ignored.
+func (r *FetchStatusRecorder) Record(ctx context.Context, logger infrastructure.Logger, source string, fetchErr error) error {
+	feed, err := r.rssRepository.FindBySource(ctx, source)
+	if err != nil {
+		return err
+	}
+	if feed.ID == uuid.Nil {
+		return nil
+	}
+
+	status := feed.FetchStatus
+	now := r.now().UTC()
+	disabled := false
+	if fetchErr == nil || errors.Is(fetchErr, ErrNotModified) {
+		status.RecordSuccess(now, httpStatusOf(fetchErr))
+	} else {
+		disabled = status.RecordFailure(now, httpStatusOf(fetchErr), fetchErr, r.maxConsecutiveFailures)
+	}
+
+	err = r.rssRepository.SaveFetchStatus(ctx, feed, status)
+	if err != nil {
+		return err
+	}
+
+	if !disabled {
+		return nil
+	}
+
+	logger.Warn("Feed disabled after consecutive failures", "source", source, "consecutiveFailures", status.ConsecutiveFailures)
+	_, _, err = r.slackSender.PostMessageContext(ctx, makeDisabledMessage(feed, status), source)
+	return err
+}
+
+func httpStatusOf(fetchErr error) int {
+	if fetchErr == nil {
+		return http.StatusOK
+	}
+	if errors.Is(fetchErr, ErrNotModified) {
+		return http.StatusNotModified
+	}
+
+	var httpErr gofeed.HTTPError
+	if errors.As(fetchErr, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+func makeDisabledMessage(feed rss.Rss, status rss.FetchStatus) string {
+	return fmt.Sprintf("*フィードの取得を停止しました:* <%s|%s>\n*連続失敗回数:* %d\n*HTTPステータス:* %d\n*最終エラー:* %s",
+		feed.Link, feed.Title, status.ConsecutiveFailures, status.HTTPStatus, status.LastError)
+}
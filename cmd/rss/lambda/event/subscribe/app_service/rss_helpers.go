@@ -8,14 +8,6 @@ import (
 	"github.com/mmcdole/gofeed"
 )
 
-func getFQDN(uri string) string {
-	parsedURL, err := url.Parse(uri)
-	if err != nil {
-		return ""
-	}
-	return parsedURL.Host
-}
-
 func getLastBuildDate(feed gofeed.Feed) (lastBuildDate time.Time) {
 	for _, item := range feed.Items {
 		if item.PublishedParsed != nil && lastBuildDate.Before(*item.PublishedParsed) {
@@ -2,10 +2,11 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
 	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
@@ -15,36 +16,72 @@ import (
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/slack-go/slack"
 )
 
-type executer func(ctx context.Context, logger infrastructure.Logger, feedURL string, language string, itemFilter rss.ItemFilter) error
+const defaultMaxConsecutiveFailures = 5
+
+type executer func(ctx context.Context, logger infrastructure.Logger, receiveMessage message.Subscribe) error
+
+type slackChannelClient struct {
+	client    *slack.Client
+	channelId string
+}
+
+func (s *slackChannelClient) PostMessageContext(ctx context.Context, text string, username string) (respChannel string, respTimestamp string, err error) {
+	return s.client.PostMessageContext(ctx, s.channelId, slack.MsgOptionText(text, false), slack.MsgOptionUsername(username))
+}
 
 func Handler(ctx context.Context, event events.SNSEvent) error {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
 	cfg := awsConfig.LoadConfig(ctx)
 	snsClient := cfg.NewSnsClient()
+	deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(awsConfig.NewSnsTopicClient(snsClient, os.Getenv("DEAD_LETTER_TOPIC_ARN")))
+
+	claimCheckStore := cfg.NewClaimCheckStore()
 
 	snsTopicClient := awsConfig.NewSnsTopicClient(snsClient, os.Getenv("OUTPUT_TOPIC_RSS_ARN"))
-	publisher := publisher.NewWriterMessagePublisher(snsTopicClient)
+	publisher := publisher.NewWriterMessagePublisherWithClaimCheck(snsTopicClient, claimCheckStore)
+
+	dynamodbClient := cfg.NewDynamodbClient()
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
+
+	slackClient := slack.New(os.Getenv("SLACK_TOKEN"))
+	slackChannelClient := &slackChannelClient{
+		client:    slackClient,
+		channelId: os.Getenv("SLACK_CHANNEL_ID"),
+	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	logger.Info("SNS Event", "event", shared.SnsEventToJson(event))
 
-	httpClient := &http.Client{}
-	executer := func(ctx context.Context, logger infrastructure.Logger, feedURL string, language string, itemFilter rss.ItemFilter) error {
-		repository := app_service.NewFeedRepository(httpClient, feedURL, language, itemFilter)
-		return app_service.Execute(ctx, logger, &repository, *publisher)
+	maxConsecutiveFailures, err := strconv.Atoi(os.Getenv("MAX_CONSECUTIVE_FAILURES"))
+	if err != nil {
+		maxConsecutiveFailures = defaultMaxConsecutiveFailures
+	}
+	fetchStatusRecorder := app_service.NewFetchStatusRecorder(rssRepository, slackChannelClient, maxConsecutiveFailures)
+
+	httpClient := &http.Client{Timeout: app_service.FetchTimeout}
+	executer := func(ctx context.Context, logger infrastructure.Logger, receiveMessage message.Subscribe) error {
+		repository := app_service.NewFeedRepository(httpClient, receiveMessage.FeedURL, receiveMessage.Language, receiveMessage.ItemFilter)
+		repository.SetSource(receiveMessage.Source)
+		repository.SetCacheValidators(receiveMessage.ETag, receiveMessage.LastModified)
+		repository.SetRetention(receiveMessage.Retention)
+		return app_service.Execute(ctx, logger, &repository, *publisher, fetchStatusRecorder)
 	}
 
+	var failures error
 	for _, record := range event.Records {
-		recordLogger := logger.With("messageID", record.SNS.MessageID)
-		err := processRecord(ctx, recordLogger, record, executer)
+		recordCtx, recordLogger := shared.WithTrace(ctx, logger.With("messageID", record.SNS.MessageID), message.StageSubscribe, record.SNS.Message)
+		err := processRecord(recordCtx, recordLogger, record, executer)
 
 		if err != nil {
-			recordLogger.Error("Failed", "error", err)
+			err = shared.HandleFailure(recordCtx, recordLogger, *deadLetterPublisher, claimCheckStore, message.StageSubscribe, record.SNS.MessageID, record.SNS.Message, err)
+			failures = errors.Join(failures, err)
 		}
 	}
 
-	return nil
+	return failures
 }
 
 func processRecord(ctx context.Context, logger infrastructure.Logger, record events.SNSEventRecord, executer executer) error {
@@ -53,10 +90,10 @@ func processRecord(ctx context.Context, logger infrastructure.Logger, record eve
 		return err
 	}
 
-	return executer(ctx, logger, receiveMessage.FeedURL, receiveMessage.Language, receiveMessage.ItemFilter)
+	return executer(ctx, logger, receiveMessage)
 }
 
 func getMessage(record events.SNSEventRecord) (receiveMessage message.Subscribe, err error) {
-	err = json.Unmarshal([]byte(record.SNS.Message), &receiveMessage)
+	_, err = message.Decode(record.SNS.Message, message.TypeSubscribe, &receiveMessage)
 	return receiveMessage, err
 }
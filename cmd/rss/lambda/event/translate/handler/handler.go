@@ -2,7 +2,7 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"os"
 
@@ -11,6 +11,7 @@ import (
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/translate/app_service"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/aws/aws-lambda-go/events"
@@ -20,10 +21,12 @@ type executer func(ctx context.Context, logger infrastructure.Logger, rssEntry r
 
 func Handler(ctx context.Context, event events.SNSEvent) error {
 	cfg := awsConfig.LoadConfig(ctx)
+	claimCheckStore := cfg.NewClaimCheckStore()
 	snsClient := cfg.NewSnsClient()
+	deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(awsConfig.NewSnsTopicClient(snsClient, os.Getenv("DEAD_LETTER_TOPIC_ARN")))
 
 	snsTopicClient := awsConfig.NewSnsTopicClient(snsClient, os.Getenv("OUTPUT_TOPIC_RSS_ARN"))
-	publisher := publisher.NewWriterMessagePublisher(snsTopicClient)
+	publisher := publisher.NewWriterMessagePublisherWithClaimCheck(snsTopicClient, claimCheckStore)
 	easyTranslateClient := awsConfig.NewTranslateClient(os.Getenv("TRANSLATE_URL"))
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -33,42 +36,36 @@ func Handler(ctx context.Context, event events.SNSEvent) error {
 		return app_service.Execute(ctx, logger, easyTranslateClient, *publisher, rssEntry)
 	}
 
+	var failures error
 	for _, record := range event.Records {
-		recordLogger := logger.With("messageID", record.SNS.MessageID)
-		err := processRecord(ctx, recordLogger, executer, record)
+		recordCtx, recordLogger := shared.WithTrace(ctx, logger.With("messageID", record.SNS.MessageID), message.StageTranslate, record.SNS.Message)
+		err := processRecord(recordCtx, recordLogger, executer, claimCheckStore, record)
 
 		if err != nil {
-			recordLogger.Error("Failed", "error", err)
+			err = shared.HandleFailure(recordCtx, recordLogger, *deadLetterPublisher, claimCheckStore, message.StageTranslate, record.SNS.MessageID, record.SNS.Message, err)
+			failures = errors.Join(failures, err)
 		}
 		logger.Info("finish")
 	}
 
-	return nil
+	return failures
 }
 
-func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, record events.SNSEventRecord) error {
-	receiveMessage, err := getMessage(record)
+func processRecord(ctx context.Context, logger infrastructure.Logger, executer executer, claimCheckStore claimcheck.Store, record events.SNSEventRecord) error {
+	receiveMessage, err := getMessage(ctx, record, claimCheckStore)
 	if err != nil {
 		return err
 	}
+	if receiveMessage.Parts > 0 {
+		logger.Info("Processing a part of the feed", "source", receiveMessage.RssFeed.Source, "part", receiveMessage.Part, "parts", receiveMessage.Parts)
+	}
 	return executer(ctx, logger, receiveMessage.RssFeed)
 }
 
-func getMessage(record events.SNSEventRecord) (receiveMessage message.Write, err error) {
-	err = json.Unmarshal([]byte(record.SNS.Message), &receiveMessage)
+func getMessage(ctx context.Context, record events.SNSEventRecord, claimCheckStore claimcheck.Store) (receiveMessage message.Write, err error) {
+	_, err = message.Decode(record.SNS.Message, message.TypeWrite, &receiveMessage)
 	if err != nil {
 		return message.Write{}, err
 	}
-
-	if receiveMessage.Compressed {
-		decompressedRssData, err := message.DecodeAndDecompressData(receiveMessage.Data)
-		if err != nil {
-			return message.Write{}, err
-		}
-		receiveMessage.RssFeed = decompressedRssData
-		receiveMessage.Data = nil
-		receiveMessage.Compressed = false
-	}
-
-	return receiveMessage, nil
+	return message.ResolveWrite(ctx, receiveMessage, claimCheckStore)
 }
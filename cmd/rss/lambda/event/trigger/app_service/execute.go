@@ -21,7 +21,7 @@ func Execute(ctx context.Context, logger infrastructure.Logger, publisher publis
 }
 
 func Trigger(ctx context.Context, logger infrastructure.Logger, publisher publisher.SubscribeMessagePublisher, throttleConfig throttle.Config, rssRepository rss.IRssRepository) error {
-	messages, err := getMessages(ctx, rssRepository)
+	messages, err := getMessages(ctx, logger, rssRepository)
 	if err != nil {
 		return err
 	}
@@ -39,7 +39,7 @@ func Trigger(ctx context.Context, logger infrastructure.Logger, publisher publis
 	return nil
 }
 
-func getMessages(ctx context.Context, rssRepository rss.IRssRepository) ([]message.Subscribe, error) {
+func getMessages(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository) ([]message.Subscribe, error) {
 	feeds, err := rssRepository.FindAll(ctx)
 	if err != nil {
 		return nil, err
@@ -47,10 +47,27 @@ func getMessages(ctx context.Context, rssRepository rss.IRssRepository) ([]messa
 
 	var messages []message.Subscribe
 	for _, feed := range feeds {
+		if feed.IsTrashed() {
+			logger.Info("Skipping trashed feed", "source", feed.Source)
+			continue
+		}
+		if feed.IsPaused() {
+			logger.Info("Skipping paused feed", "source", feed.Source)
+			continue
+		}
+		if feed.FetchStatus.IsDisabled() {
+			logger.Info("Skipping disabled feed", "source", feed.Source, "disabledAt", feed.FetchStatus.DisabledAt, "consecutiveFailures", feed.FetchStatus.ConsecutiveFailures)
+			continue
+		}
+
 		message := message.Subscribe{
-			FeedURL:    feed.Link,
-			Language:   feed.Language,
-			ItemFilter: feed.ItemFilter,
+			Source:       feed.Source,
+			FeedURL:      feed.Link,
+			Language:     feed.Language,
+			ItemFilter:   feed.ItemFilter,
+			Retention:    feed.Retention,
+			ETag:         feed.ETag,
+			LastModified: feed.LastModified,
 		}
 		messages = append(messages, message)
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/trigger/app_service"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/YamazakiNorihito/workday/pkg/throttle"
 	"github.com/aws/aws-lambda-go/events"
@@ -39,12 +40,14 @@ func Handler(ctx context.Context, event events.EventBridgeEvent) error {
 	}
 
 	dynamodbClient := cfg.NewDynamodbClient()
-	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient)
+	rssRepository := rss.NewDynamoDBRssRepository(dynamodbClient, logger)
 
 	executer := func(ctx context.Context, logger infrastructure.Logger) error {
 		return app_service.Execute(ctx, logger, *publisher, throttleConfig, rssRepository)
 	}
 
+	// Every Subscribe message starts the correlation ID of its own feed refresh.
+	ctx = message.ContextWithTrace(ctx, message.Trace{Origin: message.OriginTrigger})
 	err = processRecord(ctx, logger, event, executer)
 	if err != nil {
 		logger.Error("ProcessRecord function execution failed", "error", err)
@@ -2,8 +2,8 @@ package app_service
 
 import (
 	"context"
+	"errors"
 
-	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/internal/infrastructure"
 )
@@ -18,17 +18,88 @@ func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rs
 	return nil
 }
 
+// saveAttempts bounds how many times Write re-reads and merges the stored feed
+// after losing a race with another writer.
+const saveAttempts = 3
+
+// saveAttemptsOf returns saveAttempts, or for a part of a fetch one attempt more
+// than the fetch has parts. The parts are written concurrently and every round of
+// conflicts lets at least one of them through, so the last part still gets to save
+// and has an attempt left over for a save through the API.
+func saveAttemptsOf(rssEntry rss.Rss) int {
+	if rssEntry.Parts == nil {
+		return saveAttempts
+	}
+	return max(saveAttempts, rssEntry.Parts.Parts+1)
+}
+
+// Write saves the fetch rssEntry carries on the stored feed. The save is conditioned
+// on the version Write read, not on the one clean read; see rss.ConflictError.
 func Write(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, rssEntry rss.Rss) (rss.Rss, error) {
-	exists, existingRss := rss.Exists(ctx, rssRepository, rssEntry)
-	logger.Info("Checking existence of RSS entry", "exists", exists, "source", rssEntry.Source)
+	attempts := saveAttemptsOf(rssEntry)
+	for attempt := 1; ; attempt++ {
+		exists, existingRss, err := rss.Exists(ctx, rssRepository, rssEntry)
+		if err != nil {
+			return rss.Rss{}, err
+		}
+		logger.Info("Checking existence of RSS entry", "exists", exists, "source", rssEntry.Source)
+
+		entry := merge(existingRss, exists, rssEntry)
+		if !shouldUpdateRssEntry(existingRss, entry) {
+			logger.Info("RSS entry is up-to-date, no update needed", "source", rssEntry.Source)
+			return existingRss, nil
+		}
+
+		savedRss, err := rssRepository.Save(ctx, entry, rss.FetchUser(rssEntry.Source))
+		var conflict *rss.ConflictError
+		if errors.As(err, &conflict) && attempt < attempts {
+			logger.Warn("RSS entry was updated concurrently, retrying", "source", rssEntry.Source, "version", conflict.Version, "attempt", attempt)
+			continue
+		}
+		return savedRss, err
+	}
+}
+
+// merge applies the fetch rssEntry carries on top of the stored feed. The settings
+// (language, item filter and retention), the status and the trash are changed only
+// through the API and may have been updated while this feed was being processed,
+// so the stored ones win and a message brings only the items, the LastBuildDate and
+// the cache validators of a fetch.
+func merge(existingRss rss.Rss, exists bool, rssEntry rss.Rss) rss.Rss {
+	entry := rssEntry
+	entry.Version = 0
+	if exists {
+		entry = existingRss
+		entry.Items = rssEntry.Items
+		entry.LastBuildDate = rssEntry.LastBuildDate
+		entry.SetCacheValidators(rssEntry.ETag, rssEntry.LastModified)
+		entry.Parts = rssEntry.Parts
+	}
 
-	if !shouldUpdateRssEntry(existingRss, rssEntry) {
-		logger.Info("RSS entry is up-to-date, no update needed", "source", rssEntry.Source)
-		return existingRss, nil
+	if entry.Parts != nil {
+		entry = mergeParts(existingRss, exists, entry)
+	}
+	return entry
+}
+
+// mergeParts adds the part rssEntry carries to the parts of its fetch written so
+// far. Until every part is written the cache validators are left empty, so that a
+// part that fails is fetched again rather than hidden by a 304. The save that writes
+// the last missing part is recorded, for notification to announce the fetch once.
+func mergeParts(existingRss rss.Rss, exists bool, rssEntry rss.Rss) rss.Rss {
+	parts := rssEntry.Parts.Merge(existingRss.Parts)
+	if !exists {
+		parts.NewFeed = true
 	}
 
-	savedRss, err := rssRepository.Save(ctx, rssEntry, metadata.UserMeta{ID: rssEntry.Source, Name: rssEntry.Source})
-	return savedRss, err
+	if !parts.IsComplete() {
+		rssEntry.ETag = ""
+		rssEntry.LastModified = ""
+	} else if parts.CompletedVersion == 0 {
+		parts.CompletedVersion = rssEntry.Version + 1
+	}
+	rssEntry.Parts = parts
+	return rssEntry
 }
 
 func shouldUpdateRssEntry(existingRss rss.Rss, newRss rss.Rss) bool {
@@ -36,7 +107,17 @@ func shouldUpdateRssEntry(existingRss rss.Rss, newRss rss.Rss) bool {
 		return true
 	}
 
-	if !existingRss.ItemFilter.Equal(newRss.ItemFilter) {
+	// New and updated items are forwarded by the clean stage even when the feed
+	// itself reports the same LastBuildDate.
+	if len(newRss.Items) > 0 {
+		return true
+	}
+
+	if existingRss.ETag != newRss.ETag || existingRss.LastModified != newRss.LastModified {
+		return true
+	}
+
+	if !existingRss.Parts.Equal(newRss.Parts) {
 		return true
 	}
 
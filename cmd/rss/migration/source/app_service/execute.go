@@ -0,0 +1,94 @@
+package app_service
+
+import (
+	"context"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+)
+
+var migrationUser = metadata.UserMeta{ID: "migration", Name: "source-migration"}
+
+type Result struct {
+	Migrated int
+	Skipped  int
+}
+
+func Execute(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, dryRun bool) error {
+	result, err := Migrate(ctx, logger, rssRepository, dryRun)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Source migration finished", "migrated", result.Migrated, "skipped", result.Skipped, "dryRun", dryRun)
+	return nil
+}
+
+// Migrate re-keys every stored feed whose source was derived from the FQDN of its
+// feed URL onto the per-feed source returned by rss.NewSource.
+// The rss row, its items and its fetch status are written under the new source
+// before the old partition is deleted, so an interrupted run can simply be executed
+// again, and a feed disabled by its failures stays disabled.
+func Migrate(ctx context.Context, logger infrastructure.Logger, rssRepository rss.IRssRepository, dryRun bool) (Result, error) {
+	feeds, err := rssRepository.FindAll(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{}
+	for _, feed := range feeds {
+		newSource, err := rss.NewSource(feed.Link)
+		if err != nil {
+			logger.Error("Failed to derive source from feed link", "error", err, "source", feed.Source, "link", feed.Link)
+			result.Skipped++
+			continue
+		}
+
+		if newSource == feed.Source {
+			continue
+		}
+
+		exists, existingRss, err := rss.Exists(ctx, rssRepository, rss.Rss{Source: newSource})
+		if err != nil {
+			return result, err
+		}
+		if exists && existingRss.ID != feed.ID {
+			logger.Warn("Another feed is already stored under the new source", "source", feed.Source, "newSource", newSource, "link", feed.Link)
+			result.Skipped++
+			continue
+		}
+
+		logger.Info("Migrating source", "source", feed.Source, "newSource", newSource, "link", feed.Link, "dryRun", dryRun)
+		if dryRun {
+			result.Migrated++
+			continue
+		}
+
+		fullRss, err := rss.GetItems(ctx, rssRepository, feed)
+		if err != nil {
+			return result, err
+		}
+
+		migratedRss := fullRss
+		migratedRss.Source = newSource
+		// The version belongs to the row under the new source, which is usually not there yet.
+		migratedRss.Version = existingRss.Version
+		savedRss, err := rssRepository.Save(ctx, migratedRss, migrationUser)
+		if err != nil {
+			return result, err
+		}
+		if feed.FetchStatus != (rss.FetchStatus{}) {
+			if err := rssRepository.SaveFetchStatus(ctx, savedRss, feed.FetchStatus); err != nil {
+				return result, err
+			}
+		}
+
+		if err := rss.Delete(ctx, rssRepository, fullRss); err != nil {
+			return result, err
+		}
+		result.Migrated++
+	}
+
+	return result, nil
+}
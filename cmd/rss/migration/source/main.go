@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/cmd/rss/migration/source/app_service"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure/repository_config"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "log the planned changes without writing to the repository")
+	flag.Parse()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	rssRepository, closeRepository, err := repository_config.NewRssRepository(ctx, repository_config.FromEnv(), logger, cfg.NewDynamodbClient)
+	if err != nil {
+		logger.Error("Failed to create the rss repository", "error", err)
+		os.Exit(1)
+	}
+	defer closeRepository()
+
+	if err := app_service.Execute(ctx, logger, rssRepository, *dryRun); err != nil {
+		logger.Error("Source migration failed", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	subscribeService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/subscribe/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/workday-server/server"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure/repository_config"
+	"github.com/YamazakiNorihito/workday/pkg/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	newDynamodbClient := func() *dynamodb.Client {
+		cfg := awsConfig.LoadConfig(ctx)
+		return cfg.NewDynamodbClient()
+	}
+	rssRepository, closeRepository, err := repository_config.NewRssRepository(ctx, repository_config.FromEnv(), logger, newDynamodbClient)
+	if err != nil {
+		logger.Error("Failed to open the rss repository", "error", err)
+		os.Exit(1)
+	}
+	defer closeRepository()
+
+	dependencies := server.Dependencies{
+		Logger:        logger,
+		RssRepository: rssRepository,
+		HttpClient:    &http.Client{Timeout: subscribeService.FetchTimeout},
+		Translator:    server.NoopTranslator{},
+		SlackSender:   &server.LogSlackSender{Logger: logger},
+	}
+	if url := os.Getenv("TRANSLATE_URL"); url != "" {
+		dependencies.Translator = awsConfig.NewTranslateClient(url)
+	}
+	if token := os.Getenv("SLACK_TOKEN"); token != "" {
+		dependencies.SlackSender = server.NewSlackChannelClient(token, os.Getenv("SLACK_CHANNEL_ID"))
+	}
+
+	if err := server.New(configFromEnv(), dependencies).Run(ctx); err != nil {
+		logger.Error("workday-server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// configFromEnv reads the same variables as the Lambda functions, plus
+// TRIGGER_INTERVAL (e.g. "1m") to poll the feeds more often than in production
+// and HTTP_ADDR for the REST API ("" turns it off).
+func configFromEnv() server.Config {
+	config := server.DefaultConfig()
+	if batchSize, err := strconv.Atoi(os.Getenv("BATCH_SIZE")); err == nil {
+		config.BatchSize = batchSize
+	}
+	if maxConsecutiveFailures, err := strconv.Atoi(os.Getenv("MAX_CONSECUTIVE_FAILURES")); err == nil {
+		config.MaxConsecutiveFailures = maxConsecutiveFailures
+	}
+	if retentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil {
+		config.TrashRetention = time.Duration(retentionDays) * 24 * time.Hour
+	}
+	if minIntervalMinutes, err := strconv.Atoi(os.Getenv("REFRESH_MIN_INTERVAL_MINUTES")); err == nil {
+		config.RefreshMinInterval = time.Duration(minIntervalMinutes) * time.Minute
+	}
+	if addr, ok := os.LookupEnv("HTTP_ADDR"); ok {
+		config.HttpAddr = addr
+	}
+	if interval, err := time.ParseDuration(os.Getenv("TRIGGER_INTERVAL")); err == nil {
+		config.TriggerSchedule = scheduler.Every(interval)
+	}
+	config.NotifyUpdatedItems = os.Getenv("NOTIFY_UPDATED_ITEMS") == "true"
+	return config
+}
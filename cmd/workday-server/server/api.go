@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+
+	createHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/create/handler"
+	deleteHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/delete/handler"
+	feedIdHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/feed_id/handler"
+	feedsHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/feeds/handler"
+	filterPreviewHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/filter_preview/handler"
+	itemsHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/items/handler"
+	patchHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/patch/handler"
+	refreshHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/refresh/handler"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/router"
+	statusHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/status/handler"
+	trashHandler "github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/trash/handler"
+)
+
+// APIHandler serves the REST API of the API Gateway on the same resources,
+// publishing to the in-memory bus instead of SNS.
+func (s *Server) APIHandler() http.Handler {
+	r := router.New(s.logger)
+
+	r.Handle(http.MethodGet, "/api/v1/rss", feedsHandler.NewProxyHandler(s.rssRepository))
+	r.Handle(http.MethodPost, "/api/v1/rss", createHandler.NewProxyHandler(s.SubscribePublisher()))
+	r.Handle(http.MethodGet, "/api/v1/rss/trash", trashHandler.NewProxyHandler(s.rssRepository))
+	r.Handle(http.MethodGet, "/api/v1/rss/{source}", feedIdHandler.NewProxyHandler(s.rssRepository))
+	r.Handle(http.MethodPatch, "/api/v1/rss/{source}", patchHandler.NewProxyHandler(s.rssRepository, s.SubscribePublisher()))
+	r.Handle(http.MethodDelete, "/api/v1/rss/{source}", deleteHandler.NewProxyHandler(s.DeletePublisher()))
+	r.Handle(http.MethodPost, "/api/v1/rss/{source}", statusHandler.NewProxyHandler(s.rssRepository))
+	r.Handle(http.MethodGet, "/api/v1/rss/{source}/items", itemsHandler.NewProxyHandler(s.rssRepository))
+	r.Handle(http.MethodPost, "/api/v1/rss/{source}/filter:preview", filterPreviewHandler.NewProxyHandler(s.rssRepository, s.httpClient))
+	r.Handle(http.MethodPost, "/api/v1/rss/{source}/refresh", refreshHandler.NewProxyHandler(s.rssRepository, s.SubscribePublisher(), s.config.RefreshMinInterval))
+	return r
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	deadLetterService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/dead_letter/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/bus"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+)
+
+// stage retries handle under the RetryPolicy the way Lambda retries the function of
+// the stage, and sends the message to TopicDeadLetter once it fails permanently or
+// runs out of retries. Like the Lambda handlers, it logs under the correlation ID of
+// the message and passes it on to the messages handle publishes.
+func (s *Server) stage(stage string, handle bus.Handler) bus.Handler {
+	return func(ctx context.Context, _ infrastructure.Logger, receiveMessage string) error {
+		ctx, logger := shared.WithTrace(ctx, s.logger.With("stage", stage, "messageID", bus.MessageID(ctx)), stage, receiveMessage)
+		err := s.config.RetryPolicy.Do(ctx, logger, stage, infrastructure.IsRetryable, func() error {
+			return handle(ctx, logger, receiveMessage)
+		})
+		if err == nil {
+			return nil
+		}
+
+		logger.Error("Failed, sending the message to the dead-letter topic", "error", err)
+		deadLetter := message.DeadLetter{
+			Stage:     stage,
+			MessageID: bus.MessageID(ctx),
+			Message:   receiveMessage,
+			Reason:    err.Error(),
+			FailedAt:  time.Now().UTC(),
+		}
+		deadLetter, err = message.NewDeadLetterWithClaimCheck(ctx, deadLetter, s.claimCheckStore)
+		if err != nil {
+			return err
+		}
+		return publisher.NewDeadLetterMessagePublisher(s.bus.Topic(TopicDeadLetter)).Publish(ctx, deadLetter)
+	}
+}
+
+func (s *Server) deadLetter(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	var deadLetter message.DeadLetter
+	if _, err := message.Decode(receiveMessage, message.TypeDeadLetter, &deadLetter); err != nil {
+		return err
+	}
+
+	return deadLetterService.Execute(ctx, logger, s.deadLetterStore, deadLetter)
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	subscribeService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/subscribe/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/bus"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/YamazakiNorihito/workday/pkg/scheduler"
+)
+
+// Topics of the in-memory bus. They mirror the SNS topics of the deployed pipeline,
+// except TopicStream, which stands in for the DynamoDB stream of the Rss table.
+const (
+	TopicSubscribe = "subscribe"
+	TopicClean     = "clean"
+	TopicTranslate = "translate"
+	TopicWrite     = "write"
+	TopicDelete    = "delete"
+	TopicStream    = "stream"
+	// TopicDeadLetter receives the messages a stage gave up on.
+	TopicDeadLetter = "dead-letter"
+)
+
+type Config struct {
+	BatchSize              int
+	BatchSleep             time.Duration
+	MaxConsecutiveFailures int
+	TrashRetention         time.Duration
+	NotifyUpdatedItems     bool
+	RefreshMinInterval     time.Duration
+	// HttpAddr is the address the REST API listens on. Empty means no API.
+	HttpAddr string
+	// Concurrency is the number of messages each stage handles at once.
+	Concurrency int
+	// RetryPolicy retries the messages a stage fails on with a retryable error,
+	// as Lambda retries the deployed functions.
+	RetryPolicy     infrastructure.RetryPolicy
+	TriggerSchedule scheduler.Schedule
+	PruneSchedule   scheduler.Schedule
+	PurgeSchedule   scheduler.Schedule
+}
+
+// DefaultConfig matches the settings of the deployed Lambda functions and schedules.
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:              1,
+		BatchSleep:             2 * time.Second,
+		MaxConsecutiveFailures: 5,
+		TrashRetention:         30 * 24 * time.Hour,
+		RefreshMinInterval:     5 * time.Minute,
+		HttpAddr:               ":8080",
+		Concurrency:            4,
+		RetryPolicy:            infrastructure.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute},
+		TriggerSchedule:        scheduler.Every(15 * time.Minute),
+		PruneSchedule:          scheduler.DailyAt(18, 30, time.UTC),
+		PurgeSchedule:          scheduler.DailyAt(18, 0, time.UTC),
+	}
+}
+
+type SlackSender interface {
+	PostMessageContext(ctx context.Context, text string, username string) (respChannel string, respTimestamp string, err error)
+}
+
+type Dependencies struct {
+	Logger        *slog.Logger
+	RssRepository rss.IRssRepository
+	HttpClient    *http.Client
+	Translator    shared.Translator
+	SlackSender   SlackSender
+	// DeadLetterStore keeps the messages the stages gave up on. Nil means an in-memory store.
+	DeadLetterStore deadletter.Store
+	// ClaimCheckStore takes the Write messages and dead letters too large for SNS. The bus has no size
+	// limit, so nil, which never offloads, is fine unless the limit is to be exercised.
+	ClaimCheckStore claimcheck.Store
+}
+
+// Server hosts the whole rss pipeline in one process. The stages run the same
+// app_services as the Lambda functions and talk to each other through a bus.Bus
+// instead of SNS; saving an rss row publishes to TopicStream the way the DynamoDB
+// stream invokes the notification function.
+type Server struct {
+	config              Config
+	logger              *slog.Logger
+	bus                 *bus.Bus
+	rssRepository       rss.IRssRepository
+	httpClient          *http.Client
+	translator          shared.Translator
+	slackSender         SlackSender
+	fetchStatusRecorder *subscribeService.FetchStatusRecorder
+	deadLetterStore     deadletter.Store
+	claimCheckStore     claimcheck.Store
+}
+
+func New(config Config, dependencies Dependencies) *Server {
+	messageBus := bus.New(dependencies.Logger)
+	rssRepository := &streamingRssRepository{IRssRepository: dependencies.RssRepository, publisher: messageBus.Topic(TopicStream)}
+
+	deadLetterStore := dependencies.DeadLetterStore
+	if deadLetterStore == nil {
+		deadLetterStore = deadletter.NewInMemoryStore()
+	}
+
+	s := &Server{
+		config:              config,
+		logger:              dependencies.Logger,
+		bus:                 messageBus,
+		rssRepository:       rssRepository,
+		httpClient:          dependencies.HttpClient,
+		translator:          dependencies.Translator,
+		slackSender:         dependencies.SlackSender,
+		fetchStatusRecorder: subscribeService.NewFetchStatusRecorder(rssRepository, dependencies.SlackSender, config.MaxConsecutiveFailures),
+		deadLetterStore:     deadLetterStore,
+		claimCheckStore:     dependencies.ClaimCheckStore,
+	}
+
+	messageBus.Subscribe(TopicSubscribe, config.Concurrency, s.stage(message.StageSubscribe, s.subscribe))
+	messageBus.Subscribe(TopicClean, config.Concurrency, s.stage(message.StageClean, s.clean))
+	messageBus.Subscribe(TopicTranslate, config.Concurrency, s.stage(message.StageTranslate, s.translate))
+	messageBus.Subscribe(TopicWrite, config.Concurrency, s.stage(message.StageWrite, s.write))
+	messageBus.Subscribe(TopicStream, config.Concurrency, s.stage(message.StageNotification, s.notification))
+	messageBus.Subscribe(TopicDelete, config.Concurrency, s.stage(message.StageDelete, s.delete))
+	messageBus.Subscribe(TopicDeadLetter, 1, s.deadLetter)
+	return s
+}
+
+// RssRepository returns the repository the stages use. Saves through it are notified.
+func (s *Server) RssRepository() rss.IRssRepository {
+	return s.rssRepository
+}
+
+// DeadLetterStore returns the store the messages the stages gave up on are kept in.
+func (s *Server) DeadLetterStore() deadletter.Store {
+	return s.deadLetterStore
+}
+
+func (s *Server) SubscribePublisher() *publisher.SubscribeMessagePublisher {
+	return publisher.NewSubscribeMessagePublisher(s.bus.Topic(TopicSubscribe))
+}
+
+func (s *Server) DeletePublisher() *publisher.DeleteMessagePublisher {
+	return publisher.NewDeleteMessagePublisher(s.bus.Topic(TopicDelete))
+}
+
+// Run serves the REST API and runs the scheduled jobs until ctx is done. It then
+// lets the messages already on the bus flow through the pipeline before it returns.
+func (s *Server) Run(ctx context.Context) error {
+	s.logger.Info("workday-server started")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var httpServer *http.Server
+	listenErr := make(chan error, 1)
+	if s.config.HttpAddr != "" {
+		httpServer = &http.Server{Addr: s.config.HttpAddr, Handler: s.APIHandler()}
+		go func() {
+			s.logger.Info("Serving the REST API", "addr", s.config.HttpAddr)
+			if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				listenErr <- err
+				cancel()
+			}
+		}()
+	}
+
+	jobs := scheduler.New(s.logger,
+		scheduler.Job{Name: "trigger", Schedule: s.config.TriggerSchedule, Run: s.Trigger},
+		scheduler.Job{Name: "prune", Schedule: s.config.PruneSchedule, Run: s.Prune},
+		scheduler.Job{Name: "purge", Schedule: s.config.PurgeSchedule, Run: s.Purge},
+	)
+	jobs.Run(ctx)
+
+	if httpServer != nil {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelShutdown()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			s.logger.Warn("Failed to shut down the REST API", "error", err)
+		}
+	}
+
+	s.logger.Info("Draining the pipeline")
+	s.bus.Wait()
+	s.bus.Close()
+	s.logger.Info("workday-server stopped")
+
+	select {
+	case err := <-listenErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until every message on the bus has been handled.
+func (s *Server) Wait() {
+	s.bus.Wait()
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/slack-go/slack"
+)
+
+type SlackChannelClient struct {
+	client    *slack.Client
+	channelId string
+}
+
+func NewSlackChannelClient(token string, channelId string) *SlackChannelClient {
+	return &SlackChannelClient{client: slack.New(token), channelId: channelId}
+}
+
+func (s *SlackChannelClient) PostMessageContext(ctx context.Context, text string, username string) (respChannel string, respTimestamp string, err error) {
+	return s.client.PostMessageContext(ctx, s.channelId, slack.MsgOptionText(text, false), slack.MsgOptionUsername(username))
+}
+
+// LogSlackSender writes the messages to the log instead of Slack, for running without a Slack token.
+type LogSlackSender struct {
+	Logger infrastructure.Logger
+}
+
+func (s *LogSlackSender) PostMessageContext(ctx context.Context, text string, username string) (respChannel string, respTimestamp string, err error) {
+	s.Logger.Info("Slack message", "username", username, "text", text)
+	return "", "", nil
+}
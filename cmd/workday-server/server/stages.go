@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	cleanService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/clean/app_service"
+	deleteService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/delete/app_service"
+	notificationService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/notification/app_service"
+	pruneService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/prune/app_service"
+	purgeService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/purge/app_service"
+	subscribeService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/subscribe/app_service"
+	translateService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/translate/app_service"
+	triggerService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/trigger/app_service"
+	writeService "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/write/app_service"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/YamazakiNorihito/workday/pkg/throttle"
+)
+
+// Trigger publishes a Subscribe message for every active feed, like the trigger function.
+func (s *Server) Trigger(ctx context.Context) error {
+	throttleConfig := throttle.Config{
+		BatchSize: s.config.BatchSize,
+		Sleep:     func() { time.Sleep(s.config.BatchSleep) },
+	}
+	ctx = message.ContextWithTrace(ctx, message.Trace{Origin: message.OriginTrigger})
+	return triggerService.Execute(ctx, s.logger, *s.SubscribePublisher(), throttleConfig, s.rssRepository)
+}
+
+func (s *Server) Prune(ctx context.Context) error {
+	return pruneService.Execute(ctx, s.logger, s.rssRepository)
+}
+
+func (s *Server) Purge(ctx context.Context) error {
+	return purgeService.Execute(ctx, s.logger, s.rssRepository)
+}
+
+func (s *Server) subscribe(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	var subscribeMessage message.Subscribe
+	if _, err := message.Decode(receiveMessage, message.TypeSubscribe, &subscribeMessage); err != nil {
+		return err
+	}
+
+	repository := subscribeService.NewFeedRepository(s.httpClient, subscribeMessage.FeedURL, subscribeMessage.Language, subscribeMessage.ItemFilter)
+	repository.SetSource(subscribeMessage.Source)
+	repository.SetCacheValidators(subscribeMessage.ETag, subscribeMessage.LastModified)
+	repository.SetRetention(subscribeMessage.Retention)
+	return subscribeService.Execute(ctx, logger, &repository, *s.writerPublisher(TopicClean), s.fetchStatusRecorder)
+}
+
+func (s *Server) clean(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	writeMessage, err := s.decodeWrite(ctx, receiveMessage)
+	if err != nil {
+		return err
+	}
+	return cleanService.Execute(ctx, logger, s.rssRepository, *s.writerPublisher(TopicTranslate), writeMessage.RssFeed)
+}
+
+func (s *Server) translate(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	writeMessage, err := s.decodeWrite(ctx, receiveMessage)
+	if err != nil {
+		return err
+	}
+	return translateService.Execute(ctx, logger, s.translator, *s.writerPublisher(TopicWrite), writeMessage.RssFeed)
+}
+
+func (s *Server) write(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	writeMessage, err := s.decodeWrite(ctx, receiveMessage)
+	if err != nil {
+		return err
+	}
+	return writeService.Execute(ctx, logger, s.rssRepository, writeMessage.RssFeed)
+}
+
+func (s *Server) notification(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	var event streamEvent
+	if err := json.Unmarshal([]byte(receiveMessage), &event); err != nil {
+		return err
+	}
+
+	saved := notificationService.SavedFeed{Source: event.Source, Inserted: event.IsNew, CreatedAt: event.CreatedAt, Version: event.Version, Parts: event.Parts, UpdatedBy: event.UpdatedBy}
+	if !notificationService.IsAnnounced(saved) {
+		logger.Info("The save stores no complete fetch. Skipping processing.", "source", saved.Source, "updatedBy", saved.UpdatedBy, "parts", saved.Parts)
+		return nil
+	}
+
+	now := time.Now()
+	conditions := notificationService.NewRssConditions(logger, now, notificationService.IsNewFeed(now, saved), s.config.NotifyUpdatedItems)
+	return notificationService.Execute(ctx, logger, s.rssRepository, s.slackSender, conditions, saved.Source)
+}
+
+func (s *Server) delete(ctx context.Context, logger infrastructure.Logger, receiveMessage string) error {
+	var deleteMessage message.Delete
+	if _, err := message.Decode(receiveMessage, message.TypeDelete, &deleteMessage); err != nil {
+		return err
+	}
+	return deleteService.Execute(ctx, logger, s.rssRepository, deleteMessage.Source, s.config.TrashRetention)
+}
+
+func (s *Server) writerPublisher(topic string) *publisher.WriterMessagePublisher {
+	return publisher.NewWriterMessagePublisherWithClaimCheck(s.bus.Topic(topic), s.claimCheckStore)
+}
+
+func (s *Server) decodeWrite(ctx context.Context, receiveMessage string) (message.Write, error) {
+	var writeMessage message.Write
+	if _, err := message.Decode(receiveMessage, message.TypeWrite, &writeMessage); err != nil {
+		return message.Write{}, err
+	}
+	return message.ResolveWrite(ctx, writeMessage, s.claimCheckStore)
+}
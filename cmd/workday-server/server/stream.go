@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+)
+
+// streamEvent is what the DynamoDB stream tells the notification function about a saved rss row.
+type streamEvent struct {
+	Source    string          `json:"source"`
+	IsNew     bool            `json:"is_new"`
+	CreatedAt time.Time       `json:"created_at"`
+	Version   int             `json:"version"`
+	Parts     *rss.FetchParts `json:"parts,omitempty"`
+	UpdatedBy string          `json:"updated_by"`
+}
+
+// streamingRssRepository publishes a streamEvent for every saved rss row, so the
+// notification stage runs after a write as it does behind the DynamoDB stream.
+type streamingRssRepository struct {
+	rss.IRssRepository
+	publisher publisher.MessagePublisher
+}
+
+func (r *streamingRssRepository) Save(ctx context.Context, rssEntry rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+	saved, err := r.IRssRepository.Save(ctx, rssEntry, updateBy)
+	if err != nil {
+		return saved, err
+	}
+
+	// A row starts at version 1, so that is the INSERT of the stream.
+	event, err := json.Marshal(streamEvent{Source: saved.Source, IsNew: saved.Version == 1, CreatedAt: saved.CreatedAt, Version: saved.Version, Parts: saved.Parts, UpdatedBy: saved.UpdatedBy.ID})
+	if err != nil {
+		return saved, err
+	}
+	return saved, r.publisher.Publish(ctx, string(event))
+}
@@ -0,0 +1,10 @@
+package server
+
+import "context"
+
+// NoopTranslator leaves the text as it is, for running without a translation endpoint.
+type NoopTranslator struct{}
+
+func (NoopTranslator) TranslateText(ctx context.Context, sourceLanguageCode string, targetLanguageCode string, text string) (translatedText string, err error) {
+	return text, nil
+}
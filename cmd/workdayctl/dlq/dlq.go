@@ -0,0 +1,221 @@
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+)
+
+const Usage = `usage: workdayctl dlq <command> [flags]
+
+commands:
+  list    [-stage stage]                          list the dead letters, oldest first
+  show    <message-id>                            print a dead letter and its message
+  replay  [-stage stage] [-dry-run] (-all | <message-id>...)
+                                                  republish to the stage the messages failed in
+  purge   [-stage stage] (-all | <message-id>...) delete dead letters without replaying them
+`
+
+type Dependencies struct {
+	Store           deadletter.Store
+	Publishers      Publishers
+	ClaimCheckStore claimcheck.Store
+	Stdout          io.Writer
+}
+
+// Run runs the dlq command given by args, e.g. ["replay", "-all"].
+func Run(ctx context.Context, args []string, dependencies Dependencies) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+
+	switch args[0] {
+	case "list":
+		return list(ctx, args[1:], dependencies)
+	case "show":
+		return show(ctx, args[1:], dependencies)
+	case "replay":
+		return replay(ctx, args[1:], dependencies)
+	case "purge":
+		return purge(ctx, args[1:], dependencies)
+	default:
+		return fmt.Errorf("unknown dlq command %q\n%s", args[0], Usage)
+	}
+}
+
+func list(ctx context.Context, args []string, dependencies Dependencies) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	stage := flags.String("stage", "", "only the dead letters of this stage")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deadLetters, err := findAll(ctx, dependencies.Store, *stage)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(dependencies.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MESSAGE ID\tSTAGE\tFAILED AT\tREASON")
+	for _, deadLetter := range deadLetters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", deadLetter.MessageID, deadLetter.Stage, deadLetter.FailedAt.Format(time.RFC3339), summarize(deadLetter.Reason))
+	}
+	return w.Flush()
+}
+
+func show(ctx context.Context, args []string, dependencies Dependencies) error {
+	if len(args) != 1 {
+		return errors.New("usage: workdayctl dlq show <message-id>")
+	}
+
+	deadLetter, err := find(ctx, dependencies.Store, args[0])
+	if err != nil {
+		return err
+	}
+	deadLetter, err = message.ResolveDeadLetter(ctx, deadLetter, dependencies.ClaimCheckStore)
+	if err != nil {
+		return err
+	}
+
+	// The message is printed as JSON rather than as an escaped string when it is JSON.
+	view := struct {
+		message.DeadLetter
+		Message any `json:"message"`
+	}{DeadLetter: deadLetter, Message: deadLetter.Message}
+	if json.Valid([]byte(deadLetter.Message)) {
+		view.Message = json.RawMessage(deadLetter.Message)
+	}
+
+	encoder := json.NewEncoder(dependencies.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(view)
+}
+
+// replay republishes the selected dead letters and deletes each one once it is
+// published. It carries on past the ones that cannot be replayed and reports them at the end.
+func replay(ctx context.Context, args []string, dependencies Dependencies) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	stage := flags.String("stage", "", "only the dead letters of this stage (with -all)")
+	all := flags.Bool("all", false, "replay every dead letter")
+	dryRun := flags.Bool("dry-run", false, "print what would be replayed without publishing")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deadLetters, err := selectDeadLetters(ctx, dependencies.Store, *all, *stage, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	var failures error
+	for _, deadLetter := range deadLetters {
+		if *dryRun {
+			fmt.Fprintf(dependencies.Stdout, "would replay %s to %s\n", deadLetter.MessageID, deadLetter.Stage)
+			continue
+		}
+
+		if err := Replay(ctx, dependencies.Publishers, dependencies.ClaimCheckStore, deadLetter); err != nil {
+			failures = errors.Join(failures, fmt.Errorf("%s: %w", deadLetter.MessageID, err))
+			continue
+		}
+		if err := dependencies.Store.Delete(ctx, deadLetter.MessageID); err != nil {
+			failures = errors.Join(failures, fmt.Errorf("%s: replayed but not deleted, it may be replayed twice: %w", deadLetter.MessageID, err))
+			continue
+		}
+		fmt.Fprintf(dependencies.Stdout, "replayed %s to %s\n", deadLetter.MessageID, deadLetter.Stage)
+	}
+	return failures
+}
+
+func purge(ctx context.Context, args []string, dependencies Dependencies) error {
+	flags := flag.NewFlagSet("purge", flag.ContinueOnError)
+	stage := flags.String("stage", "", "only the dead letters of this stage (with -all)")
+	all := flags.Bool("all", false, "purge every dead letter")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	deadLetters, err := selectDeadLetters(ctx, dependencies.Store, *all, *stage, flags.Args())
+	if err != nil {
+		return err
+	}
+
+	for _, deadLetter := range deadLetters {
+		if err := dependencies.Store.Delete(ctx, deadLetter.MessageID); err != nil {
+			return fmt.Errorf("%s: %w", deadLetter.MessageID, err)
+		}
+		fmt.Fprintf(dependencies.Stdout, "purged %s\n", deadLetter.MessageID)
+	}
+	return nil
+}
+
+// selectDeadLetters returns the dead letters given by messageIDs, or with all every
+// dead letter of stage (of every stage when stage is empty). Naming neither is an
+// error, so that a bare replay or purge never touches everything by accident.
+func selectDeadLetters(ctx context.Context, store deadletter.Store, all bool, stage string, messageIDs []string) ([]message.DeadLetter, error) {
+	switch {
+	case all && len(messageIDs) > 0:
+		return nil, errors.New("give either -all or message IDs, not both")
+	case all:
+		return findAll(ctx, store, stage)
+	case len(messageIDs) == 0:
+		return nil, errors.New("give -all or at least one message ID")
+	}
+
+	deadLetters := make([]message.DeadLetter, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		deadLetter, err := find(ctx, store, messageID)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+func findAll(ctx context.Context, store deadletter.Store, stage string) ([]message.DeadLetter, error) {
+	deadLetters, err := store.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stage == "" {
+		return deadLetters, nil
+	}
+
+	filtered := []message.DeadLetter{}
+	for _, deadLetter := range deadLetters {
+		if deadLetter.Stage == stage {
+			filtered = append(filtered, deadLetter)
+		}
+	}
+	return filtered, nil
+}
+
+func find(ctx context.Context, store deadletter.Store, messageID string) (message.DeadLetter, error) {
+	deadLetter, err := store.Find(ctx, messageID)
+	if errors.Is(err, deadletter.ErrNotFound) {
+		return message.DeadLetter{}, fmt.Errorf("%s: %w", messageID, err)
+	}
+	return deadLetter, err
+}
+
+// summarize keeps the first line of reason, cut to fit a terminal row.
+func summarize(reason string) string {
+	const maxLength = 80
+	reason, _, _ = strings.Cut(reason, "\n")
+	if runes := []rune(reason); len(runes) > maxLength {
+		return string(runes[:maxLength-1]) + "…"
+	}
+	return reason
+}
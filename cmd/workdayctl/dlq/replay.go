@@ -0,0 +1,92 @@
+package dlq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+)
+
+// Publishers publish to the topics the stages subscribe to. A nil publisher
+// means the topic is not configured, and the dead letters of its stage cannot be replayed.
+type Publishers struct {
+	Subscribe *publisher.SubscribeMessagePublisher
+	Clean     *publisher.WriterMessagePublisher
+	Translate *publisher.WriterMessagePublisher
+	Write     *publisher.WriterMessagePublisher
+	Delete    *publisher.DeleteMessagePublisher
+}
+
+// Replay publishes the message of deadLetter again to the topic of the stage it failed in.
+// The message is decoded and published through the publisher of that topic, so it
+// is sent the way the previous stage would send it, under its original correlation ID.
+// claimCheckStore resolves the dead letters and the Write messages that were sent as
+// a claim check.
+func Replay(ctx context.Context, publishers Publishers, claimCheckStore claimcheck.Store, deadLetter message.DeadLetter) error {
+	deadLetter, err := message.ResolveDeadLetter(ctx, deadLetter, claimCheckStore)
+	if err != nil {
+		return err
+	}
+
+	envelope, _ := message.PeekEnvelope(deadLetter.Message)
+	ctx = message.ContextWithTrace(ctx, message.Trace{CorrelationID: envelope.CorrelationID, Origin: message.OriginReplay})
+
+	switch deadLetter.Stage {
+	case message.StageSubscribe:
+		if publishers.Subscribe == nil {
+			return notConfigured(deadLetter.Stage)
+		}
+		var subscribe message.Subscribe
+		if _, err := message.Decode(deadLetter.Message, message.TypeSubscribe, &subscribe); err != nil {
+			return fmt.Errorf("failed to decode the %s message: %w", deadLetter.Stage, err)
+		}
+		return publishers.Subscribe.Publish(ctx, subscribe)
+
+	case message.StageClean, message.StageTranslate, message.StageWrite:
+		writerPublisher := map[string]*publisher.WriterMessagePublisher{
+			message.StageClean:     publishers.Clean,
+			message.StageTranslate: publishers.Translate,
+			message.StageWrite:     publishers.Write,
+		}[deadLetter.Stage]
+		if writerPublisher == nil {
+			return notConfigured(deadLetter.Stage)
+		}
+		rssEntry, err := decodeWrite(ctx, deadLetter.Message, claimCheckStore)
+		if err != nil {
+			return fmt.Errorf("failed to decode the %s message: %w", deadLetter.Stage, err)
+		}
+		return writerPublisher.Publish(ctx, rssEntry)
+
+	case message.StageDelete:
+		if publishers.Delete == nil {
+			return notConfigured(deadLetter.Stage)
+		}
+		var deleteMessage message.Delete
+		if _, err := message.Decode(deadLetter.Message, message.TypeDelete, &deleteMessage); err != nil {
+			return fmt.Errorf("failed to decode the %s message: %w", deadLetter.Stage, err)
+		}
+		return publishers.Delete.Publish(ctx, deleteMessage.Source)
+
+	case message.StageNotification:
+		return fmt.Errorf("%s failures come from the DynamoDB stream and cannot be replayed; save the feed again to notify it", deadLetter.Stage)
+
+	default:
+		return fmt.Errorf("unknown stage %q", deadLetter.Stage)
+	}
+}
+
+func decodeWrite(ctx context.Context, receiveMessage string, claimCheckStore claimcheck.Store) (rss.Rss, error) {
+	var writeMessage message.Write
+	if _, err := message.Decode(receiveMessage, message.TypeWrite, &writeMessage); err != nil {
+		return rss.Rss{}, err
+	}
+	writeMessage, err := message.ResolveWrite(ctx, writeMessage, claimCheckStore)
+	return writeMessage.RssFeed, err
+}
+
+func notConfigured(stage string) error {
+	return fmt.Errorf("no topic is configured for the %s stage", stage)
+}
@@ -0,0 +1,3 @@
+module github.com/YamazakiNorihito/workday/cmd/workdayctl
+
+go 1.22.2
\ No newline at end of file
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	awsConfig "github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared/aws_config"
+	"github.com/YamazakiNorihito/workday/cmd/workdayctl/dlq"
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+const usage = `usage: workdayctl <command> [arguments]
+
+commands:
+  dlq    inspect, replay and purge the dead letters of the rss pipeline
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "dlq":
+		err = runDlq(ctx, os.Args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runDlq reads the dead letters from the Rss table and replays them to the topics
+// given by SUBSCRIBE_TOPIC_ARN, CLEAN_TOPIC_ARN, TRANSLATE_TOPIC_ARN,
+// WRITE_TOPIC_ARN and DELETE_TOPIC_ARN. Only the stages whose topic is set can be replayed.
+func runDlq(ctx context.Context, args []string) error {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	cfg := awsConfig.LoadConfig(ctx)
+	snsClient := cfg.NewSnsClient()
+	claimCheckStore := cfg.NewClaimCheckStore()
+	newWriterPublisher := func(topicPublisher publisher.MessagePublisher) *publisher.WriterMessagePublisher {
+		return publisher.NewWriterMessagePublisherWithClaimCheck(topicPublisher, claimCheckStore)
+	}
+
+	return dlq.Run(ctx, args, dlq.Dependencies{
+		Store: deadletter.NewDynamoDBStore(cfg.NewDynamodbClient(), logger),
+		Publishers: dlq.Publishers{
+			Subscribe: newPublisher(snsClient, "SUBSCRIBE_TOPIC_ARN", publisher.NewSubscribeMessagePublisher),
+			Clean:     newPublisher(snsClient, "CLEAN_TOPIC_ARN", newWriterPublisher),
+			Translate: newPublisher(snsClient, "TRANSLATE_TOPIC_ARN", newWriterPublisher),
+			Write:     newPublisher(snsClient, "WRITE_TOPIC_ARN", newWriterPublisher),
+			Delete:    newPublisher(snsClient, "DELETE_TOPIC_ARN", publisher.NewDeleteMessagePublisher),
+		},
+		ClaimCheckStore: claimCheckStore,
+		Stdout:          os.Stdout,
+	})
+}
+
+func newPublisher[T any](snsClient *sns.Client, topicArnEnv string, newTopicPublisher func(publisher.MessagePublisher) *T) *T {
+	topicArn := os.Getenv(topicArnEnv)
+	if topicArn == "" {
+		return nil
+	}
+	return newTopicPublisher(awsConfig.NewSnsTopicClient(snsClient, topicArn))
+}
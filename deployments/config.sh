@@ -19,8 +19,16 @@ FUNCTIONs=("RssNotificationFunction:event/notification"
         "RssTranslateFunction:event/translate"
         "RssCleanFunction:event/clean"
         "RssDeleteFunction:event/delete"
+        "RssDeadLetterFunction:event/dead_letter"
+        "RssPurgeFunction:event/purge"
+        "RssPruneFunction:event/prune"
         "RssCreateFunction:api/create"
         "RssFeedsFunction:api/feeds"
         "RssFeedIdFunction:api/feed_id"
+        "RssFilterPreviewFunction:api/filter_preview"
+        "RssItemsFunction:api/items"
         "RssPatchFunction:api/patch"
+        "RssRefreshFunction:api/refresh"
+        "RssStatusFunction:api/status"
+        "RssTrashFunction:api/trash"
         "RssDeleteRequestHandlerFunction:api/delete")
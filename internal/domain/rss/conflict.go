@@ -0,0 +1,25 @@
+package rss
+
+import "fmt"
+
+// ConflictError is returned by Save when the stored feed has been saved by someone
+// else since it was read, that is when its version is no longer Version.
+//
+// Only the saves of a feed read by the saver itself are guarded: the write stage
+// and the APIs. The version is not sent in messages, so what clean read is not
+// checked; write reads the feed again and puts only the fetched data on it, and
+// the items clean found new or changed are written whatever was saved meanwhile.
+type ConflictError struct {
+	Source  string
+	Version int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("rss %s was updated concurrently: version %d is stale", e.Source, e.Version)
+}
+
+// Retryable tells the event handlers that the write can succeed once it is merged
+// with the stored feed again.
+func (e *ConflictError) Retryable() bool {
+	return true
+}
@@ -0,0 +1,59 @@
+package rss
+
+import "slices"
+
+// FetchParts tracks a fetch too large for one Write message, whose items reach the
+// write stage as several parts of the same feed. On a feed in a message, Written
+// holds the number of the part it carries; on a stored feed, the numbers of the
+// parts written so far, which Merge adds up.
+type FetchParts struct {
+	FetchID string `json:"fetch_id"`
+	Parts   int    `json:"parts"`
+	Written []int  `json:"written"`
+	// NewFeed tells that the first part written created the feed, and
+	// CompletedVersion is the version of the save that wrote the last missing part.
+	NewFeed          bool `json:"new_feed,omitempty"`
+	CompletedVersion int  `json:"completed_version,omitempty"`
+}
+
+// NewFetchParts returns the FetchParts of the part-th of parts of the fetch fetchID.
+func NewFetchParts(fetchID string, part int, parts int) *FetchParts {
+	return &FetchParts{FetchID: fetchID, Parts: parts, Written: []int{part}}
+}
+
+// IsComplete reports whether every part of the fetch has been written. A feed that
+// was not fetched in parts is always complete.
+func (p *FetchParts) IsComplete() bool {
+	return p == nil || len(p.Written) >= p.Parts
+}
+
+// Merge returns the parts written of stored, the FetchParts of the stored feed, with
+// those of p added when both belong to the same fetch, or p alone when they do not.
+func (p *FetchParts) Merge(stored *FetchParts) *FetchParts {
+	merged := *p
+	merged.Written = slices.Clone(p.Written)
+	if stored == nil || stored.FetchID != p.FetchID {
+		return &merged
+	}
+
+	merged.NewFeed = stored.NewFeed
+	merged.CompletedVersion = stored.CompletedVersion
+	for _, part := range stored.Written {
+		if !slices.Contains(merged.Written, part) {
+			merged.Written = append(merged.Written, part)
+		}
+	}
+	slices.Sort(merged.Written)
+	return &merged
+}
+
+func (p *FetchParts) Equal(other *FetchParts) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return p.FetchID == other.FetchID &&
+		p.Parts == other.Parts &&
+		slices.Equal(p.Written, other.Written) &&
+		p.NewFeed == other.NewFeed &&
+		p.CompletedVersion == other.CompletedVersion
+}
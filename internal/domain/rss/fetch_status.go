@@ -0,0 +1,46 @@
+package rss
+
+import "time"
+
+// FetchStatus is the outcome of the recent fetches of a feed by the subscribe stage.
+// A feed is disabled once ConsecutiveFailures reaches the configured limit and is
+// enabled again by the next successful fetch. The trigger skips a disabled feed, so
+// only a refresh through the API fetches it again.
+type FetchStatus struct {
+	LastSuccessAt       time.Time `json:"last_success_at"`
+	LastFailureAt       time.Time `json:"last_failure_at"`
+	LastError           string    `json:"last_error"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	HTTPStatus          int       `json:"http_status"`
+	DisabledAt          time.Time `json:"disabled_at"`
+}
+
+func (s *FetchStatus) RecordSuccess(now time.Time, httpStatus int) {
+	s.LastSuccessAt = now
+	s.HTTPStatus = httpStatus
+	s.ConsecutiveFailures = 0
+	s.DisabledAt = time.Time{}
+}
+
+// RecordFailure counts a failed fetch and disables the feed when the number of
+// consecutive failures reaches maxConsecutiveFailures.
+// It reports true only for the failure that disabled the feed.
+func (s *FetchStatus) RecordFailure(now time.Time, httpStatus int, fetchErr error, maxConsecutiveFailures int) (disabled bool) {
+	s.LastFailureAt = now
+	s.HTTPStatus = httpStatus
+	s.ConsecutiveFailures++
+	if fetchErr != nil {
+		s.LastError = fetchErr.Error()
+	}
+
+	if s.IsDisabled() || maxConsecutiveFailures <= 0 || s.ConsecutiveFailures < maxConsecutiveFailures {
+		return false
+	}
+
+	s.DisabledAt = now
+	return true
+}
+
+func (s FetchStatus) IsDisabled() bool {
+	return !s.DisabledAt.IsZero()
+}
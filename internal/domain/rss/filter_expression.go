@@ -0,0 +1,210 @@
+package rss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FilterExpression is a node of the AST of a filter expression. See
+// ParseFilterExpression for the language.
+type FilterExpression interface {
+	Match(item Item) bool
+	// String returns the node in the canonical form of the language, which
+	// parses back to the same node.
+	String() string
+}
+
+// Fields a filter expression term can be scoped to. FieldAny, the field of an
+// unscoped term, matches the title or the description.
+const (
+	FieldAny         = ""
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldAuthor      = "author"
+	FieldLink        = "link"
+	FieldTag         = "tag"
+	FieldPubDate     = "pubdate"
+)
+
+// Operators of a filter expression term.
+const (
+	// OperatorContains matches a text containing the value.
+	OperatorContains = ":"
+	// OperatorContainsFold matches a text containing the value, ignoring case.
+	OperatorContainsFold = "~"
+	// OperatorWord matches a text containing the value as a whole word.
+	OperatorWord = "="
+	// OperatorWordFold matches a text containing the value as a whole word, ignoring case.
+	OperatorWordFold = "~="
+
+	OperatorBefore     = "<"
+	OperatorBeforeOrOn = "<="
+	OperatorAfter      = ">"
+	OperatorAfterOrOn  = ">="
+	OperatorOn         = "="
+)
+
+type FilterAnd struct {
+	Left, Right FilterExpression
+}
+
+func (e FilterAnd) Match(item Item) bool {
+	return e.Left.Match(item) && e.Right.Match(item)
+}
+
+func (e FilterAnd) String() string {
+	return "(" + e.Left.String() + " AND " + e.Right.String() + ")"
+}
+
+type FilterOr struct {
+	Left, Right FilterExpression
+}
+
+func (e FilterOr) Match(item Item) bool {
+	return e.Left.Match(item) || e.Right.Match(item)
+}
+
+func (e FilterOr) String() string {
+	return "(" + e.Left.String() + " OR " + e.Right.String() + ")"
+}
+
+type FilterNot struct {
+	Operand FilterExpression
+}
+
+func (e FilterNot) Match(item Item) bool {
+	return !e.Operand.Match(item)
+}
+
+func (e FilterNot) String() string {
+	return "NOT " + e.Operand.String()
+}
+
+// FilterText matches Value against the Field of an item with Operator, one of
+// the text operators. A tag term matches when any of the tags does.
+type FilterText struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+func (e FilterText) Match(item Item) bool {
+	for _, text := range e.texts(item) {
+		if e.matchText(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e FilterText) texts(item Item) []string {
+	switch e.Field {
+	case FieldTitle:
+		return []string{item.Title}
+	case FieldDescription:
+		return []string{item.Description}
+	case FieldAuthor:
+		return []string{item.Author}
+	case FieldLink:
+		return []string{item.Link}
+	case FieldTag:
+		return item.Tags
+	default:
+		return []string{item.Title, item.Description}
+	}
+}
+
+func (e FilterText) matchText(text string) bool {
+	value := e.Value
+	if e.Operator == OperatorContainsFold || e.Operator == OperatorWordFold {
+		text, value = strings.ToLower(text), strings.ToLower(value)
+	}
+
+	if e.Operator == OperatorWord || e.Operator == OperatorWordFold {
+		return containsWord(text, value)
+	}
+	return strings.Contains(text, value)
+}
+
+func (e FilterText) String() string {
+	value := `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(e.Value) + `"`
+	if e.Field == FieldAny && e.Operator == OperatorContains {
+		return value
+	}
+	return e.Field + e.Operator + value
+}
+
+// containsWord reports whether value appears in text with no letter, digit or
+// underscore right before or after it.
+func containsWord(text string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for offset := 0; offset <= len(text)-len(value); {
+		index := strings.Index(text[offset:], value)
+		if index < 0 {
+			return false
+		}
+		start, end := offset+index, offset+index+len(value)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// FilterDate compares the publication date of an item with the period from From
+// until To, exclusive. A date written without a time covers its whole day in UTC;
+// a time covers only its instant.
+type FilterDate struct {
+	Operator string
+	From     time.Time
+	To       time.Time
+}
+
+func (e FilterDate) Match(item Item) bool {
+	pubDate := item.PubDate
+	switch e.Operator {
+	case OperatorBefore:
+		return pubDate.Before(e.From)
+	case OperatorBeforeOrOn:
+		return pubDate.Before(e.To)
+	case OperatorAfter:
+		return !pubDate.Before(e.To)
+	case OperatorAfterOrOn:
+		return !pubDate.Before(e.From)
+	default:
+		return !pubDate.Before(e.From) && pubDate.Before(e.To)
+	}
+}
+
+func (e FilterDate) String() string {
+	if e.To.Sub(e.From) == 24*time.Hour && e.From.Equal(e.From.Truncate(24*time.Hour)) {
+		return FieldPubDate + e.Operator + e.From.UTC().Format(time.DateOnly)
+	}
+	return FieldPubDate + e.Operator + e.From.UTC().Format(time.RFC3339Nano)
+}
+
+// FilterExpressionError is returned by ParseFilterExpression for an invalid
+// expression. Position is the byte offset of the error in the expression.
+type FilterExpressionError struct {
+	Position int
+	Message  string
+}
+
+func (e *FilterExpressionError) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.Position, e.Message)
+}
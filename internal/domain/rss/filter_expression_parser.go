@@ -0,0 +1,369 @@
+package rss
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseFilterExpression parses a filter expression into its AST.
+//
+// An expression is made of terms combined with AND, OR, NOT and parentheses.
+// AND binds tighter than OR, and terms written next to each other are joined
+// with AND. A term is a value, a word or a double-quoted string, optionally
+// scoped to a field and preceded by an operator:
+//
+//	go                   title or description contains "go"
+//	title:go             title contains "go"
+//	title~go             title contains "go", ignoring case
+//	title=go             title contains the whole word "go"
+//	title~=go            title contains the whole word "go", ignoring case
+//	author:"Jane Doe"    author contains "Jane Doe"
+//	tag=golang           a tag is the word "golang"
+//	pubdate>=2024-07-01  published on or after July 1, 2024 (UTC)
+//	pubdate<2024-07-01T09:00:00+09:00
+//
+// The fields are title, description, author, link, tag and pubdate. pubdate
+// takes the date operators <, <=, >, >= and =, and the other fields the text
+// operators :, ~, = and ~=. An unscoped term takes the text operators too.
+// A prefix that is not a field is part of an unscoped value, so https://go.dev
+// and foo:bar need no quotes. For example:
+//
+//	(title~go OR tag=golang) AND NOT author:bot AND pubdate>=2024-01-01
+//
+// An expression is at most MaxFilterExpressionLength bytes long, and NOT and
+// parentheses nest at most MaxFilterExpressionDepth deep.
+func ParseFilterExpression(source string) (FilterExpression, error) {
+	if len(source) > MaxFilterExpressionLength {
+		return nil, &FilterExpressionError{Position: MaxFilterExpressionLength, Message: fmt.Sprintf("the expression is longer than %d bytes", MaxFilterExpressionLength)}
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{source: source, tokens: tokens}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != tokenEnd {
+		return nil, p.errorAt(token, "unexpected %s", token.describe())
+	}
+	return expression, nil
+}
+
+// Limits of a filter expression, which comes from the API and is parsed and
+// matched recursively.
+const (
+	MaxFilterExpressionLength = 1024
+	MaxFilterExpressionDepth  = 32
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenLeftParen
+	tokenRightParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTerm
+)
+
+type token struct {
+	kind     tokenKind
+	position int
+	field    string
+	operator string
+	value    string
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEnd:
+		return "end of the expression"
+	case tokenLeftParen:
+		return `"("`
+	case tokenRightParen:
+		return `")"`
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	default:
+		return fmt.Sprintf("term %q", t.field+t.operator+t.value)
+	}
+}
+
+var filterFields = map[string]bool{
+	FieldTitle:       true,
+	FieldDescription: true,
+	FieldAuthor:      true,
+	FieldLink:        true,
+	FieldTag:         true,
+	FieldPubDate:     true,
+}
+
+// filterOperators are tried in order, so the longer ones come first.
+var filterOperators = []string{OperatorWordFold, OperatorBeforeOrOn, OperatorAfterOrOn, OperatorContains, OperatorContainsFold, OperatorWord, OperatorBefore, OperatorAfter}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for position := 0; ; {
+		for position < len(source) {
+			r, size := utf8.DecodeRuneInString(source[position:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			position += size
+		}
+		if position == len(source) {
+			return append(tokens, token{kind: tokenEnd, position: position}), nil
+		}
+
+		switch source[position] {
+		case '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, position: position})
+			position++
+			continue
+		case ')':
+			tokens = append(tokens, token{kind: tokenRightParen, position: position})
+			position++
+			continue
+		}
+
+		term, next, err := scanTerm(source, position)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, term)
+		position = next
+	}
+}
+
+// scanTerm scans the term starting at start and returns it with the offset after it.
+func scanTerm(source string, start int) (token, int, error) {
+	term := token{kind: tokenTerm, position: start}
+	position := start
+
+	fieldEnd := position
+	for fieldEnd < len(source) && source[fieldEnd] >= 'a' && source[fieldEnd] <= 'z' {
+		fieldEnd++
+	}
+	if operator := operatorAt(source, fieldEnd); operator != "" {
+		if field := source[position:fieldEnd]; field == "" || filterFields[field] {
+			term.field, term.operator = field, operator
+			position = fieldEnd + len(operator)
+		}
+	}
+
+	value, next, quoted, err := scanValue(source, position)
+	if err != nil {
+		return token{}, 0, err
+	}
+	term.value = value
+
+	if term.operator == "" && !quoted {
+		switch value {
+		case "AND":
+			term.kind = tokenAnd
+		case "OR":
+			term.kind = tokenOr
+		case "NOT":
+			term.kind = tokenNot
+		}
+	}
+	if term.kind == tokenTerm && value == "" {
+		return token{}, 0, &FilterExpressionError{Position: position, Message: "missing value"}
+	}
+	return term, next, nil
+}
+
+func operatorAt(source string, position int) string {
+	for _, operator := range filterOperators {
+		if strings.HasPrefix(source[position:], operator) {
+			return operator
+		}
+	}
+	return ""
+}
+
+// scanValue scans a word, which ends at a space or a parenthesis, or a double-quoted
+// string, in which \" and \\ stand for " and \.
+func scanValue(source string, start int) (value string, next int, quoted bool, err error) {
+	if start < len(source) && source[start] == '"' {
+		var builder strings.Builder
+		for position := start + 1; position < len(source); position++ {
+			switch source[position] {
+			case '\\':
+				if position+1 < len(source) && (source[position+1] == '"' || source[position+1] == '\\') {
+					position++
+				}
+				builder.WriteByte(source[position])
+			case '"':
+				return builder.String(), position + 1, true, nil
+			default:
+				builder.WriteByte(source[position])
+			}
+		}
+		return "", 0, false, &FilterExpressionError{Position: start, Message: "unterminated quoted string"}
+	}
+
+	position := start
+	for position < len(source) {
+		r, size := utf8.DecodeRuneInString(source[position:])
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+			break
+		}
+		position += size
+	}
+	return source[start:position], position, false, nil
+}
+
+type filterParser struct {
+	source   string
+	tokens   []token
+	position int
+	depth    int
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *filterParser) next() token {
+	token := p.tokens[p.position]
+	if token.kind != tokenEnd {
+		p.position++
+	}
+	return token
+}
+
+func (p *filterParser) errorAt(token token, format string, args ...any) error {
+	return &FilterExpressionError{Position: token.position, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *filterParser) parseOr() (FilterExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = FilterOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (FilterExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenNot, tokenLeftParen, tokenTerm:
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = FilterAnd{Left: left, Right: right}
+	}
+}
+
+// nest enters a NOT or parentheses at token, and returns the function that leaves it.
+func (p *filterParser) nest(token token) (func(), error) {
+	if p.depth >= MaxFilterExpressionDepth {
+		return nil, p.errorAt(token, "NOT and parentheses nest deeper than %d", MaxFilterExpressionDepth)
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *filterParser) parseUnary() (FilterExpression, error) {
+	if token := p.peek(); token.kind == tokenNot {
+		p.next()
+		leave, err := p.nest(token)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return FilterNot{Operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (FilterExpression, error) {
+	token := p.next()
+	switch token.kind {
+	case tokenLeftParen:
+		leave, err := p.nest(token)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, p.errorAt(closing, `expected ")" but found %s`, closing.describe())
+		}
+		return expression, nil
+	case tokenTerm:
+		return p.parseTerm(token)
+	default:
+		return nil, p.errorAt(token, "expected a term but found %s", token.describe())
+	}
+}
+
+func (p *filterParser) parseTerm(term token) (FilterExpression, error) {
+	if term.field == FieldPubDate {
+		return parseDateTerm(term)
+	}
+
+	switch term.operator {
+	case "":
+		return FilterText{Field: term.field, Operator: OperatorContains, Value: term.value}, nil
+	case OperatorContains, OperatorContainsFold, OperatorWord, OperatorWordFold:
+		return FilterText{Field: term.field, Operator: term.operator, Value: term.value}, nil
+	default:
+		return nil, p.errorAt(term, "operator %q is only for %s", term.operator, FieldPubDate)
+	}
+}
+
+func parseDateTerm(term token) (FilterExpression, error) {
+	switch term.operator {
+	case OperatorBefore, OperatorBeforeOrOn, OperatorAfter, OperatorAfterOrOn, OperatorOn:
+	default:
+		return nil, &FilterExpressionError{Position: term.position, Message: fmt.Sprintf("operator %q is not for %s", term.operator, FieldPubDate)}
+	}
+
+	if date, err := time.Parse(time.DateOnly, term.value); err == nil {
+		return FilterDate{Operator: term.operator, From: date, To: date.Add(24 * time.Hour)}, nil
+	}
+	if instant, err := time.Parse(time.RFC3339Nano, term.value); err == nil {
+		return FilterDate{Operator: term.operator, From: instant, To: instant.Add(time.Nanosecond)}, nil
+	}
+	return nil, &FilterExpressionError{Position: term.position, Message: fmt.Sprintf("invalid date %q: use YYYY-MM-DD or RFC 3339", term.value)}
+}
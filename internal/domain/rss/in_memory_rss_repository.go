@@ -0,0 +1,458 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+type inMemoryKey struct {
+	partitionKey string
+	sortKey      string
+}
+
+// InMemoryRssRepository is an IRssRepository that keeps the rows of the Rss table
+// in memory. The rows are the same models DynamoDBRssRepository writes, marshaled
+// the same way, so both repositories round-trip an Rss identically (times are
+// truncated to seconds, items are keyed by "rssId#guid", and so on).
+// TTL attributes are stored but rows never expire.
+// It is safe for concurrent use.
+type InMemoryRssRepository struct {
+	mu   sync.RWMutex
+	rows map[inMemoryKey]map[string]types.AttributeValue
+}
+
+func NewInMemoryRssRepository() *InMemoryRssRepository {
+	return &InMemoryRssRepository{rows: make(map[inMemoryKey]map[string]types.AttributeValue)}
+}
+
+func (r *InMemoryRssRepository) FindBySource(ctx context.Context, source string) (Rss, error) {
+	if source == "" {
+		return Rss{}, errors.New("invalid source")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var model rssModel
+	if err := r.get(source, "rss", &model); err != nil {
+		return Rss{}, err
+	}
+
+	rss := buildRss(rssManager{rss: model, items: []itemModel{}})
+	if rss.ID == uuid.Nil {
+		return rss, nil
+	}
+
+	var status fetchStatusModel
+	if err := r.get(source, fetchStatusSortKey, &status); err != nil {
+		return Rss{}, err
+	}
+	rss.FetchStatus = buildFetchStatus(status)
+	return rss, nil
+}
+
+func (r *InMemoryRssRepository) FindAll(ctx context.Context) ([]Rss, error) {
+	var rssFeeds []Rss
+	err := r.FindAllPages(ctx, func(page []Rss) bool {
+		rssFeeds = append(rssFeeds, page...)
+		return true
+	})
+	if err != nil {
+		return []Rss{}, err
+	}
+
+	return rssFeeds, nil
+}
+
+// FindAllPages calls fn once with every stored Rss, ordered by source.
+func (r *InMemoryRssRepository) FindAllPages(ctx context.Context, fn func(rssFeeds []Rss) bool) error {
+	r.mu.RLock()
+	var models []rssModel
+	err := r.scan(func(key inMemoryKey) bool { return key.sortKey == "rss" }, &models)
+	if err != nil {
+		r.mu.RUnlock()
+		return err
+	}
+
+	rssFeeds := make([]Rss, 0, len(models))
+	for _, model := range models {
+		rss := buildRss(rssManager{rss: model, items: []itemModel{}})
+
+		var status fetchStatusModel
+		if err := r.get(model.PartitionKey, fetchStatusSortKey, &status); err != nil {
+			r.mu.RUnlock()
+			return err
+		}
+		rss.FetchStatus = buildFetchStatus(status)
+		rssFeeds = append(rssFeeds, rss)
+	}
+	r.mu.RUnlock()
+
+	fn(rssFeeds)
+	return nil
+}
+
+func (r *InMemoryRssRepository) FindItems(ctx context.Context, rss Rss) (Rss, error) {
+	if rss.Source == "" {
+		return Rss{}, errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	itemModels, err := r.itemModels(manager.rss)
+	if err != nil {
+		return Rss{}, err
+	}
+
+	return buildRss(rssManager{rss: manager.rss, items: itemModels}), nil
+}
+
+// FindItemsPages calls fn once with every item of rss.
+func (r *InMemoryRssRepository) FindItemsPages(ctx context.Context, rss Rss, fn func(items []Item) bool) error {
+	if rss.Source == "" {
+		return errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	itemModels, err := r.itemModels(manager.rss)
+	if err != nil {
+		return err
+	}
+
+	items := make([]Item, 0, len(itemModels))
+	for _, model := range itemModels {
+		items = append(items, buildItem(model))
+	}
+	fn(items)
+	return nil
+}
+
+func (r *InMemoryRssRepository) FindItemsByPk(ctx context.Context, rss Rss, guid Guid) (Rss, error) {
+	if rss.ID.String() == "" || guid.Value == "" {
+		return Rss{}, errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	searchItemModel := manager.rss.NewItemModel(Item{Guid: guid})
+
+	r.mu.RLock()
+	var findItemModel itemModel
+	err := r.get(searchItemModel.PartitionKey, searchItemModel.SortKey, &findItemModel)
+	r.mu.RUnlock()
+	if err != nil {
+		return Rss{}, err
+	}
+
+	itemModels := []itemModel{}
+	if findItemModel.PartitionKey != "" {
+		itemModels = []itemModel{findItemModel}
+	}
+
+	return buildRss(rssManager{rss: manager.rss, items: itemModels}), nil
+}
+
+// FindItemsPage returns one page of the items of rss ordered by PubDate, with the
+// same cursors as DynamoDBRssRepository. As with DynamoDB, a full page always has
+// a NextCursor, even when no items follow it.
+func (r *InMemoryRssRepository) FindItemsPage(ctx context.Context, rss Rss, query ItemQuery) (ItemPage, error) {
+	if rss.ID == uuid.Nil {
+		return ItemPage{}, errors.New("invalid rss ID")
+	}
+	if query.Limit <= 0 {
+		return ItemPage{}, errors.New("invalid limit")
+	}
+
+	rssId := rss.ID.String()
+	startKey, err := decodeItemCursor(query.Cursor, rssId)
+	if err != nil {
+		return ItemPage{}, err
+	}
+	var start *itemCursor
+	if startKey != nil {
+		start = &itemCursor{}
+		if err := attributevalue.UnmarshalMap(startKey, start); err != nil {
+			return ItemPage{}, err
+		}
+	}
+
+	r.mu.RLock()
+	var models []itemModel
+	err = r.scan(func(key inMemoryKey) bool { return strings.HasPrefix(key.sortKey, rssId+"#") }, &models)
+	r.mu.RUnlock()
+	if err != nil {
+		return ItemPage{}, err
+	}
+
+	// The index is ordered by pub_date; items with the same pub_date are ordered by
+	// their table key so that the order is stable across pages.
+	less := func(a, b itemCursor) bool {
+		if a.PubDate != b.PubDate {
+			return a.PubDate < b.PubDate
+		}
+		if a.PartitionKey != b.PartitionKey {
+			return a.PartitionKey < b.PartitionKey
+		}
+		return a.SortKey < b.SortKey
+	}
+	cursorOf := func(model itemModel) itemCursor {
+		return itemCursor{PartitionKey: model.PartitionKey, SortKey: model.SortKey, RssId: model.RssId, PubDate: model.PubDate}
+	}
+	before := func(a, b itemCursor) bool {
+		if query.Ascending {
+			return less(a, b)
+		}
+		return less(b, a)
+	}
+	sort.Slice(models, func(i, j int) bool { return before(cursorOf(models[i]), cursorOf(models[j])) })
+
+	page := ItemPage{Items: []Item{}}
+	var last *itemCursor
+	for _, model := range models {
+		cursor := cursorOf(model)
+		if start != nil && !before(*start, cursor) {
+			continue
+		}
+		if !matchesItemQuery(model, query) {
+			continue
+		}
+
+		page.Items = append(page.Items, buildItem(model))
+		if len(page.Items) == query.Limit {
+			last = &cursor
+			break
+		}
+	}
+
+	if last != nil {
+		lastEvaluatedKey, err := attributevalue.MarshalMap(*last)
+		if err != nil {
+			return ItemPage{}, err
+		}
+		page.NextCursor, err = encodeItemCursor(lastEvaluatedKey)
+		if err != nil {
+			return ItemPage{}, err
+		}
+	}
+	return page, nil
+}
+
+func matchesItemQuery(model itemModel, query ItemQuery) bool {
+	if !query.Since.IsZero() && model.PubDate < query.Since.Unix() {
+		return false
+	}
+	if !query.Until.IsZero() && model.PubDate > query.Until.Unix() {
+		return false
+	}
+	for _, tag := range query.Tags {
+		found := false
+		for _, itemTag := range model.Tags {
+			if itemTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Save stores rss and returns it with its new Version.
+// It returns a *ConflictError when the stored rss is no longer at rss.Version.
+func (r *InMemoryRssRepository) Save(ctx context.Context, rss Rss, updateBy metadata.UserMeta) (Rss, error) {
+	if rss.ID == uuid.Nil {
+		return rss, errors.New("invalid rss ID")
+	}
+
+	now := time.Now()
+
+	if rss.CreatedBy.ID == "" {
+		rss.CreatedAt = metadata.CreateAt(now)
+		rss.CreatedBy = metadata.CreateBy(updateBy)
+	}
+	rss.UpdatedAt = metadata.UpdateAt(now)
+	rss.UpdatedBy = metadata.UpdateBy(updateBy)
+
+	rssManager := buildRssManager(rss)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The version is checked before any item is written, so a stale save leaves nothing behind.
+	var stored rssModel
+	if err := r.get(rssManager.rss.PartitionKey, rssManager.rss.SortKey, &stored); err != nil {
+		return rss, err
+	}
+	if stored.Version != rss.Version {
+		return rss, &ConflictError{Source: rss.Source, Version: rss.Version}
+	}
+
+	for _, item := range rssManager.items {
+		if err := r.put(item.PartitionKey, item.SortKey, item); err != nil {
+			return rss, err
+		}
+	}
+
+	rssManager.rss.Version = rss.Version + 1
+	if err := r.put(rssManager.rss.PartitionKey, rssManager.rss.SortKey, rssManager.rss); err != nil {
+		return rss, err
+	}
+
+	rss.Version = rssManager.rss.Version
+	return rss, nil
+}
+
+// SaveFetchStatus stores the fetch status of rss.
+func (r *InMemoryRssRepository) SaveFetchStatus(ctx context.Context, rss Rss, status FetchStatus) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.put(rss.Source, fetchStatusSortKey, buildFetchStatusModel(rss, status))
+}
+
+// ReserveRefresh records an on-demand refresh of rss at now unless another refresh
+// was reserved within minInterval. It reports false when the refresh is rate limited.
+func (r *InMemoryRssRepository) ReserveRefresh(ctx context.Context, rss Rss, now time.Time, minInterval time.Duration) (bool, error) {
+	if rss.ID == uuid.Nil {
+		return false, errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return false, errors.New("invalid the Source")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var stored refreshModel
+	if err := r.get(rss.Source, refreshSortKey, &stored); err != nil {
+		return false, err
+	}
+	if stored.PartitionKey != "" && stored.RequestedAt > now.Add(-minInterval).Unix() {
+		return false, nil
+	}
+
+	model := refreshModel{
+		PartitionKey: rss.Source,
+		SortKey:      refreshSortKey,
+		RssId:        rss.ID.String(),
+		RequestedAt:  now.Unix(),
+		ExpireAt:     now.Add(minInterval).Unix(),
+	}
+	if err := r.put(model.PartitionKey, model.SortKey, model); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteItems deletes the items of rss identified by guids. The rss row is kept.
+func (r *InMemoryRssRepository) DeleteItems(ctx context.Context, rss Rss, guids []Guid) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	manager := buildRssManager(rss)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, guid := range guids {
+		delete(r.rows, inMemoryKey{manager.rss.PartitionKey, manager.rss.RssId + "#" + guid.Value})
+	}
+	return nil
+}
+
+func (r *InMemoryRssRepository) Delete(ctx context.Context, rss Rss) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	manager := buildRssManager(rss)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.rows {
+		if key.partitionKey == manager.rss.PartitionKey && strings.HasPrefix(key.sortKey, manager.rss.RssId) {
+			delete(r.rows, key)
+		}
+	}
+	delete(r.rows, inMemoryKey{manager.rss.PartitionKey, fetchStatusSortKey})
+	delete(r.rows, inMemoryKey{manager.rss.PartitionKey, refreshSortKey})
+	delete(r.rows, inMemoryKey{manager.rss.PartitionKey, manager.rss.SortKey})
+	return nil
+}
+
+// itemModels returns the items of the rss row model, ordered by sort key as a
+// DynamoDB query would.
+func (r *InMemoryRssRepository) itemModels(model rssModel) ([]itemModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var models []itemModel
+	err := r.scan(func(key inMemoryKey) bool {
+		return key.partitionKey == model.PartitionKey && strings.HasPrefix(key.sortKey, model.RssId)
+	}, &models)
+	return models, err
+}
+
+// get unmarshals the row at the key into out, leaving out untouched when there is none.
+// The caller must hold the lock.
+func (r *InMemoryRssRepository) get(partitionKey string, sortKey string, out interface{}) error {
+	row, ok := r.rows[inMemoryKey{partitionKey, sortKey}]
+	if !ok {
+		return nil
+	}
+	return attributevalue.UnmarshalMap(row, out)
+}
+
+// scan unmarshals the rows whose key matches into out, ordered by key.
+// The caller must hold the lock.
+func (r *InMemoryRssRepository) scan(match func(key inMemoryKey) bool, out interface{}) error {
+	var keys []inMemoryKey
+	for key := range r.rows {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].partitionKey != keys[j].partitionKey {
+			return keys[i].partitionKey < keys[j].partitionKey
+		}
+		return keys[i].sortKey < keys[j].sortKey
+	})
+
+	rows := make([]map[string]types.AttributeValue, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, r.rows[key])
+	}
+	return attributevalue.UnmarshalListOfMaps(rows, out)
+}
+
+// put marshals model and stores it at the key. The caller must hold the lock.
+func (r *InMemoryRssRepository) put(partitionKey string, sortKey string, model interface{}) error {
+	row, err := attributevalue.MarshalMap(model)
+	if err != nil {
+		return err
+	}
+	r.rows[inMemoryKey{partitionKey, sortKey}] = row
+	return nil
+}
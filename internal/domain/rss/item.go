@@ -1,18 +1,34 @@
 package rss
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 )
 
+// MaxItemRevisions is the number of previous versions kept per item.
+const MaxItemRevisions = 10
+
 type Item struct {
-	Guid        Guid      `json:"guid"`
+	Guid        Guid           `json:"guid"`
+	Title       string         `json:"title"`
+	Link        string         `json:"link"`
+	Description string         `json:"description"`
+	Author      string         `json:"author"`
+	PubDate     time.Time      `json:"pubDate"`
+	Tags        []string       `json:"tags"`
+	ContentHash string         `json:"contentHash,omitempty"`
+	Revisions   []ItemRevision `json:"revisions,omitempty"`
+}
+
+// ItemRevision is a previous version of an item, replaced at RevisedAt.
+type ItemRevision struct {
 	Title       string    `json:"title"`
 	Link        string    `json:"link"`
 	Description string    `json:"description"`
-	Author      string    `json:"author"`
-	PubDate     time.Time `json:"pubDate"`
-	Tags        []string  `json:"tags"`
+	ContentHash string    `json:"contentHash"`
+	RevisedAt   time.Time `json:"revisedAt"`
 }
 
 func NewItem(guid Guid, title, link, description, author string, pubDate time.Time) (Item, error) {
@@ -28,9 +44,17 @@ func NewItem(guid Guid, title, link, description, author string, pubDate time.Ti
 		Guid:        guid,
 		PubDate:     pubDate,
 		Tags:        []string{},
+		ContentHash: ContentHash(title, link, description),
 	}, nil
 }
 
+// ContentHash identifies the published content of an item.
+// It is computed from the original text, before any translation.
+func ContentHash(title, link, description string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + link + "\x00" + description))
+	return hex.EncodeToString(sum[:])
+}
+
 func (i *Item) AddTag(tag string) {
 	for _, t := range i.Tags {
 		if t == tag {
@@ -39,3 +63,37 @@ func (i *Item) AddTag(tag string) {
 	}
 	i.Tags = append(i.Tags, tag)
 }
+
+// HasChangedFrom reports whether the content of the item differs from stored.
+// Items stored without a hash are never reported as changed.
+func (i Item) HasChangedFrom(stored Item) bool {
+	if i.ContentHash == "" || stored.ContentHash == "" {
+		return false
+	}
+	return i.ContentHash != stored.ContentHash
+}
+
+// Revise records previous as a revision of the item, keeping the revision history
+// of previous and at most MaxItemRevisions entries.
+func (i *Item) Revise(previous Item, revisedAt time.Time) {
+	revisions := append([]ItemRevision{}, previous.Revisions...)
+	revisions = append(revisions, ItemRevision{
+		Title:       previous.Title,
+		Link:        previous.Link,
+		Description: previous.Description,
+		ContentHash: previous.ContentHash,
+		RevisedAt:   revisedAt,
+	})
+	if len(revisions) > MaxItemRevisions {
+		revisions = revisions[len(revisions)-MaxItemRevisions:]
+	}
+	i.Revisions = revisions
+}
+
+// RevisedAt returns when the item was last revised, or the zero time if it never was.
+func (i Item) RevisedAt() time.Time {
+	if len(i.Revisions) == 0 {
+		return time.Time{}
+	}
+	return i.Revisions[len(i.Revisions)-1].RevisedAt
+}
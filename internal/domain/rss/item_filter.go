@@ -1,10 +1,35 @@
 package rss
 
-import "regexp"
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+)
 
 type ItemFilter struct {
 	IncludeKeywords []string `json:"include_keywords"`
 	ExcludeKeywords []string `json:"exclude_keywords"`
+	// Expression is a filter expression an item has to match besides the keywords.
+	// See ParseFilterExpression. Empty means none.
+	Expression string `json:"expression,omitempty"`
+
+	// compiled is set by the constructors, UnmarshalJSON and SetExpression and never
+	// written after, so the copies of a filter can share it. A filter built as a
+	// literal, or whose fields were changed since, compiles them on every match.
+	compiled *compiledItemFilter
+}
+
+// compiledItemFilter holds the keywords and the expression of an ItemFilter compiled,
+// a nil regexp or expression standing for an invalid one.
+type compiledItemFilter struct {
+	includeKeywords []string
+	excludeKeywords []string
+	expressionText  string
+
+	includeRegexps []*regexp.Regexp
+	excludeRegexps []*regexp.Regexp
+	expression     FilterExpression
 }
 
 func NewItemFilter(includeKeywords, excludeKeywords []string) ItemFilter {
@@ -14,10 +39,97 @@ func NewItemFilter(includeKeywords, excludeKeywords []string) ItemFilter {
 	if excludeKeywords == nil {
 		excludeKeywords = []string{}
 	}
-	return ItemFilter{
+	itemFilter := ItemFilter{
 		IncludeKeywords: includeKeywords,
 		ExcludeKeywords: excludeKeywords,
 	}
+	itemFilter.compile()
+	return itemFilter
+}
+
+// UnmarshalJSON decodes the filter and compiles it, as the constructors do, so that
+// a filter received in a message does not compile its keywords on every match.
+func (f *ItemFilter) UnmarshalJSON(data []byte) error {
+	type plainItemFilter ItemFilter
+	decoded := plainItemFilter(*f)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = ItemFilter(decoded)
+	f.compile()
+	return nil
+}
+
+// NewItemFilterWithExpression returns the filter of NewItemFilter with expression
+// added, or a *FilterExpressionError when expression cannot be parsed.
+func NewItemFilterWithExpression(includeKeywords, excludeKeywords []string, expression string) (ItemFilter, error) {
+	itemFilter := NewItemFilter(includeKeywords, excludeKeywords)
+	if err := itemFilter.SetExpression(expression); err != nil {
+		return ItemFilter{}, err
+	}
+	return itemFilter, nil
+}
+
+// SetExpression replaces the filter expression, keeping the keywords. An empty
+// expression removes it.
+func (f *ItemFilter) SetExpression(expression string) error {
+	if strings.TrimSpace(expression) == "" {
+		f.Expression = ""
+		f.compile()
+		return nil
+	}
+
+	if _, err := ParseFilterExpression(expression); err != nil {
+		return err
+	}
+	f.Expression = expression
+	f.compile()
+	return nil
+}
+
+// ParsedExpression returns the AST of Expression, or nil when there is none.
+func (f *ItemFilter) ParsedExpression() (FilterExpression, error) {
+	if f.Expression == "" {
+		return nil, nil
+	}
+	if compiled := f.compiledFilter(); compiled != nil && compiled.expression != nil {
+		return compiled.expression, nil
+	}
+	return ParseFilterExpression(f.Expression)
+}
+
+// compile replaces the compiled keywords and expression with those of the fields.
+// Nothing is kept for a filter without any, which has nothing to compile.
+func (f *ItemFilter) compile() {
+	if len(f.IncludeKeywords) == 0 && len(f.ExcludeKeywords) == 0 && f.Expression == "" {
+		f.compiled = nil
+		return
+	}
+
+	compiled := &compiledItemFilter{
+		includeKeywords: slices.Clone(f.IncludeKeywords),
+		excludeKeywords: slices.Clone(f.ExcludeKeywords),
+		expressionText:  f.Expression,
+		includeRegexps:  compileKeywords(f.IncludeKeywords),
+		excludeRegexps:  compileKeywords(f.ExcludeKeywords),
+	}
+	if f.Expression != "" {
+		compiled.expression, _ = ParseFilterExpression(f.Expression)
+	}
+	f.compiled = compiled
+}
+
+// compiledFilter returns what compile kept, or nil when nothing was kept or the
+// fields have been changed since.
+func (f *ItemFilter) compiledFilter() *compiledItemFilter {
+	compiled := f.compiled
+	if compiled == nil ||
+		!slices.Equal(compiled.includeKeywords, f.IncludeKeywords) ||
+		!slices.Equal(compiled.excludeKeywords, f.ExcludeKeywords) ||
+		compiled.expressionText != f.Expression {
+		return nil
+	}
+	return compiled
 }
 
 func (f *ItemFilter) GetIncludeKeywords() []string {
@@ -28,37 +140,76 @@ func (f *ItemFilter) GetExcludeKeywords() []string {
 	return f.ExcludeKeywords
 }
 
+// Reasons of a FilterVerdict.
+const (
+	// FilterReasonKept keeps an item. Pattern is the include keyword it matched,
+	// or empty when there is none.
+	FilterReasonKept = "kept"
+	// FilterReasonNotIncluded drops an item that matches none of the include keywords.
+	FilterReasonNotIncluded = "not_included"
+	// FilterReasonExcluded drops an item that matches Pattern, an exclude keyword.
+	FilterReasonExcluded = "excluded"
+	// FilterReasonExpression drops an item that does not match Pattern, the expression.
+	FilterReasonExpression = "expression"
+)
+
+// FilterVerdict tells whether an item is kept by an ItemFilter and why.
+type FilterVerdict struct {
+	Kept    bool
+	Reason  string
+	Pattern string
+}
+
+// IsMatch reports whether item matches the keywords and the expression. A keyword
+// that is not a valid regexp is skipped; CompileKeyword keeps one from being set
+// through the API.
 func (f *ItemFilter) IsMatch(item Item) bool {
+	return f.Explain(item).Kept
+}
+
+// Explain returns the verdict of IsMatch on item with the pattern that decided it.
+func (f *ItemFilter) Explain(item Item) FilterVerdict {
+	includeRegexps, excludeRegexps := f.keywordRegexps()
+
+	included := ""
 	if len(f.IncludeKeywords) > 0 {
 		matched := false
-		for _, pattern := range f.IncludeKeywords {
-			re, err := regexp.Compile(pattern)
-			if err != nil {
-				continue
-			}
-			if re.MatchString(item.Title) || re.MatchString(item.Description) {
-				matched = true
+		for i, re := range includeRegexps {
+			if matchKeyword(re, item) {
+				matched, included = true, f.IncludeKeywords[i]
 				break
 			}
 		}
 		if !matched {
-			return false
+			return FilterVerdict{Reason: FilterReasonNotIncluded}
 		}
 	}
 
-	if len(f.ExcludeKeywords) > 0 {
-		for _, pattern := range f.ExcludeKeywords {
-			re, err := regexp.Compile(pattern)
-			if err != nil {
-				continue
-			}
-			if re.MatchString(item.Title) || re.MatchString(item.Description) {
-				return false
-			}
+	for i, re := range excludeRegexps {
+		if matchKeyword(re, item) {
+			return FilterVerdict{Reason: FilterReasonExcluded, Pattern: f.ExcludeKeywords[i]}
 		}
 	}
 
-	return true
+	// Like an invalid keyword, an expression that cannot be parsed is skipped;
+	// SetExpression keeps one from being set in the first place.
+	if expression, err := f.ParsedExpression(); err == nil && expression != nil && !expression.Match(item) {
+		return FilterVerdict{Reason: FilterReasonExpression, Pattern: f.Expression}
+	}
+
+	return FilterVerdict{Kept: true, Reason: FilterReasonKept, Pattern: included}
+}
+
+// keywordRegexps returns the include and the exclude keywords compiled, in order.
+func (f *ItemFilter) keywordRegexps() (includeRegexps, excludeRegexps []*regexp.Regexp) {
+	if compiled := f.compiledFilter(); compiled != nil {
+		return compiled.includeRegexps, compiled.excludeRegexps
+	}
+	return compileKeywords(f.IncludeKeywords), compileKeywords(f.ExcludeKeywords)
+}
+
+func matchKeyword(re *regexp.Regexp, item Item) bool {
+	return re != nil && (re.MatchString(item.Title) || re.MatchString(item.Description))
 }
 
 func (f *ItemFilter) Equal(other ItemFilter) bool {
@@ -80,5 +231,5 @@ func (f *ItemFilter) Equal(other ItemFilter) bool {
 		}
 	}
 
-	return true
+	return f.Expression == other.Expression
 }
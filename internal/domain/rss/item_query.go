@@ -0,0 +1,29 @@
+package rss
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ItemQuery narrows down the items of a feed returned by FindItemsPage.
+// Zero values mean "no condition": a zero Since or Until leaves that side of the
+// PubDate range open, and empty Tags match every item.
+// Cursor is the NextCursor of a previous ItemPage and must be used with the same
+// conditions it was issued for.
+type ItemQuery struct {
+	Since     time.Time
+	Until     time.Time
+	Tags      []string
+	Ascending bool
+	Limit     int
+	Cursor    string
+}
+
+// ItemPage is a page of items ordered by PubDate.
+// NextCursor is empty when there are no more items.
+type ItemPage struct {
+	Items      []Item
+	NextCursor string
+}
@@ -0,0 +1,105 @@
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Limits of a keyword pattern. Go regexps match in linear time, but every pattern
+// is run against every item of every feed, so a long pattern, or one compiling to
+// a large program such as a big counted repetition, is turned away.
+const (
+	MaxKeywordLength      = 256
+	MaxKeywordProgramSize = 1000
+)
+
+// KeywordError tells why a keyword pattern is rejected. Position is the byte offset
+// in Pattern of the part at fault.
+type KeywordError struct {
+	Pattern  string
+	Position int
+	Message  string
+}
+
+func (e *KeywordError) Error() string {
+	return fmt.Sprintf("pattern %q at position %d: %s", e.Pattern, e.Position, e.Message)
+}
+
+// CompileKeyword compiles pattern, an include or exclude keyword of an ItemFilter,
+// and returns a *KeywordError when it is not a valid regexp or exceeds the limits.
+func CompileKeyword(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > MaxKeywordLength {
+		return nil, &KeywordError{Pattern: pattern, Position: MaxKeywordLength, Message: fmt.Sprintf("pattern is longer than %d bytes", MaxKeywordLength)}
+	}
+
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if !errors.As(err, &syntaxErr) {
+			return nil, &KeywordError{Pattern: pattern, Message: err.Error()}
+		}
+		position := strings.Index(pattern, syntaxErr.Expr)
+		if syntaxErr.Code == syntax.ErrMissingParen || syntaxErr.Code == syntax.ErrUnexpectedParen {
+			position = unbalancedParen(pattern)
+		}
+		if position < 0 {
+			position = 0
+		}
+		return nil, &KeywordError{Pattern: pattern, Position: position, Message: fmt.Sprintf("%s: `%s`", syntaxErr.Code, syntaxErr.Expr)}
+	}
+
+	program, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, &KeywordError{Pattern: pattern, Message: err.Error()}
+	}
+	if len(program.Inst) > MaxKeywordProgramSize {
+		return nil, &KeywordError{Pattern: pattern, Message: fmt.Sprintf("pattern is too complex: %d instructions, at most %d", len(program.Inst), MaxKeywordProgramSize)}
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// unbalancedParen returns the offset of the ")" without an opening parenthesis,
+// or else of the innermost "(" left open, or -1 when the parentheses balance.
+// Escaped characters and character classes are skipped.
+func unbalancedParen(pattern string) int {
+	var open []int
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			for i++; i < len(pattern) && pattern[i] != ']'; i++ {
+				if pattern[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			open = append(open, i)
+		case ')':
+			if len(open) == 0 {
+				return i
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) == 0 {
+		return -1
+	}
+	return open[len(open)-1]
+}
+
+// compileKeywords compiles patterns, a nil regexp standing for one that is not a
+// valid regexp.
+func compileKeywords(patterns []string) []*regexp.Regexp {
+	regexps := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			regexps[i] = re
+		}
+	}
+	return regexps
+}
@@ -0,0 +1,75 @@
+package rss
+
+import (
+	"sort"
+	"time"
+)
+
+// Retention limits how long and how many items of a feed are kept.
+// Zero values mean "no limit".
+type Retention struct {
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+	MaxItems   int `json:"max_items,omitempty"`
+}
+
+func NewRetention(maxAgeDays, maxItems int) Retention {
+	return Retention{MaxAgeDays: maxAgeDays, MaxItems: maxItems}
+}
+
+func (r Retention) maxAge() time.Duration {
+	return time.Duration(r.MaxAgeDays) * 24 * time.Hour
+}
+
+// ExpiresAt returns when item falls out of the max age, or the zero time when
+// there is no max age.
+func (r Retention) ExpiresAt(item Item) time.Time {
+	if r.MaxAgeDays <= 0 {
+		return time.Time{}
+	}
+	return item.PubDate.Add(r.maxAge())
+}
+
+func (r Retention) IsExpired(item Item, now time.Time) bool {
+	expiresAt := r.ExpiresAt(item)
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+// Overflow returns the items beyond MaxItems, keeping the newest ones by PubDate.
+func (r Retention) Overflow(items []Item) []Item {
+	if r.MaxItems <= 0 || len(items) <= r.MaxItems {
+		return nil
+	}
+
+	sorted := make([]Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PubDate.After(sorted[j].PubDate)
+	})
+	return sorted[r.MaxItems:]
+}
+
+func (r *Rss) SetRetention(retention Retention) {
+	r.Retention = retention
+}
+
+// ApplyRetention removes the items that the retention would not keep and returns
+// how many were removed. Dropping them before they are stored keeps old items from
+// being written again on every fetch only to be expired or pruned afterwards.
+func (r *Rss) ApplyRetention(now time.Time) int {
+	var kept []Item
+	removed := 0
+	for guid, item := range r.Items {
+		if r.Retention.IsExpired(item, now) {
+			delete(r.Items, guid)
+			removed++
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	for _, item := range r.Retention.Overflow(kept) {
+		delete(r.Items, item.Guid)
+		removed++
+	}
+	return removed
+}
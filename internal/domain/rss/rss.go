@@ -8,6 +8,13 @@ import (
 	"github.com/google/uuid"
 )
 
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusPaused Status = "paused"
+)
+
 type Rss struct {
 	ID            uuid.UUID         `json:"id"`
 	Source        string            `json:"source"`
@@ -18,6 +25,15 @@ type Rss struct {
 	LastBuildDate time.Time         `json:"last_build_date"`
 	Items         map[Guid]Item     `json:"items"`
 	ItemFilter    ItemFilter        `json:"item_filter"`
+	Retention     Retention         `json:"retention"`
+	Status        Status            `json:"status"`
+	ETag          string            `json:"etag,omitempty"`
+	LastModified  string            `json:"last_modified,omitempty"`
+	Parts         *FetchParts       `json:"parts,omitempty"`
+	FetchStatus   FetchStatus       `json:"-"` // stored apart from the feed, see IRssRepository.SaveFetchStatus
+	TrashedAt     time.Time         `json:"-"` // changed only through the API, see Trash
+	PurgeAt       time.Time         `json:"-"`
+	Version       int               `json:"-"` // incremented by every Save, see ConflictError
 	CreatedBy     metadata.CreateBy `json:"create_by"`
 	CreatedAt     metadata.CreateAt `json:"create_at"`
 	UpdatedBy     metadata.UpdateBy `json:"update_by"`
@@ -39,6 +55,7 @@ func New(title, source, link, description, language string, lastBuildDate time.T
 		LastBuildDate: lastBuildDate,
 		Items:         make(map[Guid]Item),
 		ItemFilter:    NewItemFilter(nil, nil),
+		Status:        StatusActive,
 	}, nil
 }
 
@@ -61,6 +78,37 @@ func (r *Rss) AddOrUpdateItem(item Item) {
 	}
 }
 
+// SetItemFilter replaces the keywords of the item filter, keeping its expression.
 func (r *Rss) SetItemFilter(includeKeywords, excludeKeywords []string) {
-	r.ItemFilter = NewItemFilter(includeKeywords, excludeKeywords)
+	itemFilter := NewItemFilter(includeKeywords, excludeKeywords)
+	itemFilter.Expression = r.ItemFilter.Expression
+	itemFilter.compile()
+	r.ItemFilter = itemFilter
+}
+
+// SetItemFilterExpression replaces the expression of the item filter, keeping its
+// keywords. It returns a *FilterExpressionError when expression cannot be parsed.
+func (r *Rss) SetItemFilterExpression(expression string) error {
+	return r.ItemFilter.SetExpression(expression)
+}
+
+// SetCacheValidators stores the ETag and Last-Modified response headers of the feed.
+// They are sent back as If-None-Match and If-Modified-Since on the next fetch.
+func (r *Rss) SetCacheValidators(etag, lastModified string) {
+	r.ETag = etag
+	r.LastModified = lastModified
+}
+
+// Pause stops the feed from being polled and announced until Resume is called.
+// Stored items are kept as they are.
+func (r *Rss) Pause() {
+	r.Status = StatusPaused
+}
+
+func (r *Rss) Resume() {
+	r.Status = StatusActive
+}
+
+func (r Rss) IsPaused() bool {
+	return r.Status == StatusPaused
 }
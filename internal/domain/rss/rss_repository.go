@@ -2,7 +2,12 @@ package rss
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
@@ -14,6 +19,9 @@ import (
 	"github.com/google/uuid"
 )
 
+const fetchStatusSortKey = "fetch_status"
+const refreshSortKey = "refresh"
+
 type rssManager struct {
 	rss   rssModel
 	items []itemModel
@@ -31,6 +39,14 @@ type rssModel struct {
 	Language      string            `dynamodbav:"language"`
 	LastBuildDate int64             `dynamodbav:"last_build_date"`
 	ItemFilter    itemFilterModel   `dynamodbav:"item_filter"`
+	Retention     retentionModel    `dynamodbav:"retention"`
+	Status        string            `dynamodbav:"status"`
+	ETag          string            `dynamodbav:"etag"`
+	LastModified  string            `dynamodbav:"last_modified"`
+	Parts         *fetchPartsModel  `dynamodbav:"parts,omitempty"`
+	TrashedAt     int64             `dynamodbav:"trashed_at"`
+	PurgeAt       int64             `dynamodbav:"purge_at"` // no TTL, see buildRssManager
+	Version       int               `dynamodbav:"version"`
 	CreatedBy     metadata.CreateBy `dynamodbav:"create_by"`
 	CreatedAt     int64             `dynamodbav:"create_at"`
 	UpdatedBy     metadata.UpdateBy `dynamodbav:"update_by"`
@@ -38,21 +54,90 @@ type rssModel struct {
 }
 
 type itemModel struct {
-	PartitionKey string   `dynamodbav:"id"`
-	SortKey      string   `dynamodbav:"sortKey"`
-	RssId        string   `dynamodbav:"rss_id"`
-	GuId         string   `dynamodbav:"guid"`
-	Title        string   `dynamodbav:"title"`
-	Link         string   `dynamodbav:"link"`
-	Description  string   `dynamodbav:"description"`
-	Author       string   `dynamodbav:"author"`
-	PubDate      int64    `dynamodbav:"pub_date"`
-	Tags         []string `dynamodbav:"tags"`
+	PartitionKey string              `dynamodbav:"id"`
+	SortKey      string              `dynamodbav:"sortKey"`
+	RssId        string              `dynamodbav:"rss_id"`
+	GuId         string              `dynamodbav:"guid"`
+	Title        string              `dynamodbav:"title"`
+	Link         string              `dynamodbav:"link"`
+	Description  string              `dynamodbav:"description"`
+	Author       string              `dynamodbav:"author"`
+	PubDate      int64               `dynamodbav:"pub_date"`
+	Tags         []string            `dynamodbav:"tags"`
+	ContentHash  string              `dynamodbav:"content_hash"`
+	Revisions    []itemRevisionModel `dynamodbav:"revisions"`
+	ExpireAt     int64               `dynamodbav:"expire_at,omitempty"` // TTL attribute, see Retention
+}
+
+type itemRevisionModel struct {
+	Title       string `dynamodbav:"title" json:"title"`
+	Link        string `dynamodbav:"link" json:"link"`
+	Description string `dynamodbav:"description" json:"description"`
+	ContentHash string `dynamodbav:"content_hash" json:"content_hash"`
+	RevisedAt   int64  `dynamodbav:"revised_at" json:"revised_at"`
+}
+
+// fetchStatusModel is stored in its own row so that the frequent status updates
+// neither overwrite nor emit stream events for the rss row.
+type fetchStatusModel struct {
+	PartitionKey        string `dynamodbav:"id"`
+	SortKey             string `dynamodbav:"sortKey"`
+	RssId               string `dynamodbav:"rss_id"`
+	LastSuccessAt       int64  `dynamodbav:"last_success_at"`
+	LastFailureAt       int64  `dynamodbav:"last_failure_at"`
+	LastError           string `dynamodbav:"last_error"`
+	ConsecutiveFailures int    `dynamodbav:"consecutive_failures"`
+	HTTPStatus          int    `dynamodbav:"http_status"`
+	DisabledAt          int64  `dynamodbav:"disabled_at"`
+}
+
+// refreshModel records the last on-demand refresh of a feed for rate limiting.
+// It expires by TTL once the rate limit interval has passed.
+type refreshModel struct {
+	PartitionKey string `dynamodbav:"id"`
+	SortKey      string `dynamodbav:"sortKey"`
+	RssId        string `dynamodbav:"rss_id"`
+	RequestedAt  int64  `dynamodbav:"requested_at"`
+	ExpireAt     int64  `dynamodbav:"expire_at"`
+}
+
+type retentionModel struct {
+	MaxAgeDays int `dynamodbav:"max_age_days"`
+	MaxItems   int `dynamodbav:"max_items"`
+}
+
+type fetchPartsModel struct {
+	FetchID          string `dynamodbav:"fetch_id" json:"fetch_id"`
+	Parts            int    `dynamodbav:"parts" json:"parts"`
+	Written          []int  `dynamodbav:"written" json:"written"`
+	NewFeed          bool   `dynamodbav:"new_feed" json:"new_feed"`
+	CompletedVersion int    `dynamodbav:"completed_version" json:"completed_version"`
 }
 
 type itemFilterModel struct {
-	IncludeKeywords []string `dynamodbav:"include_keywords"`
-	ExcludeKeywords []string `dynamodbav:"exclude_keywords"`
+	IncludeKeywords []string `dynamodbav:"include_keywords" json:"include_keywords"`
+	ExcludeKeywords []string `dynamodbav:"exclude_keywords" json:"exclude_keywords"`
+	Expression      string   `dynamodbav:"expression,omitempty" json:"expression,omitempty"`
+}
+
+// buildItemFilter compiles the filter, since clean matches the items of a fetch
+// against the stored one.
+func buildItemFilter(model itemFilterModel) ItemFilter {
+	itemFilter := ItemFilter{
+		IncludeKeywords: model.IncludeKeywords,
+		ExcludeKeywords: model.ExcludeKeywords,
+		Expression:      model.Expression,
+	}
+	itemFilter.compile()
+	return itemFilter
+}
+
+func buildItemFilterModel(itemFilter ItemFilter) itemFilterModel {
+	return itemFilterModel{
+		IncludeKeywords: itemFilter.IncludeKeywords,
+		ExcludeKeywords: itemFilter.ExcludeKeywords,
+		Expression:      itemFilter.Expression,
+	}
 }
 
 func (r *rssModel) NewItemModel(item Item) itemModel {
@@ -67,24 +152,51 @@ func (r *rssModel) NewItemModel(item Item) itemModel {
 		Author:       item.Author,
 		PubDate:      item.PubDate.Unix(),
 		Tags:         item.Tags,
+		ContentHash:  item.ContentHash,
+		Revisions:    buildItemRevisionModels(item.Revisions),
+		ExpireAt:     timeToUnix(Retention(r.Retention).ExpiresAt(item)),
+	}
+}
+
+func buildItemRevisionModels(revisions []ItemRevision) []itemRevisionModel {
+	var models []itemRevisionModel
+	for _, revision := range revisions {
+		models = append(models, itemRevisionModel{
+			Title:       revision.Title,
+			Link:        revision.Link,
+			Description: revision.Description,
+			ContentHash: revision.ContentHash,
+			RevisedAt:   revision.RevisedAt.Unix(),
+		})
 	}
+	return models
 }
 
 type IRssRepository interface {
 	FindBySource(ctx context.Context, source string) (Rss, error)
 	FindAll(ctx context.Context) ([]Rss, error)
+	FindAllPages(ctx context.Context, fn func(rssFeeds []Rss) bool) error
 	FindItems(ctx context.Context, rss Rss) (Rss, error)
+	FindItemsPages(ctx context.Context, rss Rss, fn func(items []Item) bool) error
 	FindItemsByPk(ctx context.Context, rss Rss, guid Guid) (Rss, error)
+	FindItemsPage(ctx context.Context, rss Rss, query ItemQuery) (ItemPage, error)
 	Save(ctx context.Context, rss Rss, updateBy metadata.UserMeta) (Rss, error)
+	SaveFetchStatus(ctx context.Context, rss Rss, status FetchStatus) error
+	ReserveRefresh(ctx context.Context, rss Rss, now time.Time, minInterval time.Duration) (reserved bool, err error)
 	Delete(ctx context.Context, rss Rss) error
+	DeleteItems(ctx context.Context, rss Rss, guids []Guid) error
 }
 
 type DynamoDBRssRepository struct {
 	dynamoDBStore infrastructure.DynamoDBStore
 }
 
-func NewDynamoDBRssRepository(client *dynamodb.Client) *DynamoDBRssRepository {
-	return &DynamoDBRssRepository{dynamoDBStore: *infrastructure.NewDynamoDBStore(client, "Rss")}
+func NewDynamoDBRssRepository(client *dynamodb.Client, logger infrastructure.Logger) *DynamoDBRssRepository {
+	return NewDynamoDBRssRepositoryWithRetryPolicy(client, infrastructure.DefaultRetryPolicy(), logger)
+}
+
+func NewDynamoDBRssRepositoryWithRetryPolicy(client *dynamodb.Client, retryPolicy infrastructure.RetryPolicy, logger infrastructure.Logger) *DynamoDBRssRepository {
+	return &DynamoDBRssRepository{dynamoDBStore: *infrastructure.NewDynamoDBStore(client, "Rss", retryPolicy, logger)}
 }
 
 // FindBySource retrieves an Rss instance from the repository based on the given source.
@@ -112,33 +224,64 @@ func (r *DynamoDBRssRepository) FindBySource(ctx context.Context, source string)
 		items: []itemModel{},
 	}
 
-	return buildRss(manager), nil
+	rss := buildRss(manager)
+	if rss.ID == uuid.Nil {
+		return rss, nil
+	}
+
+	rss.FetchStatus, err = r.getFetchStatus(ctx, source)
+	if err != nil {
+		return Rss{}, err
+	}
+	return rss, nil
 }
 
 func (r *DynamoDBRssRepository) FindAll(ctx context.Context) ([]Rss, error) {
-	results, err := r.dynamoDBStore.QueryItemsBySortKey(ctx, "rss")
+	var rssFeeds []Rss
+	err := r.FindAllPages(ctx, func(page []Rss) bool {
+		rssFeeds = append(rssFeeds, page...)
+		return true
+	})
 	if err != nil {
 		return []Rss{}, err
 	}
 
-	var rssFeeds []Rss
-	for _, item := range results.Items {
-		var rssModel rssModel
-		err = attributevalue.UnmarshalMap(item, &rssModel)
-		if err != nil {
-			return []Rss{}, err
-		}
+	return rssFeeds, nil
+}
+
+// FindAllPages calls fn with each page of the stored Rss feeds.
+// Iteration stops when fn returns false or all feeds have been read.
+// As with FindBySource, `Item` data is not returned.
+func (r *DynamoDBRssRepository) FindAllPages(ctx context.Context, fn func(rssFeeds []Rss) bool) error {
+	fetchStatuses, err := r.getAllFetchStatuses(ctx)
+	if err != nil {
+		return err
+	}
 
-		manager := rssManager{
-			rss:   rssModel,
-			items: []itemModel{},
+	var unmarshalErr error
+	err = r.dynamoDBStore.QueryItemsBySortKeyPages(ctx, "rss", func(page *dynamodb.QueryOutput) bool {
+		var rssModels []rssModel
+		unmarshalErr = attributevalue.UnmarshalListOfMaps(page.Items, &rssModels)
+		if unmarshalErr != nil {
+			return false
 		}
 
-		rss := buildRss(manager)
-		rssFeeds = append(rssFeeds, rss)
+		rssFeeds := make([]Rss, 0, len(rssModels))
+		for _, model := range rssModels {
+			manager := rssManager{
+				rss:   model,
+				items: []itemModel{},
+			}
+			rss := buildRss(manager)
+			rss.FetchStatus = fetchStatuses[model.PartitionKey]
+			rssFeeds = append(rssFeeds, rss)
+		}
+		return fn(rssFeeds)
+	})
+	if err != nil {
+		return err
 	}
-
-	return rssFeeds, nil
+	return unmarshalErr
 }
 
 func (r *DynamoDBRssRepository) FindItems(ctx context.Context, rss Rss) (Rss, error) {
@@ -147,7 +290,11 @@ func (r *DynamoDBRssRepository) FindItems(ctx context.Context, rss Rss) (Rss, er
 	}
 
 	manager := buildRssManager(rss)
-	itemModels, err := r.getItemModels(ctx, manager.rss.PartitionKey, manager.rss.RssId)
+	var itemModels []itemModel
+	err := r.eachItemModelPage(ctx, manager.rss.PartitionKey, manager.rss.RssId, func(page []itemModel) bool {
+		itemModels = append(itemModels, page...)
+		return true
+	})
 	if err != nil {
 		return Rss{}, err
 	}
@@ -160,6 +307,23 @@ func (r *DynamoDBRssRepository) FindItems(ctx context.Context, rss Rss) (Rss, er
 	return buildRss(finalManager), nil
 }
 
+// FindItemsPages calls fn with each page of the items of rss.
+// Iteration stops when fn returns false or all items have been read.
+func (r *DynamoDBRssRepository) FindItemsPages(ctx context.Context, rss Rss, fn func(items []Item) bool) error {
+	if rss.Source == "" {
+		return errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	return r.eachItemModelPage(ctx, manager.rss.PartitionKey, manager.rss.RssId, func(page []itemModel) bool {
+		items := make([]Item, 0, len(page))
+		for _, model := range page {
+			items = append(items, buildItem(model))
+		}
+		return fn(items)
+	})
+}
+
 func (r *DynamoDBRssRepository) FindItemsByPk(ctx context.Context, rss Rss, guid Guid) (Rss, error) {
 	if rss.ID.String() == "" || guid.Value == "" {
 		return Rss{}, errors.New("invalid source")
@@ -188,6 +352,140 @@ func (r *DynamoDBRssRepository) FindItemsByPk(ctx context.Context, rss Rss, guid
 	return buildRss(finalManager), nil
 }
 
+// FindItemsPage returns one page of the items of rss ordered by PubDate.
+// Pages are read from the RssIdPubDateIndex and the tag conditions are applied as a
+// filter expression, so several queries may be issued to fill a single page.
+func (r *DynamoDBRssRepository) FindItemsPage(ctx context.Context, rss Rss, query ItemQuery) (ItemPage, error) {
+	if rss.ID == uuid.Nil {
+		return ItemPage{}, errors.New("invalid rss ID")
+	}
+	if query.Limit <= 0 {
+		return ItemPage{}, errors.New("invalid limit")
+	}
+
+	rssId := rss.ID.String()
+	startKey, err := decodeItemCursor(query.Cursor, rssId)
+	if err != nil {
+		return ItemPage{}, err
+	}
+
+	pageQuery := buildItemPageQuery(rssId, query)
+	page := ItemPage{Items: []Item{}}
+	for {
+		pageQuery.ExclusiveStartKey = startKey
+		pageQuery.Limit = int32(query.Limit - len(page.Items))
+
+		result, err := r.dynamoDBStore.QueryPage(ctx, pageQuery)
+		if err != nil {
+			return ItemPage{}, err
+		}
+
+		var models []itemModel
+		err = attributevalue.UnmarshalListOfMaps(result.Items, &models)
+		if err != nil {
+			return ItemPage{}, err
+		}
+		for _, model := range models {
+			page.Items = append(page.Items, buildItem(model))
+		}
+
+		startKey = result.LastEvaluatedKey
+		if len(startKey) == 0 || len(page.Items) >= query.Limit {
+			break
+		}
+	}
+
+	page.NextCursor, err = encodeItemCursor(startKey)
+	if err != nil {
+		return ItemPage{}, err
+	}
+	return page, nil
+}
+
+func buildItemPageQuery(rssId string, query ItemQuery) infrastructure.PageQuery {
+	keyCondition := "rss_id = :rssId"
+	values := map[string]types.AttributeValue{
+		":rssId": &types.AttributeValueMemberS{Value: rssId},
+	}
+
+	switch {
+	case !query.Since.IsZero() && !query.Until.IsZero():
+		keyCondition += " AND pub_date BETWEEN :since AND :until"
+	case !query.Since.IsZero():
+		keyCondition += " AND pub_date >= :since"
+	case !query.Until.IsZero():
+		keyCondition += " AND pub_date <= :until"
+	}
+	if !query.Since.IsZero() {
+		values[":since"] = &types.AttributeValueMemberN{Value: fmt.Sprint(query.Since.Unix())}
+	}
+	if !query.Until.IsZero() {
+		values[":until"] = &types.AttributeValueMemberN{Value: fmt.Sprint(query.Until.Unix())}
+	}
+
+	var tagConditions []string
+	for i, tag := range query.Tags {
+		placeholder := fmt.Sprintf(":tag%d", i)
+		tagConditions = append(tagConditions, fmt.Sprintf("contains(tags, %s)", placeholder))
+		values[placeholder] = &types.AttributeValueMemberS{Value: tag}
+	}
+
+	return infrastructure.PageQuery{
+		IndexName:                 "RssIdPubDateIndex",
+		KeyConditionExpression:    keyCondition,
+		FilterExpression:          strings.Join(tagConditions, " AND "),
+		ExpressionAttributeValues: values,
+		ScanIndexForward:          query.Ascending,
+	}
+}
+
+// itemCursor is the LastEvaluatedKey of the RssIdPubDateIndex, which consists of the
+// table keys and the index keys.
+type itemCursor struct {
+	PartitionKey string `dynamodbav:"id" json:"id"`
+	SortKey      string `dynamodbav:"sortKey" json:"sort_key"`
+	RssId        string `dynamodbav:"rss_id" json:"rss_id"`
+	PubDate      int64  `dynamodbav:"pub_date" json:"pub_date"`
+}
+
+func encodeItemCursor(lastEvaluatedKey map[string]types.AttributeValue) (string, error) {
+	if len(lastEvaluatedKey) == 0 {
+		return "", nil
+	}
+
+	var cursor itemCursor
+	if err := attributevalue.UnmarshalMap(lastEvaluatedKey, &cursor); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeItemCursor(value string, rssId string) (map[string]types.AttributeValue, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor itemCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.RssId != rssId || cursor.PartitionKey == "" || cursor.SortKey == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return attributevalue.MarshalMap(cursor)
+}
+
 func (r *DynamoDBRssRepository) getItemModel(ctx context.Context, partitionKey string, sortKey string) (itemModel, error) {
 	result, err := r.dynamoDBStore.GetItemById(ctx, partitionKey, sortKey)
 	if err != nil {
@@ -203,21 +501,24 @@ func (r *DynamoDBRssRepository) getItemModel(ctx context.Context, partitionKey s
 	return model, nil
 }
 
-func (r *DynamoDBRssRepository) getItemModels(ctx context.Context, source string, rssId string) ([]itemModel, error) {
-	result, err := r.dynamoDBStore.QueryItemsBySortPrefix(ctx, source, rssId)
-	if err != nil {
-		return nil, err
-	}
-
-	var model []itemModel
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &model)
+func (r *DynamoDBRssRepository) eachItemModelPage(ctx context.Context, source string, rssId string, fn func(page []itemModel) bool) error {
+	var unmarshalErr error
+	err := r.dynamoDBStore.QueryItemsBySortPrefixPages(ctx, source, rssId, func(page *dynamodb.QueryOutput) bool {
+		var models []itemModel
+		unmarshalErr = attributevalue.UnmarshalListOfMaps(page.Items, &models)
+		if unmarshalErr != nil {
+			return false
+		}
+		return fn(models)
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	return model, nil
+	return unmarshalErr
 }
 
+// Save stores rss and returns it with its new Version.
+// It returns a *ConflictError when the stored rss is no longer at rss.Version.
 func (r *DynamoDBRssRepository) Save(ctx context.Context, rss Rss, updateBy metadata.UserMeta) (Rss, error) {
 
 	if rss.ID == uuid.Nil {
@@ -235,24 +536,74 @@ func (r *DynamoDBRssRepository) Save(ctx context.Context, rss Rss, updateBy meta
 
 	rssManager := buildRssManager(rss)
 
-	// rss
-	err := r.dynamoDBStore.PutItem(ctx, rssManager.rss)
-	if err != nil {
-		return rss, err
+	// The rss row is written only when nobody has saved it since it was read.
+	// Rows saved before the version was introduced have none and count as version 0.
+	conditionExpression := "version = :version"
+	if rss.Version == 0 {
+		conditionExpression = "attribute_not_exists(version) OR version = :version"
 	}
+	expressionAttributeValues := map[string]types.AttributeValue{
+		":version": &types.AttributeValueMemberN{Value: strconv.Itoa(rss.Version)},
+	}
+	rssManager.rss.Version = rss.Version + 1
 
-	// item
-	for _, item := range rssManager.items {
-		err = r.dynamoDBStore.PutItem(ctx, item)
-		if err != nil {
-			return rss, err
+	items := make([]interface{}, len(rssManager.items))
+	for i, item := range rssManager.items {
+		items[i] = item
+	}
+
+	var err error
+	if len(items) <= transactItemsLimit {
+		// The items and the rss row are written together, so a conflict leaves nothing behind.
+		err = r.dynamoDBStore.TransactPutItemsWithCondition(ctx, items, rssManager.rss, conditionExpression, expressionAttributeValues)
+	} else {
+		err = r.saveInBatches(ctx, rss, items, rssManager.rss, conditionExpression, expressionAttributeValues)
+	}
+	if err != nil {
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) {
+			return rss, &ConflictError{Source: rss.Source, Version: rss.Version}
 		}
+		return rss, err
 	}
 
+	rss.Version = rssManager.rss.Version
 	return rss, nil
 }
 
-func (r *DynamoDBRssRepository) Delete(ctx context.Context, rss Rss) error {
+// transactItemsLimit is the number of items up to which Save writes the items of a
+// feed in one transaction with its rss row, short of the 100 items and 4 MB a
+// transaction may hold.
+const transactItemsLimit = 25
+
+// saveInBatches writes the items of a feed too large for a transaction before its
+// rss row, so a failure part way through leaves the previous rss row in place and
+// readers never see a half-saved feed. The version is checked before the items are
+// written, so a stale save writes nothing; a save that loses a race after the check
+// still leaves its items stored under the rss row of the winner. Items are keyed by
+// their guid, so the retry after the ConflictError writes them again in place.
+func (r *DynamoDBRssRepository) saveInBatches(ctx context.Context, rss Rss, items []interface{}, row rssModel, conditionExpression string, expressionAttributeValues map[string]types.AttributeValue) error {
+	result, err := r.dynamoDBStore.GetItemById(ctx, rss.Source, "rss")
+	if err != nil {
+		return err
+	}
+	var stored rssModel
+	if err := attributevalue.UnmarshalMap(result.Item, &stored); err != nil {
+		return err
+	}
+	if stored.Version != rss.Version {
+		return &ConflictError{Source: rss.Source, Version: rss.Version}
+	}
+
+	if err := r.dynamoDBStore.BatchPutItems(ctx, items); err != nil {
+		return err
+	}
+	return r.dynamoDBStore.PutItemWithCondition(ctx, row, conditionExpression, expressionAttributeValues)
+}
+
+// SaveFetchStatus stores the fetch status of rss.
+// The status is kept apart from the rss row, so Save never overwrites it.
+func (r *DynamoDBRssRepository) SaveFetchStatus(ctx context.Context, rss Rss, status FetchStatus) error {
 	if rss.ID == uuid.Nil {
 		return errors.New("invalid rss ID")
 	}
@@ -260,25 +611,145 @@ func (r *DynamoDBRssRepository) Delete(ctx context.Context, rss Rss) error {
 		return errors.New("invalid the Source")
 	}
 
-	manager := buildRssManager(rss)
-	itemModels, err := r.getItemModels(ctx, manager.rss.PartitionKey, manager.rss.RssId)
+	return r.dynamoDBStore.PutItem(ctx, buildFetchStatusModel(rss, status))
+}
+
+// ReserveRefresh records an on-demand refresh of rss at now unless another refresh
+// was reserved within minInterval. It reports false when the refresh is rate limited.
+// The check and the write are a single conditional put, so concurrent requests
+// cannot both be reserved.
+func (r *DynamoDBRssRepository) ReserveRefresh(ctx context.Context, rss Rss, now time.Time, minInterval time.Duration) (bool, error) {
+	if rss.ID == uuid.Nil {
+		return false, errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return false, errors.New("invalid the Source")
+	}
+
+	model := refreshModel{
+		PartitionKey: rss.Source,
+		SortKey:      refreshSortKey,
+		RssId:        rss.ID.String(),
+		RequestedAt:  now.Unix(),
+		ExpireAt:     now.Add(minInterval).Unix(),
+	}
+	err := r.dynamoDBStore.PutItemWithCondition(ctx, model,
+		"attribute_not_exists(id) OR requested_at <= :threshold",
+		map[string]types.AttributeValue{
+			":threshold": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(-minInterval).Unix(), 10)},
+		})
 	if err != nil {
-		return err
+		var conditionalCheckFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckFailed) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *DynamoDBRssRepository) getFetchStatus(ctx context.Context, source string) (FetchStatus, error) {
+	result, err := r.dynamoDBStore.GetItemById(ctx, source, fetchStatusSortKey)
+	if err != nil {
+		return FetchStatus{}, err
+	}
+
+	var model fetchStatusModel
+	err = attributevalue.UnmarshalMap(result.Item, &model)
+	if err != nil {
+		return FetchStatus{}, err
+	}
+
+	return buildFetchStatus(model), nil
+}
+
+func (r *DynamoDBRssRepository) getAllFetchStatuses(ctx context.Context) (map[string]FetchStatus, error) {
+	fetchStatuses := make(map[string]FetchStatus)
+
+	var unmarshalErr error
+	err := r.dynamoDBStore.QueryItemsBySortKeyPages(ctx, fetchStatusSortKey, func(page *dynamodb.QueryOutput) bool {
+		var models []fetchStatusModel
+		unmarshalErr = attributevalue.UnmarshalListOfMaps(page.Items, &models)
+		if unmarshalErr != nil {
+			return false
+		}
+		for _, model := range models {
+			fetchStatuses[model.PartitionKey] = buildFetchStatus(model)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return fetchStatuses, nil
+}
+
+// DeleteItems deletes the items of rss identified by guids. The rss row is kept.
+func (r *DynamoDBRssRepository) DeleteItems(ctx context.Context, rss Rss, guids []Guid) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
 	}
 
+	manager := buildRssManager(rss)
 	var deleteInputs []dynamodb.DeleteItemInput
-	for _, item := range itemModels {
-		//r.dynamoDBStore.DeleteItem(ctx, item.PartitionKey, item.SortKey)
+	for _, guid := range guids {
 		deleteInputs = append(deleteInputs, dynamodb.DeleteItemInput{
 			TableName: aws.String(r.dynamoDBStore.TableName),
 			Key: map[string]types.AttributeValue{
-				"id":      &types.AttributeValueMemberS{Value: item.PartitionKey},
-				"sortKey": &types.AttributeValueMemberS{Value: item.SortKey},
+				"id":      &types.AttributeValueMemberS{Value: manager.rss.PartitionKey},
+				"sortKey": &types.AttributeValueMemberS{Value: manager.rss.RssId + "#" + guid.Value},
 			},
 		})
 	}
 
-	err = r.dynamoDBStore.BatchDeleteItems(ctx, deleteInputs)
+	return r.dynamoDBStore.BatchDeleteItems(ctx, deleteInputs)
+}
+
+func (r *DynamoDBRssRepository) Delete(ctx context.Context, rss Rss) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	manager := buildRssManager(rss)
+	var deleteErr error
+	err := r.eachItemModelPage(ctx, manager.rss.PartitionKey, manager.rss.RssId, func(page []itemModel) bool {
+		var deleteInputs []dynamodb.DeleteItemInput
+		for _, item := range page {
+			deleteInputs = append(deleteInputs, dynamodb.DeleteItemInput{
+				TableName: aws.String(r.dynamoDBStore.TableName),
+				Key: map[string]types.AttributeValue{
+					"id":      &types.AttributeValueMemberS{Value: item.PartitionKey},
+					"sortKey": &types.AttributeValueMemberS{Value: item.SortKey},
+				},
+			})
+		}
+
+		deleteErr = r.dynamoDBStore.BatchDeleteItems(ctx, deleteInputs)
+		return deleteErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	if deleteErr != nil {
+		return deleteErr
+	}
+
+	_, err = r.dynamoDBStore.DeleteItem(ctx, manager.rss.PartitionKey, fetchStatusSortKey)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.dynamoDBStore.DeleteItem(ctx, manager.rss.PartitionKey, refreshSortKey)
 	if err != nil {
 		return err
 	}
@@ -301,15 +772,7 @@ func buildRss(manager rssManager) Rss {
 	itemsMap := make(map[Guid]Item)
 
 	for _, item := range manager.items {
-		itemsMap[Guid{Value: item.GuId}] = Item{
-			Guid:        Guid{Value: item.GuId},
-			Title:       item.Title,
-			Link:        item.Link,
-			Description: item.Description,
-			Author:      item.Author,
-			PubDate:     time.Unix(item.PubDate, 0).UTC(),
-			Tags:        item.Tags,
-		}
+		itemsMap[Guid{Value: item.GuId}] = buildItem(item)
 	}
 
 	rss := Rss{
@@ -320,7 +783,15 @@ func buildRss(manager rssManager) Rss {
 		Description:   manager.rss.Description,
 		Language:      manager.rss.Language,
 		LastBuildDate: time.Unix(manager.rss.LastBuildDate, 0),
-		ItemFilter:    ItemFilter(manager.rss.ItemFilter),
+		ItemFilter:    buildItemFilter(manager.rss.ItemFilter),
+		Retention:     Retention(manager.rss.Retention),
+		Status:        buildStatus(manager.rss.Status),
+		ETag:          manager.rss.ETag,
+		LastModified:  manager.rss.LastModified,
+		Parts:         (*FetchParts)(manager.rss.Parts),
+		TrashedAt:     unixToTime(manager.rss.TrashedAt),
+		PurgeAt:       unixToTime(manager.rss.PurgeAt),
+		Version:       manager.rss.Version,
 		Items:         itemsMap,
 		CreatedBy:     manager.rss.CreatedBy,
 		CreatedAt:     time.Unix(manager.rss.CreatedAt, 0).UTC(),
@@ -330,8 +801,86 @@ func buildRss(manager rssManager) Rss {
 	return rss
 }
 
+// buildStatus treats rows written before the status was introduced as active.
+func buildStatus(status string) Status {
+	if status == "" {
+		return StatusActive
+	}
+	return Status(status)
+}
+
+func buildItem(item itemModel) Item {
+	return Item{
+		Guid:        Guid{Value: item.GuId},
+		Title:       item.Title,
+		Link:        item.Link,
+		Description: item.Description,
+		Author:      item.Author,
+		PubDate:     time.Unix(item.PubDate, 0).UTC(),
+		Tags:        item.Tags,
+		ContentHash: item.ContentHash,
+		Revisions:   buildItemRevisions(item.Revisions),
+	}
+}
+
+func buildItemRevisions(models []itemRevisionModel) []ItemRevision {
+	var revisions []ItemRevision
+	for _, model := range models {
+		revisions = append(revisions, ItemRevision{
+			Title:       model.Title,
+			Link:        model.Link,
+			Description: model.Description,
+			ContentHash: model.ContentHash,
+			RevisedAt:   time.Unix(model.RevisedAt, 0).UTC(),
+		})
+	}
+	return revisions
+}
+
+func buildFetchStatus(model fetchStatusModel) FetchStatus {
+	return FetchStatus{
+		LastSuccessAt:       unixToTime(model.LastSuccessAt),
+		LastFailureAt:       unixToTime(model.LastFailureAt),
+		LastError:           model.LastError,
+		ConsecutiveFailures: model.ConsecutiveFailures,
+		HTTPStatus:          model.HTTPStatus,
+		DisabledAt:          unixToTime(model.DisabledAt),
+	}
+}
+
+func buildFetchStatusModel(rss Rss, status FetchStatus) fetchStatusModel {
+	return fetchStatusModel{
+		PartitionKey:        rss.Source,
+		SortKey:             fetchStatusSortKey,
+		RssId:               rss.ID.String(),
+		LastSuccessAt:       timeToUnix(status.LastSuccessAt),
+		LastFailureAt:       timeToUnix(status.LastFailureAt),
+		LastError:           status.LastError,
+		ConsecutiveFailures: status.ConsecutiveFailures,
+		HTTPStatus:          status.HTTPStatus,
+		DisabledAt:          timeToUnix(status.DisabledAt),
+	}
+}
+
+func unixToTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+func timeToUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
 func buildRssManager(rss Rss) rssManager {
 
+	// A trashed rss row gets no TTL: it would expire apart from its items and leave
+	// them with no row to find them from. The purge job deletes them together and
+	// retries the feeds it fails on at its next run.
 	rssModel := rssModel{
 		PartitionKey:  rss.Source,
 		SortKey:       "rss",
@@ -342,7 +891,15 @@ func buildRssManager(rss Rss) rssManager {
 		Description:   rss.Description,
 		Language:      rss.Language,
 		LastBuildDate: rss.LastBuildDate.Unix(),
-		ItemFilter:    itemFilterModel(rss.ItemFilter),
+		ItemFilter:    buildItemFilterModel(rss.ItemFilter),
+		Retention:     retentionModel(rss.Retention),
+		Status:        string(buildStatus(string(rss.Status))),
+		ETag:          rss.ETag,
+		LastModified:  rss.LastModified,
+		Parts:         (*fetchPartsModel)(rss.Parts),
+		TrashedAt:     timeToUnix(rss.TrashedAt),
+		PurgeAt:       timeToUnix(rss.PurgeAt),
+		Version:       rss.Version,
 		CreatedBy:     rss.CreatedBy,
 		CreatedAt:     rss.CreatedAt.Unix(),
 		UpdatedBy:     rss.UpdatedBy,
@@ -3,16 +3,29 @@ package rss
 import (
 	"context"
 
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/google/uuid"
 )
 
-func Exists(ctx context.Context, repo IRssRepository, rss Rss) (bool, Rss) {
+// FetchUser returns the user a fetch of source is saved as. The other saves, by the
+// API and the migration, change only the settings or the status of a feed.
+func FetchUser(source string) metadata.UserMeta {
+	return metadata.UserMeta{ID: source, Name: source}
+}
+
+// Exists reports whether a feed is stored under the source of rss and returns it.
+// An error of the repository is returned as is rather than taken for a missing feed,
+// so that the caller does not go on to treat a stored feed as a new one.
+func Exists(ctx context.Context, repo IRssRepository, rss Rss) (bool, Rss, error) {
 	targetRss, err := repo.FindBySource(ctx, rss.Source)
+	if err != nil {
+		return false, Rss{}, err
+	}
 
-	if err == nil && targetRss.ID != uuid.Nil {
-		return true, targetRss
+	if targetRss.ID != uuid.Nil {
+		return true, targetRss, nil
 	}
-	return false, Rss{}
+	return false, Rss{}, nil
 }
 
 func GetItems(ctx context.Context, repo IRssRepository, rss Rss) (Rss, error) {
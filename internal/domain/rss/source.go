@@ -0,0 +1,68 @@
+package rss
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+const sourceHashLength = 12
+
+// NewSource derives the stable identifier of a feed from its URL.
+// The identifier keeps the host name for readability and appends a hash of the
+// normalized URL, so several feeds served by the same host never share a source.
+func NewSource(feedURL string) (string, error) {
+	normalizedURL, err := NormalizeFeedURL(feedURL)
+	if err != nil {
+		return "", err
+	}
+
+	parsedURL, err := url.Parse(normalizedURL)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(normalizedURL))
+	return parsedURL.Hostname() + "-" + hex.EncodeToString(sum[:])[:sourceHashLength], nil
+}
+
+// NormalizeFeedURL returns the canonical form of a feed URL used to derive its source.
+// The scheme, default ports, fragments and trailing slashes are ignored, the host is
+// lower-cased and query parameters are sorted.
+func NormalizeFeedURL(feedURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(feedURL))
+	if err != nil {
+		return "", err
+	}
+
+	scheme := strings.ToLower(parsedURL.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", errors.New("invalid feed URL: scheme must be http or https")
+	}
+	if parsedURL.Hostname() == "" {
+		return "", errors.New("invalid feed URL: host must be provided")
+	}
+
+	host := strings.ToLower(parsedURL.Hostname())
+	if port := parsedURL.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	}
+
+	path := strings.TrimRight(parsedURL.EscapedPath(), "/")
+
+	normalized := url.URL{
+		Scheme:   "https",
+		Host:     host,
+		RawPath:  path,
+		RawQuery: parsedURL.Query().Encode(),
+	}
+	normalized.Path, err = url.PathUnescape(path)
+	if err != nil {
+		return "", err
+	}
+
+	return normalized.String(), nil
+}
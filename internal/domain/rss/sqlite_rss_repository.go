@@ -0,0 +1,658 @@
+package rss
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/google/uuid"
+)
+
+// sqliteMigrations are applied in order; the index of a migration plus one is its
+// version. Append new migrations, never edit applied ones.
+var sqliteMigrations = [][]string{
+	{
+		`CREATE TABLE rss (
+			source          TEXT PRIMARY KEY,
+			rss_id          TEXT NOT NULL,
+			title           TEXT NOT NULL,
+			link            TEXT NOT NULL,
+			description     TEXT NOT NULL,
+			language        TEXT NOT NULL,
+			last_build_date INTEGER NOT NULL,
+			item_filter     TEXT NOT NULL,
+			max_age_days    INTEGER NOT NULL,
+			max_items       INTEGER NOT NULL,
+			status          TEXT NOT NULL,
+			etag            TEXT NOT NULL,
+			last_modified   TEXT NOT NULL,
+			trashed_at      INTEGER NOT NULL,
+			purge_at        INTEGER NOT NULL,
+			version         INTEGER NOT NULL,
+			create_by       TEXT NOT NULL,
+			create_at       INTEGER NOT NULL,
+			update_by       TEXT NOT NULL,
+			update_at       INTEGER NOT NULL
+		)`,
+		`CREATE TABLE items (
+			source       TEXT NOT NULL,
+			sort_key     TEXT NOT NULL,
+			rss_id       TEXT NOT NULL,
+			guid         TEXT NOT NULL,
+			title        TEXT NOT NULL,
+			link         TEXT NOT NULL,
+			description  TEXT NOT NULL,
+			author       TEXT NOT NULL,
+			pub_date     INTEGER NOT NULL,
+			tags         TEXT NOT NULL,
+			content_hash TEXT NOT NULL,
+			revisions    TEXT NOT NULL,
+			expire_at    INTEGER NOT NULL,
+			PRIMARY KEY (source, sort_key)
+		)`,
+		`CREATE INDEX items_rss_id_pub_date ON items (rss_id, pub_date)`,
+		`CREATE TABLE fetch_status (
+			source               TEXT PRIMARY KEY,
+			rss_id               TEXT NOT NULL,
+			last_success_at      INTEGER NOT NULL,
+			last_failure_at      INTEGER NOT NULL,
+			last_error           TEXT NOT NULL,
+			consecutive_failures INTEGER NOT NULL,
+			http_status          INTEGER NOT NULL,
+			disabled_at          INTEGER NOT NULL
+		)`,
+		`CREATE TABLE refresh (
+			source       TEXT PRIMARY KEY,
+			rss_id       TEXT NOT NULL,
+			requested_at INTEGER NOT NULL,
+			expire_at    INTEGER NOT NULL
+		)`,
+	},
+	{
+		`ALTER TABLE rss ADD COLUMN parts TEXT NOT NULL DEFAULT 'null'`,
+	},
+}
+
+const sqliteRssColumns = `source, rss_id, title, link, description, language, last_build_date, item_filter,
+	max_age_days, max_items, status, etag, last_modified, trashed_at, purge_at, version,
+	create_by, create_at, update_by, update_at, parts`
+
+const sqliteItemColumns = `source, sort_key, rss_id, guid, title, link, description, author, pub_date,
+	tags, content_hash, revisions, expire_at`
+
+// SQLiteRssRepository is an IRssRepository backed by SQLite, for installs that run
+// without DynamoDB. It stores the same models as DynamoDBRssRepository, so both
+// round-trip an Rss identically; the expire_at columns are kept but nothing expires
+// rows, the prune and purge jobs delete them instead.
+// The caller opens the database with a registered SQLite driver and owns it.
+type SQLiteRssRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRssRepository migrates db to the latest schema and returns a repository on it.
+func NewSQLiteRssRepository(ctx context.Context, db *sql.DB) (*SQLiteRssRepository, error) {
+	r := &SQLiteRssRepository{db: db}
+	if err := r.migrate(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *SQLiteRssRepository) migrate(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	err = r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current)
+	if err != nil {
+		return err
+	}
+
+	for i := current; i < len(sqliteMigrations); i++ {
+		err := r.inTx(ctx, func(tx *sql.Tx) error {
+			for _, statement := range sqliteMigrations[i] {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, i+1)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRssRepository) FindBySource(ctx context.Context, source string) (Rss, error) {
+	if source == "" {
+		return Rss{}, errors.New("invalid source")
+	}
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+sqliteRssColumns+` FROM rss WHERE source = ?`, source)
+	model, err := scanRssModel(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return buildRss(rssManager{}), nil
+	}
+	if err != nil {
+		return Rss{}, err
+	}
+
+	rss := buildRss(rssManager{rss: model, items: []itemModel{}})
+	rss.FetchStatus, err = r.getFetchStatus(ctx, source)
+	if err != nil {
+		return Rss{}, err
+	}
+	return rss, nil
+}
+
+func (r *SQLiteRssRepository) FindAll(ctx context.Context) ([]Rss, error) {
+	var rssFeeds []Rss
+	err := r.FindAllPages(ctx, func(page []Rss) bool {
+		rssFeeds = append(rssFeeds, page...)
+		return true
+	})
+	if err != nil {
+		return []Rss{}, err
+	}
+
+	return rssFeeds, nil
+}
+
+// FindAllPages calls fn once with every stored Rss, ordered by source.
+func (r *SQLiteRssRepository) FindAllPages(ctx context.Context, fn func(rssFeeds []Rss) bool) error {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteRssColumns+` FROM rss ORDER BY source`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var models []rssModel
+	for rows.Next() {
+		model, err := scanRssModel(rows)
+		if err != nil {
+			return err
+		}
+		models = append(models, model)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	rssFeeds := make([]Rss, 0, len(models))
+	for _, model := range models {
+		rss := buildRss(rssManager{rss: model, items: []itemModel{}})
+		rss.FetchStatus, err = r.getFetchStatus(ctx, model.PartitionKey)
+		if err != nil {
+			return err
+		}
+		rssFeeds = append(rssFeeds, rss)
+	}
+
+	fn(rssFeeds)
+	return nil
+}
+
+func (r *SQLiteRssRepository) FindItems(ctx context.Context, rss Rss) (Rss, error) {
+	if rss.Source == "" {
+		return Rss{}, errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	itemModels, err := r.itemModels(ctx, manager.rss)
+	if err != nil {
+		return Rss{}, err
+	}
+
+	return buildRss(rssManager{rss: manager.rss, items: itemModels}), nil
+}
+
+// FindItemsPages calls fn once with every item of rss.
+func (r *SQLiteRssRepository) FindItemsPages(ctx context.Context, rss Rss, fn func(items []Item) bool) error {
+	if rss.Source == "" {
+		return errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	itemModels, err := r.itemModels(ctx, manager.rss)
+	if err != nil {
+		return err
+	}
+
+	items := make([]Item, 0, len(itemModels))
+	for _, model := range itemModels {
+		items = append(items, buildItem(model))
+	}
+	fn(items)
+	return nil
+}
+
+func (r *SQLiteRssRepository) FindItemsByPk(ctx context.Context, rss Rss, guid Guid) (Rss, error) {
+	if rss.ID.String() == "" || guid.Value == "" {
+		return Rss{}, errors.New("invalid source")
+	}
+
+	manager := buildRssManager(rss)
+	searchItemModel := manager.rss.NewItemModel(Item{Guid: guid})
+
+	row := r.db.QueryRowContext(ctx, `SELECT `+sqliteItemColumns+` FROM items WHERE source = ? AND sort_key = ?`,
+		searchItemModel.PartitionKey, searchItemModel.SortKey)
+	findItemModel, err := scanItemModel(row)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Rss{}, err
+	}
+
+	itemModels := []itemModel{}
+	if err == nil {
+		itemModels = []itemModel{findItemModel}
+	}
+
+	return buildRss(rssManager{rss: manager.rss, items: itemModels}), nil
+}
+
+// FindItemsPage returns one page of the items of rss ordered by PubDate, with the
+// same cursors as DynamoDBRssRepository. As with DynamoDB, a full page always has
+// a NextCursor, even when no items follow it.
+func (r *SQLiteRssRepository) FindItemsPage(ctx context.Context, rss Rss, query ItemQuery) (ItemPage, error) {
+	if rss.ID == uuid.Nil {
+		return ItemPage{}, errors.New("invalid rss ID")
+	}
+	if query.Limit <= 0 {
+		return ItemPage{}, errors.New("invalid limit")
+	}
+
+	rssId := rss.ID.String()
+	startKey, err := decodeItemCursor(query.Cursor, rssId)
+	if err != nil {
+		return ItemPage{}, err
+	}
+
+	conditions := []string{"rss_id = ?"}
+	args := []interface{}{rssId}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "pub_date >= ?")
+		args = append(args, query.Since.Unix())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "pub_date <= ?")
+		args = append(args, query.Until.Unix())
+	}
+	for _, tag := range query.Tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM json_each(items.tags) WHERE json_each.value = ?)")
+		args = append(args, tag)
+	}
+
+	// Items with the same pub_date are ordered by their table key so that the
+	// order is stable across pages.
+	order, after := "DESC", "<"
+	if query.Ascending {
+		order, after = "ASC", ">"
+	}
+	if startKey != nil {
+		var start itemCursor
+		if err := attributevalue.UnmarshalMap(startKey, &start); err != nil {
+			return ItemPage{}, err
+		}
+		conditions = append(conditions, "(pub_date, source, sort_key) "+after+" (?, ?, ?)")
+		args = append(args, start.PubDate, start.PartitionKey, start.SortKey)
+	}
+	args = append(args, query.Limit)
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteItemColumns+` FROM items WHERE `+strings.Join(conditions, " AND ")+
+		` ORDER BY pub_date `+order+`, source `+order+`, sort_key `+order+` LIMIT ?`, args...)
+	if err != nil {
+		return ItemPage{}, err
+	}
+	defer rows.Close()
+
+	page := ItemPage{Items: []Item{}}
+	var last itemModel
+	for rows.Next() {
+		last, err = scanItemModel(rows)
+		if err != nil {
+			return ItemPage{}, err
+		}
+		page.Items = append(page.Items, buildItem(last))
+	}
+	if err := rows.Err(); err != nil {
+		return ItemPage{}, err
+	}
+
+	if len(page.Items) == query.Limit {
+		lastEvaluatedKey, err := attributevalue.MarshalMap(itemCursor{PartitionKey: last.PartitionKey, SortKey: last.SortKey, RssId: last.RssId, PubDate: last.PubDate})
+		if err != nil {
+			return ItemPage{}, err
+		}
+		page.NextCursor, err = encodeItemCursor(lastEvaluatedKey)
+		if err != nil {
+			return ItemPage{}, err
+		}
+	}
+	return page, nil
+}
+
+// Save stores rss and returns it with its new Version.
+// It returns a *ConflictError when the stored rss is no longer at rss.Version.
+// Unlike DynamoDBRssRepository, the items and the rss row are written in a single
+// transaction, so nothing is written on a conflict.
+func (r *SQLiteRssRepository) Save(ctx context.Context, rss Rss, updateBy metadata.UserMeta) (Rss, error) {
+	if rss.ID == uuid.Nil {
+		return rss, errors.New("invalid rss ID")
+	}
+
+	now := time.Now()
+
+	if rss.CreatedBy.ID == "" {
+		rss.CreatedAt = metadata.CreateAt(now)
+		rss.CreatedBy = metadata.CreateBy(updateBy)
+	}
+	rss.UpdatedAt = metadata.UpdateAt(now)
+	rss.UpdatedBy = metadata.UpdateBy(updateBy)
+
+	rssManager := buildRssManager(rss)
+	rssManager.rss.Version = rss.Version + 1
+
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		for _, item := range rssManager.items {
+			if err := putItemModel(ctx, tx, item); err != nil {
+				return err
+			}
+		}
+
+		saved, err := putRssModel(ctx, tx, rssManager.rss, rss.Version)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			return &ConflictError{Source: rss.Source, Version: rss.Version}
+		}
+		return nil
+	})
+	if err != nil {
+		return rss, err
+	}
+
+	rss.Version = rssManager.rss.Version
+	return rss, nil
+}
+
+// SaveFetchStatus stores the fetch status of rss.
+func (r *SQLiteRssRepository) SaveFetchStatus(ctx context.Context, rss Rss, status FetchStatus) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	model := buildFetchStatusModel(rss, status)
+	_, err := r.db.ExecContext(ctx, `INSERT OR REPLACE INTO fetch_status
+		(source, rss_id, last_success_at, last_failure_at, last_error, consecutive_failures, http_status, disabled_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		model.PartitionKey, model.RssId, model.LastSuccessAt, model.LastFailureAt, model.LastError,
+		model.ConsecutiveFailures, model.HTTPStatus, model.DisabledAt)
+	return err
+}
+
+// ReserveRefresh records an on-demand refresh of rss at now unless another refresh
+// was reserved within minInterval. It reports false when the refresh is rate limited.
+// The check and the write are a single statement, so concurrent requests cannot
+// both be reserved.
+func (r *SQLiteRssRepository) ReserveRefresh(ctx context.Context, rss Rss, now time.Time, minInterval time.Duration) (bool, error) {
+	if rss.ID == uuid.Nil {
+		return false, errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return false, errors.New("invalid the Source")
+	}
+
+	result, err := r.db.ExecContext(ctx, `INSERT INTO refresh (source, rss_id, requested_at, expire_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (source) DO UPDATE SET rss_id = excluded.rss_id, requested_at = excluded.requested_at, expire_at = excluded.expire_at
+		WHERE refresh.requested_at <= ?`,
+		rss.Source, rss.ID.String(), now.Unix(), now.Add(minInterval).Unix(), now.Add(-minInterval).Unix())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteItems deletes the items of rss identified by guids. The rss row is kept.
+func (r *SQLiteRssRepository) DeleteItems(ctx context.Context, rss Rss, guids []Guid) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	manager := buildRssManager(rss)
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		for _, guid := range guids {
+			_, err := tx.ExecContext(ctx, `DELETE FROM items WHERE source = ? AND sort_key = ?`,
+				manager.rss.PartitionKey, manager.rss.RssId+"#"+guid.Value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteRssRepository) Delete(ctx context.Context, rss Rss) error {
+	if rss.ID == uuid.Nil {
+		return errors.New("invalid rss ID")
+	}
+	if rss.Source == "" {
+		return errors.New("invalid the Source")
+	}
+
+	manager := buildRssManager(rss)
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		statements := []struct {
+			query string
+			args  []interface{}
+		}{
+			{`DELETE FROM items WHERE source = ? AND rss_id = ?`, []interface{}{manager.rss.PartitionKey, manager.rss.RssId}},
+			{`DELETE FROM fetch_status WHERE source = ?`, []interface{}{manager.rss.PartitionKey}},
+			{`DELETE FROM refresh WHERE source = ?`, []interface{}{manager.rss.PartitionKey}},
+			{`DELETE FROM rss WHERE source = ?`, []interface{}{manager.rss.PartitionKey}},
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteRssRepository) getFetchStatus(ctx context.Context, source string) (FetchStatus, error) {
+	var model fetchStatusModel
+	err := r.db.QueryRowContext(ctx, `SELECT last_success_at, last_failure_at, last_error, consecutive_failures, http_status, disabled_at
+		FROM fetch_status WHERE source = ?`, source).
+		Scan(&model.LastSuccessAt, &model.LastFailureAt, &model.LastError, &model.ConsecutiveFailures, &model.HTTPStatus, &model.DisabledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return FetchStatus{}, nil
+	}
+	if err != nil {
+		return FetchStatus{}, err
+	}
+	return buildFetchStatus(model), nil
+}
+
+// itemModels returns the items of the rss row model, ordered by sort key as a
+// DynamoDB query would.
+func (r *SQLiteRssRepository) itemModels(ctx context.Context, model rssModel) ([]itemModel, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sqliteItemColumns+` FROM items WHERE source = ? AND rss_id = ? ORDER BY sort_key`,
+		model.PartitionKey, model.RssId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []itemModel
+	for rows.Next() {
+		item, err := scanItemModel(rows)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, item)
+	}
+	return models, rows.Err()
+}
+
+func (r *SQLiteRssRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// putRssModel writes model unless the stored row is no longer at version, and
+// reports whether it was written. Rows at version 0 have never been saved.
+func putRssModel(ctx context.Context, tx *sql.Tx, model rssModel, version int) (bool, error) {
+	itemFilter, err := json.Marshal(model.ItemFilter)
+	if err != nil {
+		return false, err
+	}
+	createBy, err := json.Marshal(model.CreatedBy)
+	if err != nil {
+		return false, err
+	}
+	updateBy, err := json.Marshal(model.UpdatedBy)
+	if err != nil {
+		return false, err
+	}
+	parts, err := json.Marshal(model.Parts)
+	if err != nil {
+		return false, err
+	}
+
+	// The upsert would insert a row that is gone, where DynamoDB fails the version
+	// condition, so a save of a read row requires the row to exist.
+	if version > 0 {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM rss WHERE source = ?)`, model.PartitionKey).Scan(&exists); err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, nil
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO rss (`+sqliteRssColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (source) DO UPDATE SET
+			rss_id = excluded.rss_id, title = excluded.title, link = excluded.link,
+			description = excluded.description, language = excluded.language,
+			last_build_date = excluded.last_build_date, item_filter = excluded.item_filter,
+			max_age_days = excluded.max_age_days, max_items = excluded.max_items,
+			status = excluded.status, etag = excluded.etag, last_modified = excluded.last_modified,
+			trashed_at = excluded.trashed_at, purge_at = excluded.purge_at, version = excluded.version,
+			create_by = excluded.create_by, create_at = excluded.create_at,
+			update_by = excluded.update_by, update_at = excluded.update_at, parts = excluded.parts
+		WHERE rss.version = ?`,
+		model.PartitionKey, model.RssId, model.Title, model.Link, model.Description, model.Language,
+		model.LastBuildDate, string(itemFilter), model.Retention.MaxAgeDays, model.Retention.MaxItems,
+		model.Status, model.ETag, model.LastModified, model.TrashedAt, model.PurgeAt, model.Version,
+		string(createBy), model.CreatedAt, string(updateBy), model.UpdatedAt, string(parts),
+		version)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func putItemModel(ctx context.Context, tx *sql.Tx, model itemModel) error {
+	tags, err := json.Marshal(model.Tags)
+	if err != nil {
+		return err
+	}
+	revisions, err := json.Marshal(model.Revisions)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT OR REPLACE INTO items (`+sqliteItemColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		model.PartitionKey, model.SortKey, model.RssId, model.GuId, model.Title, model.Link,
+		model.Description, model.Author, model.PubDate, string(tags), model.ContentHash,
+		string(revisions), model.ExpireAt)
+	return err
+}
+
+type sqliteScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRssModel(row sqliteScanner) (rssModel, error) {
+	var model rssModel
+	var itemFilter, createBy, updateBy, parts string
+	err := row.Scan(&model.PartitionKey, &model.RssId, &model.Title, &model.Link, &model.Description,
+		&model.Language, &model.LastBuildDate, &itemFilter, &model.Retention.MaxAgeDays,
+		&model.Retention.MaxItems, &model.Status, &model.ETag, &model.LastModified, &model.TrashedAt,
+		&model.PurgeAt, &model.Version, &createBy, &model.CreatedAt, &updateBy, &model.UpdatedAt, &parts)
+	if err != nil {
+		return rssModel{}, err
+	}
+
+	model.SortKey = "rss"
+	model.Source = model.PartitionKey
+	if err := json.Unmarshal([]byte(itemFilter), &model.ItemFilter); err != nil {
+		return rssModel{}, err
+	}
+	if err := json.Unmarshal([]byte(createBy), &model.CreatedBy); err != nil {
+		return rssModel{}, err
+	}
+	if err := json.Unmarshal([]byte(updateBy), &model.UpdatedBy); err != nil {
+		return rssModel{}, err
+	}
+	if err := json.Unmarshal([]byte(parts), &model.Parts); err != nil {
+		return rssModel{}, err
+	}
+	return model, nil
+}
+
+func scanItemModel(row sqliteScanner) (itemModel, error) {
+	var model itemModel
+	var tags, revisions string
+	err := row.Scan(&model.PartitionKey, &model.SortKey, &model.RssId, &model.GuId, &model.Title,
+		&model.Link, &model.Description, &model.Author, &model.PubDate, &tags, &model.ContentHash,
+		&revisions, &model.ExpireAt)
+	if err != nil {
+		return itemModel{}, err
+	}
+
+	if err := json.Unmarshal([]byte(tags), &model.Tags); err != nil {
+		return itemModel{}, err
+	}
+	if err := json.Unmarshal([]byte(revisions), &model.Revisions); err != nil {
+		return itemModel{}, err
+	}
+	return model, nil
+}
@@ -0,0 +1,25 @@
+package rss
+
+import "time"
+
+// Trash moves the feed to the trash instead of deleting it.
+// The feed and its items are kept until PurgeAt, and Restore brings the feed back
+// with the status it had before.
+func (r *Rss) Trash(now time.Time, retention time.Duration) {
+	r.TrashedAt = now
+	r.PurgeAt = now.Add(retention)
+}
+
+func (r *Rss) Restore() {
+	r.TrashedAt = time.Time{}
+	r.PurgeAt = time.Time{}
+}
+
+func (r Rss) IsTrashed() bool {
+	return !r.TrashedAt.IsZero()
+}
+
+// ShouldPurge reports whether the retention of the trashed feed has expired.
+func (r Rss) ShouldPurge(now time.Time) bool {
+	return r.IsTrashed() && !now.Before(r.PurgeAt)
+}
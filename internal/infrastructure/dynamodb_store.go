@@ -2,26 +2,52 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/YamazakiNorihito/workday/pkg/utils"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 type DynamoDBStore struct {
-	client    *dynamodb.Client
-	TableName string
+	client      *dynamodb.Client
+	TableName   string
+	retryPolicy RetryPolicy
+	logger      Logger
 }
 
-func NewDynamoDBStore(client *dynamodb.Client, tableName string) *DynamoDBStore {
+// NewDynamoDBStore returns a store whose operations are retried by retryPolicy.
+// The SDK's own retries are turned off so that retryPolicy is the only one in effect,
+// and every retry is reported to logger.
+func NewDynamoDBStore(client *dynamodb.Client, tableName string, retryPolicy RetryPolicy, logger Logger) *DynamoDBStore {
 	return &DynamoDBStore{
-		client:    client,
-		TableName: tableName,
+		client:      client,
+		TableName:   tableName,
+		retryPolicy: retryPolicy,
+		logger:      logger,
 	}
 }
 
+var retryables = retry.IsErrorRetryables(retry.DefaultRetryables)
+
+func isRetryable(err error) bool {
+	return retryables.IsErrorRetryable(err) == aws.TrueTernary
+}
+
+func withoutSDKRetries(o *dynamodb.Options) {
+	o.RetryMaxAttempts = 1
+	o.RetryMode = aws.RetryModeStandard
+}
+
+func (r *DynamoDBStore) retry(ctx context.Context, operation string, fn func() error) error {
+	return r.retryPolicy.Do(ctx, r.logger, operation, isRetryable, fn)
+}
+
 func (r *DynamoDBStore) GetItemById(ctx context.Context, partitionkey string, sortKey string) (*dynamodb.GetItemOutput, error) {
 	input := &dynamodb.GetItemInput{
 		TableName: &r.TableName,
@@ -30,11 +56,11 @@ func (r *DynamoDBStore) GetItemById(ctx context.Context, partitionkey string, so
 			"sortKey": &types.AttributeValueMemberS{Value: sortKey},
 		},
 	}
-	optFns := func(o *dynamodb.Options) {
-		o.RetryMaxAttempts = 1
-		o.RetryMode = aws.RetryModeStandard
-	}
-	result, err := r.client.GetItem(ctx, input, optFns)
+	var result *dynamodb.GetItemOutput
+	err := r.retry(ctx, "GetItem", func() (err error) {
+		result, err = r.client.GetItem(ctx, input, withoutSDKRetries)
+		return err
+	})
 
 	if err != nil {
 		return nil, err
@@ -43,7 +69,10 @@ func (r *DynamoDBStore) GetItemById(ctx context.Context, partitionkey string, so
 	return result, nil
 }
 
-func (r *DynamoDBStore) QueryItemsBySortPrefix(ctx context.Context, partitionkey string, sortKeyPrefix string) (*dynamodb.QueryOutput, error) {
+// QueryItemsBySortPrefixPages queries the items of partitionkey whose sort key begins
+// with sortKeyPrefix and calls fn with each page of the result.
+// Iteration stops when fn returns false or all pages have been read.
+func (r *DynamoDBStore) QueryItemsBySortPrefixPages(ctx context.Context, partitionkey string, sortKeyPrefix string, fn func(page *dynamodb.QueryOutput) bool) error {
 	// https://docs.aws.amazon.com/ja_jp/amazondynamodb/latest/developerguide/LegacyConditionalParameters.KeyConditions.html#KeyConditionExpression.instead
 	input := &dynamodb.QueryInput{
 		TableName:              &r.TableName,
@@ -53,19 +82,13 @@ func (r *DynamoDBStore) QueryItemsBySortPrefix(ctx context.Context, partitionkey
 			":sortKeyPrefix": &types.AttributeValueMemberS{Value: sortKeyPrefix},
 		},
 	}
-	optFns := func(o *dynamodb.Options) {
-		o.RetryMaxAttempts = 1
-		o.RetryMode = aws.RetryModeStandard
-	}
-	result, err := r.client.Query(ctx, input, optFns)
-	if err != nil {
-		return nil, err
-	}
-
-	return result, nil
+	return r.queryPages(ctx, input, fn)
 }
 
-func (r *DynamoDBStore) QueryItemsBySortKey(ctx context.Context, sortKey string) (*dynamodb.QueryOutput, error) {
+// QueryItemsBySortKeyPages queries the SortKeyIndex for items with sortKey and calls fn
+// with each page of the result.
+// Iteration stops when fn returns false or all pages have been read.
+func (r *DynamoDBStore) QueryItemsBySortKeyPages(ctx context.Context, sortKey string, fn func(page *dynamodb.QueryOutput) bool) error {
 	input := &dynamodb.QueryInput{
 		TableName:              &r.TableName,
 		IndexName:              aws.String("SortKeyIndex"),
@@ -74,11 +97,65 @@ func (r *DynamoDBStore) QueryItemsBySortKey(ctx context.Context, sortKey string)
 			":sortKey": &types.AttributeValueMemberS{Value: sortKey},
 		},
 	}
-	optFns := func(o *dynamodb.Options) {
-		o.RetryMaxAttempts = 1
-		o.RetryMode = aws.RetryModeStandard
+	return r.queryPages(ctx, input, fn)
+}
+
+func (r *DynamoDBStore) queryPages(ctx context.Context, input *dynamodb.QueryInput, fn func(page *dynamodb.QueryOutput) bool) error {
+
+	paginator := dynamodb.NewQueryPaginator(r.client, input)
+	for paginator.HasMorePages() {
+		var page *dynamodb.QueryOutput
+		// A failed NextPage keeps the paginator on the same page, so it can be retried.
+		err := r.retry(ctx, "Query", func() (err error) {
+			page, err = paginator.NextPage(ctx, withoutSDKRetries)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !fn(page) {
+			return nil
+		}
 	}
-	result, err := r.client.Query(ctx, input, optFns)
+	return nil
+}
+
+// PageQuery describes a single Query request against the table or one of its indexes.
+// ExclusiveStartKey continues a previous query from its LastEvaluatedKey.
+type PageQuery struct {
+	IndexName                 string
+	KeyConditionExpression    string
+	FilterExpression          string
+	ExpressionAttributeValues map[string]types.AttributeValue
+	ScanIndexForward          bool
+	Limit                     int32
+	ExclusiveStartKey         map[string]types.AttributeValue
+}
+
+// QueryPage issues one Query and returns a single page of results.
+// The caller is responsible for following LastEvaluatedKey of the output.
+func (r *DynamoDBStore) QueryPage(ctx context.Context, query PageQuery) (*dynamodb.QueryOutput, error) {
+	input := &dynamodb.QueryInput{
+		TableName:                 &r.TableName,
+		KeyConditionExpression:    aws.String(query.KeyConditionExpression),
+		ExpressionAttributeValues: query.ExpressionAttributeValues,
+		ScanIndexForward:          aws.Bool(query.ScanIndexForward),
+		ExclusiveStartKey:         query.ExclusiveStartKey,
+	}
+	if query.IndexName != "" {
+		input.IndexName = aws.String(query.IndexName)
+	}
+	if query.FilterExpression != "" {
+		input.FilterExpression = aws.String(query.FilterExpression)
+	}
+	if query.Limit > 0 {
+		input.Limit = aws.Int32(query.Limit)
+	}
+	var result *dynamodb.QueryOutput
+	err := r.retry(ctx, "Query", func() (err error) {
+		result, err = r.client.Query(ctx, input, withoutSDKRetries)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -97,18 +174,85 @@ func (r *DynamoDBStore) PutItem(ctx context.Context, item interface{}) error {
 		TableName: &r.TableName,
 		Item:      mapItem,
 	}
-	optFns := func(o *dynamodb.Options) {
-		o.RetryMaxAttempts = 3
-		o.RetryMode = aws.RetryModeStandard
-	}
 
-	_, err = r.client.PutItem(ctx, input, optFns)
+	err = r.retry(ctx, "PutItem", func() error {
+		_, err := r.client.PutItem(ctx, input, withoutSDKRetries)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// PutItemWithCondition puts item only when conditionExpression holds for the stored item.
+// A failed condition is returned as *types.ConditionalCheckFailedException.
+func (r *DynamoDBStore) PutItemWithCondition(ctx context.Context, item interface{}, conditionExpression string, expressionAttributeValues map[string]types.AttributeValue) error {
+	mapItem, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:                 &r.TableName,
+		Item:                      mapItem,
+		ConditionExpression:       aws.String(conditionExpression),
+		ExpressionAttributeValues: expressionAttributeValues,
+	}
+
+	// A failed condition is not retryable, so it is returned as is.
+	return r.retry(ctx, "PutItem", func() error {
+		_, err := r.client.PutItem(ctx, input, withoutSDKRetries)
+		return err
+	})
+}
+
+// TransactPutItemsWithCondition puts items and item in one transaction, which is
+// cancelled unless conditionExpression holds for the stored item. A transaction
+// holds at most 100 items and 4 MB. A failed condition is returned as
+// *types.ConditionalCheckFailedException, as PutItemWithCondition does.
+func (r *DynamoDBStore) TransactPutItemsWithCondition(ctx context.Context, items []interface{}, item interface{}, conditionExpression string, expressionAttributeValues map[string]types.AttributeValue) error {
+	transactItems := make([]types.TransactWriteItem, 0, len(items)+1)
+	for _, item := range items {
+		mapItem, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{TableName: &r.TableName, Item: mapItem},
+		})
+	}
+
+	mapItem, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	transactItems = append(transactItems, types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:                 &r.TableName,
+			Item:                      mapItem,
+			ConditionExpression:       aws.String(conditionExpression),
+			ExpressionAttributeValues: expressionAttributeValues,
+		},
+	})
+
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: transactItems}
+	err = r.retry(ctx, "TransactWriteItems", func() error {
+		_, err := r.client.TransactWriteItems(ctx, input, withoutSDKRetries)
+		return err
+	})
+
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+				return &types.ConditionalCheckFailedException{Message: reason.Message}
+			}
+		}
+	}
+	return err
+}
+
 func (r *DynamoDBStore) DeleteItem(ctx context.Context, partitionKey string, sortKey string) (*dynamodb.DeleteItemOutput, error) {
 	input := &dynamodb.DeleteItemInput{
 		TableName: aws.String(r.TableName),
@@ -117,12 +261,12 @@ func (r *DynamoDBStore) DeleteItem(ctx context.Context, partitionKey string, sor
 			"sortKey": &types.AttributeValueMemberS{Value: sortKey},
 		},
 	}
-	optFns := func(o *dynamodb.Options) {
-		o.RetryMaxAttempts = 1
-		o.RetryMode = aws.RetryModeStandard
-	}
 
-	result, err := r.client.DeleteItem(ctx, input, optFns)
+	var result *dynamodb.DeleteItemOutput
+	err := r.retry(ctx, "DeleteItem", func() (err error) {
+		result, err = r.client.DeleteItem(ctx, input, withoutSDKRetries)
+		return err
+	})
 
 	if err != nil {
 		return nil, err
@@ -132,35 +276,73 @@ func (r *DynamoDBStore) DeleteItem(ctx context.Context, partitionKey string, sor
 }
 
 func (r *DynamoDBStore) BatchDeleteItems(ctx context.Context, deleteInputs []dynamodb.DeleteItemInput) error {
-	chunks := utils.ChunkSlice(deleteInputs, 25)
-
-	for _, chunk := range chunks {
-		writeRequests := make([]types.WriteRequest, len(chunk))
-		for j, input := range chunk {
-			writeRequests[j] = types.WriteRequest{
-				DeleteRequest: &types.DeleteRequest{
-					Key: input.Key,
-				},
-			}
+	writeRequests := make([]types.WriteRequest, len(deleteInputs))
+	for i, input := range deleteInputs {
+		writeRequests[i] = types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{
+				Key: input.Key,
+			},
 		}
+	}
 
+	return r.batchWrite(ctx, writeRequests)
+}
+
+// BatchPutItems puts items in chunks of 25, the BatchWriteItem limit.
+// The items are not written atomically: on error some chunks may already be stored.
+func (r *DynamoDBStore) BatchPutItems(ctx context.Context, items []interface{}) error {
+	writeRequests := make([]types.WriteRequest, len(items))
+	for i, item := range items {
+		mapItem, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return err
+		}
+		writeRequests[i] = types.WriteRequest{
+			PutRequest: &types.PutRequest{
+				Item: mapItem,
+			},
+		}
+	}
+
+	return r.batchWrite(ctx, writeRequests)
+}
+
+const batchWriteChunkSize = 25
+
+// batchWrite sends writeRequests in chunks. The UnprocessedItems of a chunk, which
+// are returned when the table is throttled, are resent under the retry policy as well.
+func (r *DynamoDBStore) batchWrite(ctx context.Context, writeRequests []types.WriteRequest) error {
+	if len(writeRequests) == 0 {
+		return nil
+	}
+
+	for _, chunk := range utils.ChunkSlice(writeRequests, batchWriteChunkSize) {
 		input := &dynamodb.BatchWriteItemInput{
 			RequestItems: map[string][]types.WriteRequest{
-				r.TableName: writeRequests,
+				r.TableName: chunk,
 			},
 		}
 
-		result, err := r.client.BatchWriteItem(ctx, input)
-		if err != nil {
-			return err
-		}
+		start := time.Now()
+		for retry := 1; ; retry++ {
+			var result *dynamodb.BatchWriteItemOutput
+			err := r.retry(ctx, "BatchWriteItem", func() (err error) {
+				result, err = r.client.BatchWriteItem(ctx, input, withoutSDKRetries)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if len(result.UnprocessedItems) == 0 {
+				break
+			}
 
-		for len(result.UnprocessedItems) > 0 {
-			input.RequestItems = result.UnprocessedItems
-			result, err = r.client.BatchWriteItem(ctx, input)
+			cause := fmt.Errorf("%d items unprocessed", len(result.UnprocessedItems[r.TableName]))
+			err = r.retryPolicy.wait(ctx, r.logger, "BatchWriteItem", retry, start, cause)
 			if err != nil {
 				return err
 			}
+			input.RequestItems = result.UnprocessedItems
 		}
 	}
 
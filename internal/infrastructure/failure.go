@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// retryableError is implemented by errors that know whether processing the same
+// message again can succeed, e.g. rss.ConflictError.
+type retryableError interface {
+	Retryable() bool
+}
+
+// PermanentError marks an error that processing the same message again cannot fix.
+type PermanentError struct {
+	Err error
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string   { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error   { return e.Err }
+func (e *PermanentError) Retryable() bool { return false }
+
+// RetryableError marks an error that processing the same message again may fix,
+// for failures IsRetryable cannot tell apart by itself, e.g. a 5xx of a feed.
+type RetryableError struct {
+	Err error
+}
+
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryableError{Err: err}
+}
+
+func (e *RetryableError) Error() string   { return e.Err.Error() }
+func (e *RetryableError) Unwrap() error   { return e.Err }
+func (e *RetryableError) Retryable() bool { return true }
+
+// IsRetryable reports whether a message that failed with err may succeed when it is
+// processed again. Errors that implement Retryable() bool decide for themselves;
+// otherwise timeouts and the errors the AWS SDK would retry (throttling, 5xx,
+// broken connections) are retryable and everything else, such as a malformed
+// message, is permanent.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var classified retryableError
+	if errors.As(err, &classified) {
+		return classified.Retryable()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return isRetryable(err)
+}
@@ -0,0 +1,71 @@
+// Package repository_config selects the rss.IRssRepository implementation from
+// configuration, so the same binary can run against DynamoDB or a local SQLite file.
+// Only workday-server and the source migration use it; the Lambdas construct the
+// DynamoDB repository directly, so that they do not link the SQLite driver.
+package repository_config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure/sqlite"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+const (
+	BackendDynamoDB = "dynamodb"
+	BackendSQLite   = "sqlite"
+	BackendMemory   = "memory"
+)
+
+type Config struct {
+	Backend    string
+	SQLitePath string
+}
+
+// FromEnv reads RSS_REPOSITORY ("dynamodb", the default, "sqlite" or "memory") and
+// RSS_SQLITE_PATH (default "workday.db").
+func FromEnv() Config {
+	config := Config{
+		Backend:    os.Getenv("RSS_REPOSITORY"),
+		SQLitePath: os.Getenv("RSS_SQLITE_PATH"),
+	}
+	if config.Backend == "" {
+		config.Backend = BackendDynamoDB
+	}
+	if config.SQLitePath == "" {
+		config.SQLitePath = "workday.db"
+	}
+	return config
+}
+
+// NewRssRepository returns the repository selected by config and a function that
+// releases it. newDynamodbClient is called only for the DynamoDB backend.
+func NewRssRepository(ctx context.Context, config Config, logger infrastructure.Logger, newDynamodbClient func() *dynamodb.Client) (rss.IRssRepository, func() error, error) {
+	noop := func() error { return nil }
+
+	switch config.Backend {
+	case BackendDynamoDB:
+		return rss.NewDynamoDBRssRepository(newDynamodbClient(), logger), noop, nil
+	case BackendSQLite:
+		db, err := sqlite.Open(config.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		repository, err := rss.NewSQLiteRssRepository(ctx, db)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		logger.Info("Using SQLite repository", "path", config.SQLitePath)
+		return repository, db.Close, nil
+	case BackendMemory:
+		logger.Warn("Using in-memory repository, feeds are lost on exit")
+		return rss.NewInMemoryRssRepository(), noop, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown repository backend %q", config.Backend)
+	}
+}
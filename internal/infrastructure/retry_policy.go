@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrRetriesExhausted is returned by RetryPolicy.Do, wrapped with the last error of
+// the operation, when the policy allows no more retries.
+var ErrRetriesExhausted = errors.New("gave up retrying")
+
+// RetryPolicy decides how often and how long a failed operation is retried.
+// The delay before the n-th retry is a random duration up to
+// min(BaseDelay * 2^(n-1), MaxDelay) ("full jitter"), so that clients throttled
+// together do not retry together.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one.
+	// Zero or one means no retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// MaxElapsedTime stops the retries once this much time has passed since the
+	// first attempt, whatever MaxAttempts says. Zero means no limit.
+	MaxElapsedTime time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		BaseDelay:      50 * time.Millisecond,
+		MaxDelay:       5 * time.Second,
+		MaxElapsedTime: 30 * time.Second,
+	}
+}
+
+// Backoff returns the upper bound of the delay before the retry-th retry (1-based).
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	if retry < 1 || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// Delay returns the jittered delay before the retry-th retry (1-based).
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	backoff := p.Backoff(retry)
+	if backoff <= 0 {
+		return 0
+	}
+	return rand.N(backoff + 1)
+}
+
+// Do calls fn until it succeeds, fails with an error isRetryable rejects, or the
+// policy is exhausted. In the last case the last error is returned wrapped with
+// ErrRetriesExhausted, or with the error of ctx when it was done before a retry.
+// Every retry is logged with operation, so throttling shows up in the logs.
+func (p RetryPolicy) Do(ctx context.Context, logger Logger, operation string, isRetryable func(error) bool, fn func() error) error {
+	start := time.Now()
+	for retry := 0; ; retry++ {
+		err := fn()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+
+		if waitErr := p.wait(ctx, logger, operation, retry+1, start, err); waitErr != nil {
+			return fmt.Errorf("%w: %w", waitErr, err)
+		}
+	}
+}
+
+// wait sleeps before the retry-th retry. It returns an error instead when the
+// policy does not allow another retry or ctx is done.
+func (p RetryPolicy) wait(ctx context.Context, logger Logger, operation string, retry int, start time.Time, cause error) error {
+	if retry >= p.MaxAttempts {
+		logger.Warn("Giving up retrying", "operation", operation, "attempts", retry, "error", cause)
+		return fmt.Errorf("%s: %w after %d attempts", operation, ErrRetriesExhausted, retry)
+	}
+
+	delay := p.Delay(retry)
+	if p.MaxElapsedTime > 0 && time.Since(start)+delay > p.MaxElapsedTime {
+		logger.Warn("Giving up retrying", "operation", operation, "attempts", retry, "elapsed", time.Since(start), "error", cause)
+		return fmt.Errorf("%s: %w after %s", operation, ErrRetriesExhausted, p.MaxElapsedTime)
+	}
+
+	logger.Info("Retrying", "operation", operation, "retry", retry, "delay", delay, "error", cause)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
@@ -0,0 +1,27 @@
+// Package sqlite opens SQLite databases with the pure-Go modernc.org/sqlite driver.
+// It is kept apart from package infrastructure so that only the binaries that use
+// SQLite link the driver.
+package sqlite
+
+import (
+	"database/sql"
+
+	_ "modernc.org/sqlite"
+)
+
+// Open opens the SQLite database at path, creating it when it does not exist.
+// Writes are serialized over a single connection, which SQLite requires anyway,
+// and waiting for a lock is preferred to failing with SQLITE_BUSY.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
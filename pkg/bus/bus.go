@@ -0,0 +1,117 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/google/uuid"
+)
+
+var ErrClosed = errors.New("bus is closed")
+
+// Handler consumes one message published to a topic.
+// logger carries the topic and the message ID, like the per-record logger of the Lambda handlers.
+type Handler func(ctx context.Context, logger infrastructure.Logger, message string) error
+
+type messageIDKey struct{}
+
+// MessageID returns the ID the bus gave to the message a Handler is called with.
+func MessageID(ctx context.Context) string {
+	messageID, _ := ctx.Value(messageIDKey{}).(string)
+	return messageID
+}
+
+type subscription struct {
+	handler Handler
+	slots   chan struct{}
+}
+
+// Bus is an in-memory stand-in for the SNS topics between the pipeline stages.
+// Every message is delivered to every subscriber of its topic on its own goroutine,
+// so publishing never waits for the consumers, as with SNS.
+// Failed deliveries are logged and dropped.
+type Bus struct {
+	logger *slog.Logger
+
+	mu            sync.Mutex
+	subscriptions map[string][]subscription
+	closed        bool
+	pending       sync.WaitGroup
+}
+
+func New(logger *slog.Logger) *Bus {
+	return &Bus{logger: logger, subscriptions: map[string][]subscription{}}
+}
+
+// Subscribe registers handler for topic. At most concurrency messages are handled
+// at once, like the reserved concurrency of a Lambda function; zero or less means one.
+func (b *Bus) Subscribe(topic string, concurrency int, handler Handler) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[topic] = append(b.subscriptions[topic], subscription{handler: handler, slots: make(chan struct{}, concurrency)})
+}
+
+// Publish delivers message to the subscribers of topic and returns without waiting for them.
+// The subscribers get ctx without its cancellation, since a delivered message outlives its publisher.
+func (b *Bus) Publish(ctx context.Context, topic string, message string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+
+	messageID := uuid.NewString()
+	for _, s := range b.subscriptions[topic] {
+		b.pending.Add(1)
+		deliverCtx := context.WithValue(context.WithoutCancel(ctx), messageIDKey{}, messageID)
+		go b.deliver(deliverCtx, s, b.logger.With("topic", topic, "messageID", messageID), message)
+	}
+	return nil
+}
+
+func (b *Bus) deliver(ctx context.Context, s subscription, logger *slog.Logger, message string) {
+	defer b.pending.Done()
+
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	if err := s.handler(ctx, logger, message); err != nil {
+		logger.Error("Failed", "error", err)
+	}
+}
+
+// Topic returns a publisher.MessagePublisher that publishes to topic.
+func (b *Bus) Topic(topic string) *TopicPublisher {
+	return &TopicPublisher{bus: b, topic: topic}
+}
+
+// Wait blocks until every published message, including the ones published while
+// waiting, has been handled.
+func (b *Bus) Wait() {
+	b.pending.Wait()
+}
+
+// Close refuses further messages and waits for the pending ones.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	b.pending.Wait()
+}
+
+type TopicPublisher struct {
+	bus   *Bus
+	topic string
+}
+
+func (p *TopicPublisher) Publish(ctx context.Context, message string) error {
+	return p.bus.Publish(ctx, p.topic, message)
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
 )
 
-func PollStreamAndInvokeHandler(ctx context.Context, streamArn string, debugHandle func(ctx context.Context, e events.DynamoDBEvent) error) {
+func PollStreamAndInvokeHandler(ctx context.Context, streamArn string, debugHandle func(ctx context.Context, e events.DynamoDBEvent) (events.DynamoDBEventResponse, error)) {
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		log.Fatalf("unable to load SDK config, %v", err)
@@ -64,9 +64,13 @@ func PollStreamAndInvokeHandler(ctx context.Context, streamArn string, debugHand
 					Change:       change,
 				})
 
-				if err := debugHandle(ctx, event); err != nil {
+				response, err := debugHandle(ctx, event)
+				if err != nil {
 					log.Fatalf("failed to handle request, %v", err)
 				}
+				for _, failure := range response.BatchItemFailures {
+					log.Printf("batch item failure, sequence number %s", failure.ItemIdentifier)
+				}
 			}
 		}
 
@@ -0,0 +1,17 @@
+package claimcheck
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("claim check not found")
+
+// Store keeps the payloads that are too large to be sent through SNS. A message
+// carries only the key of its payload, the claim check, and the receiver gets the
+// payload back with it.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns ErrNotFound when nothing is stored under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
@@ -0,0 +1,124 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+const sortKey = "dead_letter"
+
+// Retention is how long a dead letter is kept before the TTL removes it.
+const Retention = 14 * 24 * time.Hour
+
+type deadLetterModel struct {
+	PartitionKey string `dynamodbav:"id"`
+	SortKey      string `dynamodbav:"sortKey"`
+
+	Stage      string `dynamodbav:"stage"`
+	Message    string `dynamodbav:"message"`
+	ClaimCheck string `dynamodbav:"claim_check,omitempty"`
+	Reason     string `dynamodbav:"reason"`
+	FailedAt   int64  `dynamodbav:"failed_at"`
+	ExpireAt   int64  `dynamodbav:"expire_at"` // TTL attribute
+}
+
+// DynamoDBStore keeps the dead letters in the Rss table, one row per message
+// keyed by its MessageID with the sort key "dead_letter". The notification
+// function ignores the rows, as it does every row that is not an rss row.
+// A dead letter whose message is kept in a claimcheck.Store is stored with only
+// the claim check, so that a row stays within the DynamoDB item size limit.
+type DynamoDBStore struct {
+	dynamoDBStore infrastructure.DynamoDBStore
+}
+
+func NewDynamoDBStore(client *dynamodb.Client, logger infrastructure.Logger) *DynamoDBStore {
+	return &DynamoDBStore{dynamoDBStore: *infrastructure.NewDynamoDBStore(client, "Rss", infrastructure.DefaultRetryPolicy(), logger)}
+}
+
+func (s *DynamoDBStore) Save(ctx context.Context, deadLetter message.DeadLetter) error {
+	if deadLetter.MessageID == "" {
+		return errors.New("invalid message ID")
+	}
+
+	return s.dynamoDBStore.PutItem(ctx, deadLetterModel{
+		PartitionKey: deadLetter.MessageID,
+		SortKey:      sortKey,
+		Stage:        deadLetter.Stage,
+		Message:      deadLetter.Message,
+		ClaimCheck:   deadLetter.ClaimCheck,
+		Reason:       deadLetter.Reason,
+		FailedAt:     deadLetter.FailedAt.UnixNano(),
+		ExpireAt:     deadLetter.FailedAt.Add(Retention).Unix(),
+	})
+}
+
+func (s *DynamoDBStore) FindAll(ctx context.Context) ([]message.DeadLetter, error) {
+	var deadLetters []message.DeadLetter
+	var unmarshalErr error
+	err := s.dynamoDBStore.QueryItemsBySortKeyPages(ctx, sortKey, func(page *dynamodb.QueryOutput) bool {
+		var models []deadLetterModel
+		unmarshalErr = attributevalue.UnmarshalListOfMaps(page.Items, &models)
+		if unmarshalErr != nil {
+			return false
+		}
+		for _, model := range models {
+			deadLetters = append(deadLetters, buildDeadLetter(model))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	sortByFailedAt(deadLetters)
+	return deadLetters, nil
+}
+
+func (s *DynamoDBStore) Find(ctx context.Context, messageID string) (message.DeadLetter, error) {
+	if messageID == "" {
+		return message.DeadLetter{}, errors.New("invalid message ID")
+	}
+
+	result, err := s.dynamoDBStore.GetItemById(ctx, messageID, sortKey)
+	if err != nil {
+		return message.DeadLetter{}, err
+	}
+	if len(result.Item) == 0 {
+		return message.DeadLetter{}, ErrNotFound
+	}
+
+	var model deadLetterModel
+	if err := attributevalue.UnmarshalMap(result.Item, &model); err != nil {
+		return message.DeadLetter{}, err
+	}
+	return buildDeadLetter(model), nil
+}
+
+func (s *DynamoDBStore) Delete(ctx context.Context, messageID string) error {
+	if messageID == "" {
+		return errors.New("invalid message ID")
+	}
+
+	_, err := s.dynamoDBStore.DeleteItem(ctx, messageID, sortKey)
+	return err
+}
+
+func buildDeadLetter(model deadLetterModel) message.DeadLetter {
+	return message.DeadLetter{
+		Stage:      model.Stage,
+		MessageID:  model.PartitionKey,
+		Message:    model.Message,
+		ClaimCheck: model.ClaimCheck,
+		Reason:     model.Reason,
+		FailedAt:   time.Unix(0, model.FailedAt).UTC(),
+	}
+}
@@ -0,0 +1,61 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+)
+
+// InMemoryStore is a Store for tests and the workday-server. Dead letters never expire.
+// It is safe for concurrent use.
+type InMemoryStore struct {
+	mu          sync.RWMutex
+	deadLetters map[string]message.DeadLetter
+}
+
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{deadLetters: map[string]message.DeadLetter{}}
+}
+
+func (s *InMemoryStore) Save(ctx context.Context, deadLetter message.DeadLetter) error {
+	if deadLetter.MessageID == "" {
+		return errors.New("invalid message ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters[deadLetter.MessageID] = deadLetter
+	return nil
+}
+
+func (s *InMemoryStore) FindAll(ctx context.Context) ([]message.DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deadLetters := make([]message.DeadLetter, 0, len(s.deadLetters))
+	for _, deadLetter := range s.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	sortByFailedAt(deadLetters)
+	return deadLetters, nil
+}
+
+func (s *InMemoryStore) Find(ctx context.Context, messageID string) (message.DeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deadLetter, ok := s.deadLetters[messageID]
+	if !ok {
+		return message.DeadLetter{}, ErrNotFound
+	}
+	return deadLetter, nil
+}
+
+func (s *InMemoryStore) Delete(ctx context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.deadLetters, messageID)
+	return nil
+}
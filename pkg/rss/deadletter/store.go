@@ -0,0 +1,28 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+)
+
+var ErrNotFound = errors.New("dead letter not found")
+
+// Store keeps the messages the stages gave up on until they are replayed or purged.
+// A dead letter is identified by its MessageID.
+type Store interface {
+	Save(ctx context.Context, deadLetter message.DeadLetter) error
+	// FindAll returns the stored dead letters, oldest first.
+	FindAll(ctx context.Context) ([]message.DeadLetter, error)
+	// Find returns ErrNotFound when no dead letter has messageID.
+	Find(ctx context.Context, messageID string) (message.DeadLetter, error)
+	Delete(ctx context.Context, messageID string) error
+}
+
+func sortByFailedAt(deadLetters []message.DeadLetter) {
+	sort.SliceStable(deadLetters, func(i, j int) bool {
+		return deadLetters[i].FailedAt.Before(deadLetters[j].FailedAt)
+	})
+}
@@ -0,0 +1,120 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnvelopeVersion is the version of the Envelope the publishers write.
+const EnvelopeVersion = 1
+
+// Types of the messages an Envelope carries.
+const (
+	TypeSubscribe  = "subscribe"
+	TypeWrite      = "write"
+	TypeDelete     = "delete"
+	TypeDeadLetter = "dead_letter"
+)
+
+// Origins of the messages that are not published by a stage.
+const (
+	OriginTrigger = "trigger"
+	OriginAPI     = "api"
+	OriginReplay  = "replay"
+)
+
+// Envelope wraps every message the publishers send. CorrelationID stays the same
+// from the message that started a feed refresh (a trigger run, an API request) through
+// every message published while handling it, so that the refresh can be followed
+// across the stages in the logs.
+type Envelope struct {
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	MessageID     string          `json:"message_id"`
+	CorrelationID string          `json:"correlation_id"`
+	Origin        string          `json:"origin"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Trace is what a stage passes on to the messages it publishes.
+type Trace struct {
+	CorrelationID string
+	// Origin is the stage, or OriginTrigger and so on, that publishes.
+	Origin string
+}
+
+type traceKey struct{}
+
+func ContextWithTrace(ctx context.Context, trace Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+func TraceFromContext(ctx context.Context) Trace {
+	trace, _ := ctx.Value(traceKey{}).(Trace)
+	return trace
+}
+
+// NewEnvelope wraps payload with the trace of ctx. Without a correlation ID in ctx
+// the message starts a new chain, and its own message ID becomes the correlation ID.
+func NewEnvelope(ctx context.Context, messageType string, payload any) (Envelope, error) {
+	payloadJson, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	trace := TraceFromContext(ctx)
+	envelope := Envelope{
+		Type:          messageType,
+		Version:       EnvelopeVersion,
+		MessageID:     uuid.NewString(),
+		CorrelationID: trace.CorrelationID,
+		Origin:        trace.Origin,
+		CreatedAt:     time.Now().UTC(),
+		Payload:       payloadJson,
+	}
+	if envelope.CorrelationID == "" {
+		envelope.CorrelationID = envelope.MessageID
+	}
+	return envelope, nil
+}
+
+// Decode reads a message of messageType into payload and returns its envelope.
+// Messages published before the envelope existed are the bare payload; they are
+// still decoded and come with a zero Envelope (Version 0).
+func Decode(receiveMessage string, messageType string, payload any) (Envelope, error) {
+	envelope, err := PeekEnvelope(receiveMessage)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	if envelope.Version == 0 {
+		return Envelope{}, json.Unmarshal([]byte(receiveMessage), payload)
+	}
+	if envelope.Type != messageType {
+		return Envelope{}, fmt.Errorf("unexpected message type %q, want %q", envelope.Type, messageType)
+	}
+	return envelope, json.Unmarshal(envelope.Payload, payload)
+}
+
+// PeekEnvelope returns the envelope of receiveMessage without decoding the payload,
+// or a zero Envelope for a legacy message. It fails for a version newer than
+// EnvelopeVersion, which this build cannot read.
+func PeekEnvelope(receiveMessage string) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(receiveMessage), &envelope); err != nil {
+		return Envelope{}, err
+	}
+
+	if envelope.Version == 0 || envelope.Payload == nil {
+		return Envelope{}, nil
+	}
+	if envelope.Version > EnvelopeVersion {
+		return Envelope{}, fmt.Errorf("unsupported message version %d", envelope.Version)
+	}
+	return envelope, nil
+}
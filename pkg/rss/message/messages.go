@@ -3,31 +3,72 @@ package message
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/google/uuid"
 )
 
 const MaxMessageSize = 256 * 1024
 
 type Subscribe struct {
-	FeedURL        string `json:"feed_url"`
-	Language       string `json:"language"`
-	rss.ItemFilter `json:"item_filter"`
+	Source       string         `json:"source,omitempty"`
+	FeedURL      string         `json:"feed_url"`
+	Language     string         `json:"language"`
+	ItemFilter   rss.ItemFilter `json:"item_filter"`
+	Retention    rss.Retention  `json:"retention"`
+	ETag         string         `json:"etag,omitempty"`
+	LastModified string         `json:"last_modified,omitempty"`
 }
 
 type Write struct {
 	RssFeed    rss.Rss `json:"rss,omitempty"`
 	Compressed bool    `json:"compressed"`
 	Data       []byte  `json:"data,omitempty"`
+	// ClaimCheck is the key the serialized feed is stored under in a claimcheck.Store
+	// when even the compressed feed is too large for SNS.
+	ClaimCheck string `json:"claim_check,omitempty"`
+	// Part numbers, from 1, the messages a feed too large for one message is split
+	// into, and Parts counts them. Both are zero for a feed sent whole. The feed of
+	// a part also carries them, in rss.Rss.Parts, through the stages to write.
+	Part  int `json:"part,omitempty"`
+	Parts int `json:"parts,omitempty"`
 }
 
 type Delete struct {
 	Source string `json:"source"`
 }
 
+// Stages a DeadLetter can come from.
+const (
+	StageSubscribe    = "subscribe"
+	StageClean        = "clean"
+	StageTranslate    = "translate"
+	StageWrite        = "write"
+	StageDelete       = "delete"
+	StageNotification = "notification"
+)
+
+// DeadLetter carries a message a stage gave up on, with the reason, so that it can
+// be inspected and replayed to the same stage.
+type DeadLetter struct {
+	Stage     string `json:"stage"`
+	MessageID string `json:"message_id"`
+	Message   string `json:"message"`
+	// ClaimCheck is the key Message is stored under in a claimcheck.Store when the
+	// dead letter with it would be too large for SNS. Message is empty then.
+	ClaimCheck string    `json:"claim_check,omitempty"`
+	Reason     string    `json:"reason"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
 func NewWriteMessage(entryRss rss.Rss) (writeMessage Write, err error) {
 	serializedRss, _ := json.Marshal(entryRss)
 	if len(serializedRss) > MaxMessageSize {
@@ -49,6 +90,111 @@ func NewWriteMessage(entryRss rss.Rss) (writeMessage Write, err error) {
 	return writeMessage, nil
 }
 
+// envelopeAllowance is the room left in MaxMessageSize for the Envelope around a Write.
+const envelopeAllowance = 1024
+
+// NewWriteMessageWithClaimCheck builds the message like NewWriteMessage, but when the
+// message would still be too large for SNS it stores the serialized feed in store and
+// sends only the claim check. A nil store never offloads.
+func NewWriteMessageWithClaimCheck(ctx context.Context, entryRss rss.Rss, store claimcheck.Store) (Write, error) {
+	writeMessage, err := NewWriteMessage(entryRss)
+	if err != nil || store == nil {
+		return writeMessage, err
+	}
+
+	writeMessageJson, err := json.Marshal(writeMessage)
+	if err != nil {
+		return Write{}, err
+	}
+	if len(writeMessageJson) <= MaxMessageSize-envelopeAllowance {
+		return writeMessage, nil
+	}
+
+	serializedRss, err := json.Marshal(entryRss)
+	if err != nil {
+		return Write{}, err
+	}
+	key := "write/" + uuid.NewString() + ".json"
+	if err := store.Put(ctx, key, serializedRss); err != nil {
+		return Write{}, err
+	}
+	return Write{ClaimCheck: key}, nil
+}
+
+// NewDeadLetterWithClaimCheck returns deadLetter as it is when it fits in SNS, and
+// otherwise stores its message in store and returns it with only the claim check.
+// A nil store never offloads.
+func NewDeadLetterWithClaimCheck(ctx context.Context, deadLetter DeadLetter, store claimcheck.Store) (DeadLetter, error) {
+	if store == nil {
+		return deadLetter, nil
+	}
+
+	deadLetterJson, err := json.Marshal(deadLetter)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	if len(deadLetterJson) <= MaxMessageSize-envelopeAllowance {
+		return deadLetter, nil
+	}
+
+	key := "dead_letter/" + uuid.NewString() + ".json"
+	if err := store.Put(ctx, key, []byte(deadLetter.Message)); err != nil {
+		return DeadLetter{}, err
+	}
+	deadLetter.Message = ""
+	deadLetter.ClaimCheck = key
+	return deadLetter, nil
+}
+
+// ResolveDeadLetter returns deadLetter with Message filled in, whether the message
+// was kept inline or as a claim check on store.
+func ResolveDeadLetter(ctx context.Context, deadLetter DeadLetter, store claimcheck.Store) (DeadLetter, error) {
+	if deadLetter.ClaimCheck == "" {
+		return deadLetter, nil
+	}
+	if store == nil {
+		return DeadLetter{}, errors.New("received a claim check but no claim check store is configured")
+	}
+
+	receiveMessage, err := store.Get(ctx, deadLetter.ClaimCheck)
+	if err != nil {
+		return DeadLetter{}, fmt.Errorf("failed to get claim check %s: %w", deadLetter.ClaimCheck, err)
+	}
+	deadLetter.Message = string(receiveMessage)
+	deadLetter.ClaimCheck = ""
+	return deadLetter, nil
+}
+
+// ResolveWrite returns writeMessage with RssFeed filled in, whether the feed was sent
+// inline, compressed, or as a claim check on store.
+func ResolveWrite(ctx context.Context, writeMessage Write, store claimcheck.Store) (Write, error) {
+	switch {
+	case writeMessage.ClaimCheck != "":
+		if store == nil {
+			return Write{}, errors.New("received a claim check but no claim check store is configured")
+		}
+		serializedRss, err := store.Get(ctx, writeMessage.ClaimCheck)
+		if err != nil {
+			return Write{}, fmt.Errorf("failed to get claim check %s: %w", writeMessage.ClaimCheck, err)
+		}
+		var rssFeed rss.Rss
+		if err := json.Unmarshal(serializedRss, &rssFeed); err != nil {
+			return Write{}, err
+		}
+		return Write{RssFeed: rssFeed, Part: writeMessage.Part, Parts: writeMessage.Parts}, nil
+
+	case writeMessage.Compressed:
+		rssFeed, err := DecodeAndDecompressData(writeMessage.Data)
+		if err != nil {
+			return Write{}, err
+		}
+		return Write{RssFeed: rssFeed, Part: writeMessage.Part, Parts: writeMessage.Parts}, nil
+
+	default:
+		return writeMessage, nil
+	}
+}
+
 func compressAndEncodeData(data []byte) ([]byte, error) {
 	var buffer bytes.Buffer
 	gzipWriter := gzip.NewWriter(&buffer)
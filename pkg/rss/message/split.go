@@ -0,0 +1,107 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/google/uuid"
+)
+
+// partAllowance is the room left for the fields of a Write around the feed of a
+// part, and for the rss.FetchParts of the feed.
+const partAllowance = 256
+
+// NewWriteMessages builds the messages for entryRss. A feed that does not fit in
+// one message is split into parts with the same feed header and disjoint items,
+// numbered by Part and Parts, so that none of them has to be compressed. A single
+// item too large for a message of its own is still sent compressed or as a claim
+// check on store, as NewWriteMessageWithClaimCheck does.
+//
+// A part passed on by a stage is not split again, so that write sees the parts the
+// fetch was split into; one that has outgrown a message, e.g. by translation, is
+// sent compressed or as a claim check instead.
+func NewWriteMessages(ctx context.Context, entryRss rss.Rss, store claimcheck.Store) ([]Write, error) {
+	parts := []rss.Rss{entryRss}
+	if entryRss.Parts == nil {
+		var err error
+		parts, err = SplitRss(entryRss, MaxMessageSize-envelopeAllowance-partAllowance)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writeMessages := make([]Write, 0, len(parts))
+	for _, part := range parts {
+		writeMessage, err := NewWriteMessageWithClaimCheck(ctx, part, store)
+		if err != nil {
+			return nil, err
+		}
+		if part.Parts != nil {
+			writeMessage.Part = part.Parts.Written[0]
+			writeMessage.Parts = part.Parts.Parts
+		}
+		writeMessages = append(writeMessages, writeMessage)
+	}
+	return writeMessages, nil
+}
+
+// SplitRss splits entryRss into feeds whose JSON is at most maxSize bytes, each
+// with the header of entryRss and a share of its items. The items are taken in
+// the order of their guids, so the same feed is always split the same way. A feed
+// that fits, or has no items, is returned as the only part. Otherwise every part
+// gets the rss.FetchParts of a new fetch ID with its number.
+func SplitRss(entryRss rss.Rss, maxSize int) ([]rss.Rss, error) {
+	header := entryRss
+	header.Items = map[rss.Guid]rss.Item{}
+	headerJson, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	guids := make([]rss.Guid, 0, len(entryRss.Items))
+	for guid := range entryRss.Items {
+		guids = append(guids, guid)
+	}
+	sort.Slice(guids, func(i, j int) bool { return guids[i].Value < guids[j].Value })
+
+	var parts []rss.Rss
+	part, partSize := header, len(headerJson)
+	for _, guid := range guids {
+		itemSize, err := itemSize(guid, entryRss.Items[guid])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(part.Items) > 0 && partSize+itemSize > maxSize {
+			parts = append(parts, part)
+			part, partSize = header, len(headerJson)
+		}
+		if len(part.Items) == 0 {
+			part.Items = map[rss.Guid]rss.Item{}
+		}
+		part.Items[guid] = entryRss.Items[guid]
+		partSize += itemSize
+	}
+	parts = append(parts, part)
+	if len(parts) == 1 {
+		return parts, nil
+	}
+
+	fetchID := uuid.NewString()
+	for i := range parts {
+		parts[i].Parts = rss.NewFetchParts(fetchID, i+1, len(parts))
+	}
+	return parts, nil
+}
+
+// itemSize returns the bytes item adds to the items object of a feed, the comma included.
+func itemSize(guid rss.Guid, item rss.Item) (int, error) {
+	entryJson, err := json.Marshal(map[rss.Guid]rss.Item{guid: item})
+	if err != nil {
+		return 0, err
+	}
+	return len(entryJson) - len("{}") + len(","), nil
+}
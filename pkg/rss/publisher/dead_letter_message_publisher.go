@@ -0,0 +1,19 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+)
+
+type DeadLetterMessagePublisher struct {
+	publisher MessagePublisher
+}
+
+func NewDeadLetterMessagePublisher(publisher MessagePublisher) *DeadLetterMessagePublisher {
+	return &DeadLetterMessagePublisher{publisher: publisher}
+}
+
+func (p *DeadLetterMessagePublisher) Publish(ctx context.Context, deadLetter message.DeadLetter) error {
+	return publishEnvelope(ctx, p.publisher, message.TypeDeadLetter, deadLetter)
+}
@@ -2,7 +2,6 @@ package publisher
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 )
@@ -16,16 +15,5 @@ func NewDeleteMessagePublisher(publisher MessagePublisher) *DeleteMessagePublish
 }
 
 func (p *DeleteMessagePublisher) Publish(ctx context.Context, source string) error {
-	message := message.Delete{Source: source}
-
-	messageJson, err := json.Marshal(message)
-	if err != nil {
-		return err
-	}
-
-	err = p.publisher.Publish(ctx, string(messageJson))
-	if err != nil {
-		return err
-	}
-	return nil
+	return publishEnvelope(ctx, p.publisher, message.TypeDelete, message.Delete{Source: source})
 }
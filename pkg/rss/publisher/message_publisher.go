@@ -2,8 +2,26 @@ package publisher
 
 import (
 	"context"
+	"encoding/json"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 )
 
 type MessagePublisher interface {
 	Publish(ctx context.Context, message string) error
 }
+
+// publishEnvelope publishes payload wrapped in a message.Envelope carrying the trace of ctx.
+func publishEnvelope(ctx context.Context, publisher MessagePublisher, messageType string, payload any) error {
+	envelope, err := message.NewEnvelope(ctx, messageType, payload)
+	if err != nil {
+		return err
+	}
+
+	envelopeJson, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return publisher.Publish(ctx, string(envelopeJson))
+}
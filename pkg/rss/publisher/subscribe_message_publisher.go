@@ -2,7 +2,6 @@ package publisher
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 )
@@ -15,15 +14,6 @@ func NewSubscribeMessagePublisher(publisher MessagePublisher) *SubscribeMessageP
 	return &SubscribeMessagePublisher{publisher: publisher}
 }
 
-func (p *SubscribeMessagePublisher) Publish(ctx context.Context, message message.Subscribe) error {
-	messageJson, err := json.Marshal(message)
-	if err != nil {
-		return err
-	}
-
-	err = p.publisher.Publish(ctx, string(messageJson))
-	if err != nil {
-		return err
-	}
-	return nil
+func (p *SubscribeMessagePublisher) Publish(ctx context.Context, subscribeMessage message.Subscribe) error {
+	return publishEnvelope(ctx, p.publisher, message.TypeSubscribe, subscribeMessage)
 }
@@ -2,34 +2,40 @@ package publisher
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
 	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 )
 
 type WriterMessagePublisher struct {
-	publisher MessagePublisher
+	publisher       MessagePublisher
+	claimCheckStore claimcheck.Store
 }
 
 func NewWriterMessagePublisher(publisher MessagePublisher) *WriterMessagePublisher {
 	return &WriterMessagePublisher{publisher: publisher}
 }
 
-func (p *WriterMessagePublisher) Publish(ctx context.Context, rssEntry rss.Rss) error {
-	message, err := message.NewWriteMessage(rssEntry)
-	if err != nil {
-		return err
-	}
+// NewWriterMessagePublisherWithClaimCheck returns a publisher that stores the feeds
+// too large for SNS in claimCheckStore and publishes their claim checks instead.
+func NewWriterMessagePublisherWithClaimCheck(publisher MessagePublisher, claimCheckStore claimcheck.Store) *WriterMessagePublisher {
+	return &WriterMessagePublisher{publisher: publisher, claimCheckStore: claimCheckStore}
+}
 
-	messageJson, err := json.Marshal(message)
+// Publish publishes rssEntry, split into parts when it does not fit in one message.
+// The parts are published in order and a failure stops the rest; publishing the feed
+// again publishes every part again, which the stages handle as a redelivery.
+func (p *WriterMessagePublisher) Publish(ctx context.Context, rssEntry rss.Rss) error {
+	writeMessages, err := message.NewWriteMessages(ctx, rssEntry, p.claimCheckStore)
 	if err != nil {
 		return err
 	}
 
-	err = p.publisher.Publish(ctx, string(messageJson))
-	if err != nil {
-		return err
+	for _, writeMessage := range writeMessages {
+		if err := publishEnvelope(ctx, p.publisher, message.TypeWrite, writeMessage); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+)
+
+// Schedule returns the first run time after the given time.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+type every struct {
+	interval time.Duration
+}
+
+// Every runs a job at a fixed interval, like the rate() expressions of EventBridge Scheduler.
+func Every(interval time.Duration) Schedule {
+	return every{interval: interval}
+}
+
+func (e every) Next(after time.Time) time.Time {
+	return after.Add(e.interval)
+}
+
+type dailyAt struct {
+	hour, minute int
+	location     *time.Location
+}
+
+// DailyAt runs a job once a day at hour:minute in location, like cron(minute hour * * ? *).
+func DailyAt(hour int, minute int, location *time.Location) Schedule {
+	return dailyAt{hour: hour, minute: minute, location: location}
+}
+
+func (d dailyAt) Next(after time.Time) time.Time {
+	after = after.In(d.location)
+	next := time.Date(after.Year(), after.Month(), after.Day(), d.hour, d.minute, 0, 0, d.location)
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs in-process in place of EventBridge Scheduler.
+// A job never overlaps itself: its next run is scheduled after the current one returns.
+type Scheduler struct {
+	logger infrastructure.Logger
+	jobs   []Job
+}
+
+func New(logger infrastructure.Logger, jobs ...Job) *Scheduler {
+	return &Scheduler{logger: logger, jobs: jobs}
+}
+
+// Run runs the jobs until ctx is done and returns once the running jobs have returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	for {
+		next := job.Schedule.Next(time.Now())
+		s.logger.Debug("Scheduled", "job", job.Name, "next", next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.logger.Info("Running", "job", job.Name)
+		if err := job.Run(ctx); err != nil {
+			s.logger.Error("Job failed", "job", job.Name, "error", err)
+			continue
+		}
+		s.logger.Info("finish", "job", job.Name)
+	}
+}
@@ -3,11 +3,13 @@ package clean
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/clean/app_service"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 	"github.com/YamazakiNorihito/workday/tests/helper"
 	"github.com/stretchr/testify/assert"
 )
@@ -242,6 +244,124 @@ func TestAppService_Clean(t *testing.T) {
 	})
 }
 
+func TestAppService_Clean_UpdatedItem(t *testing.T) {
+	t.Run("should forward items whose content changed with the previous version as a revision", func(t *testing.T) {
+		// Arrange
+		test_rss := generatorTestRss(t)
+		storedRss := generatorTestRss(t)
+		storedRss.ID = test_rss.ID
+
+		guid := rss.Guid{Value: "http://www.example.com/dummy-guid1"}
+		storedItem := storedRss.Items[guid]
+		storedItem, _ = rss.NewItem(guid, "ダミー記事1(誤字)", storedItem.Link, storedItem.Description, storedItem.Author, storedItem.PubDate)
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				var copy rss.Rss
+				helper.MustSucceed(t, func() error { return deepCopy(storedRss, &copy) })
+				return copy, nil
+			},
+			FindItemsByPkFunc: func(ctx context.Context, source rss.Rss, key rss.Guid) (rss.Rss, error) {
+				var copy rss.Rss
+				helper.MustSucceed(t, func() error { return deepCopy(storedRss, &copy) })
+				copy.Items = map[rss.Guid]rss.Item{}
+				if key == guid {
+					copy.Items[key] = storedItem
+				} else {
+					copy.Items[key] = storedRss.Items[key]
+				}
+				return copy, nil
+			},
+		}
+
+		// Act
+		act_rss, err := app_service.Clean(ctx, &logger, &repo, test_rss)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, act_rss.Items, 1)
+
+		updatedItem := act_rss.Items[guid]
+		assert.Equal(t, "ダミー記事1", updatedItem.Title)
+		assert.Equal(t, test_rss.Items[guid].ContentHash, updatedItem.ContentHash)
+		assert.Len(t, updatedItem.Revisions, 1)
+		assert.Equal(t, "ダミー記事1(誤字)", updatedItem.Revisions[0].Title)
+		assert.Equal(t, storedItem.ContentHash, updatedItem.Revisions[0].ContentHash)
+		assert.False(t, updatedItem.RevisedAt().IsZero())
+	})
+}
+
+func TestAppService_Clean_RepositoryError(t *testing.T) {
+	cause := errors.New("ProvisionedThroughputExceededException")
+	testCases := []struct {
+		name            string
+		findBySourceErr error
+		findItemErr     error
+	}{
+		{"should return the error of reading the feed instead of taking it for a new feed", cause, nil},
+		{"should return the error of reading an item instead of dropping the item", nil, cause},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			test_rss := generatorTestRss(t)
+
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			repo := helper.SpyRssRepository{
+				FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+					return test_rss, tc.findBySourceErr
+				},
+				FindItemsByPkFunc: func(ctx context.Context, source rss.Rss, key rss.Guid) (rss.Rss, error) {
+					return rss.Rss{}, tc.findItemErr
+				},
+			}
+
+			// Act
+			_, err := app_service.Clean(ctx, &logger, &repo, test_rss)
+
+			// Assert
+			assert.ErrorIs(t, err, cause)
+		})
+	}
+}
+
+func TestAppService_Clean_Parts(t *testing.T) {
+	t.Run("should forward only the items of a part that are not stored yet", func(t *testing.T) {
+		// Arrange
+		test_rss := generatorTestRss(t)
+		var parts []rss.Rss
+		helper.MustSucceed(t, func() error {
+			var err error
+			parts, err = message.SplitRss(test_rss, 1)
+			return err
+		})
+		assert.Len(t, parts, 3)
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			_, err := repo.Save(ctx, parts[0], helper.ContractUser)
+			return err
+		})
+
+		// Act
+		redelivered, err1 := app_service.Clean(ctx, &logger, repo, parts[0])
+		next, err2 := app_service.Clean(ctx, &logger, repo, parts[1])
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Empty(t, redelivered.Items)
+		assert.Equal(t, parts[1].Items, next.Items)
+		assert.Equal(t, parts[0].Parts, redelivered.Parts)
+		assert.Equal(t, parts[1].Parts, next.Parts)
+	})
+}
+
 func generatorTestRss(t *testing.T) rss.Rss {
 	var dummy_rss rss.Rss
 	helper.MustSucceed(t, func() error {
@@ -5,8 +5,8 @@ import (
 	"testing"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/create/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
-	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/YamazakiNorihito/workday/tests/helper"
 	"github.com/stretchr/testify/assert"
@@ -15,7 +15,7 @@ import (
 type spyMessageClient struct{ Messages []string }
 
 func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
-	r.Messages = append(r.Messages, message)
+	r.Messages = append(r.Messages, helper.PayloadOf(message))
 	return nil
 }
 
@@ -90,6 +90,25 @@ func TestCreateCommand_Validation(t *testing.T) {
 			})
 		}
 	})
+	t.Run("should report every invalid keyword with its position", func(t *testing.T) {
+		// Arrange
+		command := app_service.CreateCommand{
+			FeedURL:            "http://validurl.com",
+			SourceLanguageCode: "en",
+		}
+		command.ItemFilter.IncludeKeywords = []string{"go", "go(lang"}
+		command.ItemFilter.ExcludeKeywords = []string{"a**", ".*(PHP|php).*"}
+
+		// Act
+		err := validator.Validate(context.Background(), command)
+
+		// Assert
+		var validationErr *validation_error.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Len(t, validationErr.Errors(), 2)
+		assert.Equal(t, `IncludeKeywords[1] is keyword: pattern "go(lang" at position 2: missing closing ): `+"`go(lang`", validationErr.Errors()["IncludeKeywords[1]"])
+		assert.Equal(t, `ExcludeKeywords[0] is keyword: pattern "a**" at position 1: invalid nested repetition operator: `+"`**`", validationErr.Errors()["ExcludeKeywords[0]"])
+	})
 }
 
 func TestAppService_Trigger(t *testing.T) {
@@ -103,8 +122,9 @@ func TestAppService_Trigger(t *testing.T) {
 		command := app_service.CreateCommand{
 			FeedURL:            "https://azure.microsoft.com/ja-jp/blog/feed",
 			SourceLanguageCode: "en",
-			ItemFilter:         rss.NewItemFilter([]string{"Azure", "Cloud", "Microsoft"}, []string{"AWS", "Google Cloud"}),
 		}
+		command.ItemFilter.IncludeKeywords = []string{"Azure", "Cloud", "Microsoft"}
+		command.ItemFilter.ExcludeKeywords = []string{"AWS", "Google Cloud"}
 
 		// Act
 		err := app_service.Trigger(ctx, &logger, *subscribeMessagePublisher, command)
@@ -113,7 +133,53 @@ func TestAppService_Trigger(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, messageClient.Messages, 1)
 		assert.ElementsMatch(t, messageClient.Messages, []string{
-			"{\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]}}",
+			"{\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]},\"retention\":{}}",
+		})
+	})
+
+	t.Run("should publish the filter expression", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+
+		command := app_service.CreateCommand{
+			FeedURL:            "https://azure.microsoft.com/ja-jp/blog/feed",
+			SourceLanguageCode: "en",
+		}
+		command.ItemFilter.Expression = "title~azure AND NOT tag=preview"
+
+		// Act
+		err := app_service.Trigger(ctx, &logger, *subscribeMessagePublisher, command)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, messageClient.Messages, []string{
+			"{\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[],\"expression\":\"title~azure AND NOT tag=preview\"},\"retention\":{}}",
 		})
 	})
+
+	t.Run("should reject an invalid filter expression", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+
+		command := app_service.CreateCommand{
+			FeedURL:            "https://azure.microsoft.com/ja-jp/blog/feed",
+			SourceLanguageCode: "en",
+		}
+		command.ItemFilter.Expression = "title~azure AND"
+
+		// Act
+		err := app_service.Trigger(ctx, &logger, *subscribeMessagePublisher, command)
+
+		// Assert
+		var validationErr *validation_error.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, validationErr.Errors()["item_filter.expression"], "at position 15")
+		assert.Empty(t, messageClient.Messages)
+	})
 }
@@ -8,13 +8,17 @@ import (
 	"time"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/delete/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/tests/helper"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestAppService_Clean(t *testing.T) {
-	t.Run("should delete RSS feed when found by source", func(t *testing.T) {
+func TestAppService_Delete(t *testing.T) {
+	now := time.Date(2024, time.July, 10, 9, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+
+	t.Run("should move the RSS feed to the trash when found by source", func(t *testing.T) {
 		// Arrange
 		test_rss := generatorTestRss(t)
 		ctx := context.Background()
@@ -28,20 +32,42 @@ func TestAppService_Clean(t *testing.T) {
 				}
 				return rss.Rss{}, errors.New("RSS feed not found")
 			},
-			DeleteFunc: func(ctx context.Context, rss rss.Rss) error {
-				act_rss = rss
-				return nil
+			SaveFunc: func(ctx context.Context, r rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				act_rss = r
+				return r, nil
 			},
 		}
 
 		// Act
-		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080")
+		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080", retention, now)
 
 		// Assert
 		assert.NoError(t, err)
 
-		assert.NotEmpty(t, act_rss.ID)
-		assert.Equal(t, test_rss, act_rss)
+		assert.Equal(t, test_rss.ID, act_rss.ID)
+		assert.True(t, act_rss.IsTrashed())
+		assert.Equal(t, now, act_rss.TrashedAt)
+		assert.Equal(t, now.Add(retention), act_rss.PurgeAt)
+	})
+
+	t.Run("should keep the original retention when the RSS feed is already in the trash", func(t *testing.T) {
+		// Arrange
+		test_rss := generatorTestRss(t)
+		test_rss.Trash(now.Add(-time.Hour), retention)
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return test_rss, nil
+			},
+		}
+
+		// Act
+		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080", retention, now)
+
+		// Assert
+		assert.NoError(t, err)
 	})
 
 	t.Run("should return error when RSS feed not found", func(t *testing.T) {
@@ -52,20 +78,35 @@ func TestAppService_Clean(t *testing.T) {
 			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
 				return rss.Rss{}, errors.New("RSS feed not found")
 			},
-			DeleteFunc: func(ctx context.Context, rss rss.Rss) error {
-				return nil
-			},
 		}
 
 		// Act
-		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080")
+		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080", retention, now)
 
 		// Assert
 		assert.Error(t, err)
 		assert.Equal(t, "RSS feed not found", err.Error())
 	})
 
-	t.Run("should return error when Delete fails", func(t *testing.T) {
+	t.Run("should return error when the source is not registered", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{}, nil
+			},
+		}
+
+		// Act
+		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080", retention, now)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "not found source: 127.0.0.1:8080", err.Error())
+	})
+
+	t.Run("should return error when Save fails", func(t *testing.T) {
 		// Arrange
 		test_rss := generatorTestRss(t)
 		ctx := context.Background()
@@ -75,17 +116,17 @@ func TestAppService_Clean(t *testing.T) {
 			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
 				return test_rss, nil
 			},
-			DeleteFunc: func(ctx context.Context, rss rss.Rss) error {
-				return errors.New("failed to delete RSS feed")
+			SaveFunc: func(ctx context.Context, r rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				return r, errors.New("failed to save RSS feed")
 			},
 		}
 
 		// Act
-		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080")
+		err := app_service.Delete(ctx, &logger, &repo, "127.0.0.1:8080", retention, now)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, "failed to delete RSS feed", err.Error())
+		assert.Equal(t, "failed to save RSS feed", err.Error())
 	})
 }
 
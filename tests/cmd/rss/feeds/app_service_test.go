@@ -69,6 +69,39 @@ func TestAppService_AllRssFeeds(t *testing.T) {
 	})
 }
 
+func TestAppService_AllRssFeeds_Trashed(t *testing.T) {
+	t.Run("should not return trashed feeds", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				activeRss, err := rss.New("ダミーニュースのフィード1", "127.0.0.1:8080", "http://127.0.0.1:8080/1", "", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+				if err != nil {
+					return nil, err
+				}
+
+				trashedRss, err := rss.New("ダミーニュースのフィード2", "127.0.0.1:8081", "http://127.0.0.1:8081/2", "", "ja", time.Date(2024, time.July, 4, 14, 0, 0, 0, time.UTC))
+				if err != nil {
+					return nil, err
+				}
+				trashedRss.Trash(time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), 30*24*time.Hour)
+
+				return []rss.Rss{activeRss, trashedRss}, nil
+			},
+		}
+
+		// Act
+		act_rssFeeds, err := app_service.AllRssFeeds(ctx, &logger, &repo)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, act_rssFeeds, 1)
+		assert.Equal(t, "127.0.0.1:8080", act_rssFeeds[0].Source)
+		assert.Equal(t, "active", act_rssFeeds[0].Status)
+	})
+}
+
 func TestAppService_AllRssFeeds_Error(t *testing.T) {
 	t.Run("should return an error when repository returns an error", func(t *testing.T) {
 		// Arrange
@@ -0,0 +1,261 @@
+package filter_preview
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/filter_preview/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+const liveFeed = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーイベントのフィード</title>
+  <link>https://connpass.com/</link>
+  <description>このフィードはダミーイベントを提供します。</description>
+
+  <item>
+    <title>Go もくもく会</title>
+    <guid>https://connpass.com/event/2/</guid>
+    <link>https://connpass.com/event/2/</link>
+    <description>Go を書く会です。</description>
+    <pubDate>Wed, 03 Jul 2024 12:00:00 GMT</pubDate>
+  </item>
+
+  <item>
+    <title>PHP 勉強会</title>
+    <guid>https://connpass.com/event/3/</guid>
+    <link>https://connpass.com/event/3/</link>
+    <description>PHP の勉強会です。</description>
+    <pubDate>Thu, 04 Jul 2024 12:00:00 GMT</pubDate>
+  </item>
+
+</channel>
+</rss>`
+
+func newTestRss(t *testing.T, link string) rss.Rss {
+	var testRss rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		testRss, err = rss.New("ダミーイベントのフィード", "connpass.com", link, "このフィードはダミーイベントを提供します。", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+		item, err := rss.NewItem(rss.Guid{Value: "https://connpass.com/event/1/"}, "Go 勉強会", "https://connpass.com/event/1/", "Go の勉強会です。", "", time.Date(2024, time.July, 1, 12, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+		testRss.AddOrUpdateItem(item)
+		item, err = rss.NewItem(rss.Guid{Value: "https://connpass.com/event/2/"}, "Go もくもく会", "https://connpass.com/event/2/", "Go を書く会です。", "", time.Date(2024, time.July, 3, 12, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+		testRss.AddOrUpdateItem(item)
+		return nil
+	})
+	return testRss
+}
+
+func newRepository(testRss rss.Rss) *helper.SpyRssRepository {
+	return &helper.SpyRssRepository{
+		FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+			return testRss, nil
+		},
+		FindItemsPageFunc: func(ctx context.Context, r rss.Rss, query rss.ItemQuery) (rss.ItemPage, error) {
+			page := rss.ItemPage{}
+			for _, item := range testRss.Items {
+				page.Items = append(page.Items, item)
+			}
+			return page, nil
+		},
+	}
+}
+
+func TestAppService_Preview(t *testing.T) {
+	t.Run("should evaluate the filter against the stored items", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := newRepository(newTestRss(t, "https://connpass.com/explore/ja.atom"))
+		command := app_service.PreviewCommand{Source: "connpass.com", Limit: app_service.DefaultLimit}
+		command.ItemFilter.ExcludeKeywords = []string{".*勉強会.*"}
+
+		// Act
+		response, err := app_service.Preview(ctx, &logger, repo, http.DefaultClient, command)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, app_service.PreviewResponse{
+			Kept:    1,
+			Dropped: 1,
+			Items: []app_service.ItemResponse{
+				{
+					Guid:    "https://connpass.com/event/2/",
+					Title:   "Go もくもく会",
+					Link:    "https://connpass.com/event/2/",
+					PubDate: time.Date(2024, time.July, 3, 12, 0, 0, 0, time.UTC),
+					Stored:  true,
+					Kept:    true,
+					Reason:  rss.FilterReasonKept,
+				},
+				{
+					Guid:    "https://connpass.com/event/1/",
+					Title:   "Go 勉強会",
+					Link:    "https://connpass.com/event/1/",
+					PubDate: time.Date(2024, time.July, 1, 12, 0, 0, 0, time.UTC),
+					Stored:  true,
+					Reason:  rss.FilterReasonExcluded,
+					Pattern: ".*勉強会.*",
+				},
+			},
+		}, response)
+	})
+
+	t.Run("should also evaluate the filter against the items fetched live", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(liveFeed))
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := newRepository(newTestRss(t, server.URL))
+		command := app_service.PreviewCommand{Source: "connpass.com", Limit: app_service.DefaultLimit, Live: true}
+		command.ItemFilter.IncludeKeywords = []string{"Go"}
+		command.ItemFilter.Expression = "NOT title:もくもく"
+
+		// Act
+		response, err := app_service.Preview(ctx, &logger, repo, server.Client(), command)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.Kept)
+		assert.Equal(t, 2, response.Dropped)
+
+		type verdict struct {
+			Guid    string
+			Stored  bool
+			Kept    bool
+			Reason  string
+			Pattern string
+		}
+		var actual []verdict
+		for _, item := range response.Items {
+			actual = append(actual, verdict{item.Guid, item.Stored, item.Kept, item.Reason, item.Pattern})
+		}
+		assert.Equal(t, []verdict{
+			{"https://connpass.com/event/3/", false, false, rss.FilterReasonNotIncluded, ""},
+			{"https://connpass.com/event/2/", true, false, rss.FilterReasonExpression, "NOT title:もくもく"},
+			{"https://connpass.com/event/1/", true, true, rss.FilterReasonKept, "Go"},
+		}, actual)
+	})
+
+	t.Run("should page the stored items and fetch the live ones on the first page only", func(t *testing.T) {
+		// Arrange
+		fetches := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(liveFeed))
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := newTestRss(t, server.URL)
+		var queries []rss.ItemQuery
+		repo := newRepository(testRss)
+		repo.FindItemsPageFunc = func(ctx context.Context, r rss.Rss, query rss.ItemQuery) (rss.ItemPage, error) {
+			queries = append(queries, query)
+			if query.Cursor == "" {
+				return rss.ItemPage{Items: []rss.Item{testRss.Items[rss.Guid{Value: "https://connpass.com/event/2/"}]}, NextCursor: "next"}, nil
+			}
+			return rss.ItemPage{Items: []rss.Item{testRss.Items[rss.Guid{Value: "https://connpass.com/event/1/"}]}}, nil
+		}
+
+		// Act
+		first, err := app_service.Preview(ctx, &logger, repo, server.Client(), app_service.PreviewCommand{Source: "connpass.com", Limit: 1, Live: true})
+		assert.NoError(t, err)
+		second, err := app_service.Preview(ctx, &logger, repo, server.Client(), app_service.PreviewCommand{Source: "connpass.com", Limit: 1, Cursor: first.NextCursor, Live: true})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []rss.ItemQuery{{Limit: 1}, {Limit: 1, Cursor: "next"}}, queries)
+		assert.Equal(t, 1, fetches)
+		assert.Equal(t, "next", first.NextCursor)
+		assert.Len(t, first.Items, 2)
+		assert.Empty(t, second.NextCursor)
+		assert.Len(t, second.Items, 1)
+		assert.Equal(t, "https://connpass.com/event/1/", second.Items[0].Guid)
+	})
+
+	t.Run("should return ErrFetchFeed when the live fetch fails", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := newRepository(newTestRss(t, server.URL))
+		command := app_service.PreviewCommand{Source: "connpass.com", Limit: app_service.DefaultLimit, Live: true}
+
+		// Act
+		_, err := app_service.Preview(ctx, &logger, repo, server.Client(), command)
+
+		// Assert
+		assert.ErrorIs(t, err, app_service.ErrFetchFeed)
+	})
+
+	testCases := []struct {
+		name    string
+		modify  func(command *app_service.PreviewCommand)
+		find    func(r rss.Rss) rss.Rss
+		errorOn string
+	}{
+		{"should return a validation error for an unknown source", nil, func(r rss.Rss) rss.Rss { return rss.Rss{} }, "source"},
+		{"should return a validation error for an invalid keyword", func(command *app_service.PreviewCommand) {
+			command.ItemFilter.ExcludeKeywords = []string{"(勉強会"}
+		}, nil, "ExcludeKeywords[0]"},
+		{"should return a validation error for an invalid expression", func(command *app_service.PreviewCommand) {
+			command.ItemFilter.Expression = "title:go AND"
+		}, nil, "item_filter.expression"},
+		{"should return a validation error for a limit over the maximum", func(command *app_service.PreviewCommand) {
+			command.Limit = 501
+		}, nil, "Limit"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			testRss := newTestRss(t, "https://connpass.com/explore/ja.atom")
+			if tc.find != nil {
+				testRss = tc.find(testRss)
+			}
+			repo := newRepository(testRss)
+			command := app_service.PreviewCommand{Source: "connpass.com", Limit: app_service.DefaultLimit}
+			if tc.modify != nil {
+				tc.modify(&command)
+			}
+
+			// Act
+			_, err := app_service.Preview(ctx, &logger, repo, http.DefaultClient, command)
+
+			// Assert
+			validationErr, ok := err.(*validation_error.ValidationError)
+			assert.True(t, ok)
+			assert.Contains(t, validationErr.Error(), tc.errorOn)
+		})
+	}
+}
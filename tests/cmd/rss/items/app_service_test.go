@@ -0,0 +1,159 @@
+package items
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/items/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRss(t *testing.T) rss.Rss {
+	var testRss rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		testRss, err = rss.New("ダミーニュースのフィード", "connpass.com", "https://connpass.com/explore/ja.atom", "このフィードはダミーニュースを提供します。", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+		return err
+	})
+	return testRss
+}
+
+func TestAppService_ListItems(t *testing.T) {
+	t.Run("should pass the conditions to the repository and return the page", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := newTestRss(t)
+		since := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+		until := time.Date(2024, time.July, 31, 0, 0, 0, 0, time.UTC)
+
+		var actualQuery rss.ItemQuery
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+			FindItemsPageFunc: func(ctx context.Context, r rss.Rss, query rss.ItemQuery) (rss.ItemPage, error) {
+				actualQuery = query
+				return rss.ItemPage{
+					Items: []rss.Item{
+						{
+							Guid:    rss.Guid{Value: "guid-1"},
+							Title:   "タイトル1",
+							Link:    "https://connpass.com/event/1/",
+							PubDate: time.Date(2024, time.July, 2, 0, 0, 0, 0, time.UTC),
+							Tags:    []string{"Go"},
+						},
+					},
+					NextCursor: "next",
+				}, nil
+			},
+		}
+		command := app_service.ListCommand{
+			Source: "connpass.com",
+			Limit:  10,
+			Cursor: "cursor",
+			Order:  app_service.OrderAsc,
+			Since:  since,
+			Until:  until,
+			Tags:   []string{"Go"},
+		}
+
+		// Act
+		response, err := app_service.ListItems(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rss.ItemQuery{
+			Since:     since,
+			Until:     until,
+			Tags:      []string{"Go"},
+			Ascending: true,
+			Limit:     10,
+			Cursor:    "cursor",
+		}, actualQuery)
+
+		assert.Len(t, response.Items, 1)
+		assert.Equal(t, "guid-1", response.Items[0].Guid)
+		assert.Equal(t, "タイトル1", response.Items[0].Title)
+		assert.Equal(t, []string{"Go"}, response.Items[0].Tags)
+		assert.Equal(t, "next", response.NextCursor)
+	})
+
+	t.Run("should return validation error when command is invalid", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			command app_service.ListCommand
+		}{
+			{"limit is too large", app_service.ListCommand{Source: "connpass.com", Limit: 101, Order: app_service.OrderDesc}},
+			{"limit is zero", app_service.ListCommand{Source: "connpass.com", Limit: 0, Order: app_service.OrderDesc}},
+			{"order is unknown", app_service.ListCommand{Source: "connpass.com", Limit: 10, Order: "random"}},
+			{"since is after until", app_service.ListCommand{
+				Source: "connpass.com",
+				Limit:  10,
+				Order:  app_service.OrderDesc,
+				Since:  time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+				Until:  time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+			}},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Arrange
+				ctx := context.Background()
+				logger := helper.MockLogger{}
+				repo := helper.SpyRssRepository{}
+
+				// Act
+				_, err := app_service.ListItems(ctx, &logger, &repo, tc.command)
+
+				// Assert
+				assert.IsType(t, &validation_error.ValidationError{}, err)
+			})
+		}
+	})
+
+	t.Run("should return validation error when source is not found", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{}, nil
+			},
+		}
+		command := app_service.ListCommand{Source: "unknown.com", Limit: 10, Order: app_service.OrderDesc}
+
+		// Act
+		_, err := app_service.ListItems(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.IsType(t, &validation_error.ValidationError{}, err)
+	})
+
+	t.Run("should return validation error when cursor is invalid", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := newTestRss(t)
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+			FindItemsPageFunc: func(ctx context.Context, r rss.Rss, query rss.ItemQuery) (rss.ItemPage, error) {
+				return rss.ItemPage{}, rss.ErrInvalidCursor
+			},
+		}
+		command := app_service.ListCommand{Source: "connpass.com", Limit: 10, Order: app_service.OrderDesc, Cursor: "broken"}
+
+		// Act
+		_, err := app_service.ListItems(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.IsType(t, &validation_error.ValidationError{}, err)
+		assert.Contains(t, err.Error(), "cursor")
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/items/handler"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func main() {
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/feeds/connpass.com/items",
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		PathParameters: map[string]string{
+			"source": "connpass.com",
+		},
+		QueryStringParameters: map[string]string{
+			"limit": "10",
+		},
+	}
+
+	response, err := handler.Handler(context.Background(), event)
+
+	// 結果をコンソールに表示
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Response: %+v\n", response)
+	}
+}
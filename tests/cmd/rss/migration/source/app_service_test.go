@@ -0,0 +1,176 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/migration/source/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStoredRss(t *testing.T, source, link string) rss.Rss {
+	t.Helper()
+	var r rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		r, err = rss.New("title", source, link, "description", "ja", time.Now())
+		return err
+	})
+	r.CreatedBy = metadata.CreateBy{ID: "user", Name: "user"}
+	r.CreatedAt = metadata.CreateAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	return r
+}
+
+func TestAppService_Migrate(t *testing.T) {
+	t.Run("should move feeds keyed by host onto the per-feed source", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		legacyRss := newStoredRss(t, "example.com", "https://example.com/feed")
+		expectedSource, _ := rss.NewSource(legacyRss.Link)
+		alreadyMigratedSource, _ := rss.NewSource("https://example.com/other")
+		migratedRss := newStoredRss(t, alreadyMigratedSource, "https://example.com/other")
+
+		var saved []rss.Rss
+		var deleted []rss.Rss
+		rssRepository := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{legacyRss, migratedRss}, nil
+			},
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{}, nil
+			},
+			FindItemsFunc: func(ctx context.Context, r rss.Rss) (rss.Rss, error) {
+				return r, nil
+			},
+			SaveFunc: func(ctx context.Context, r rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				saved = append(saved, r)
+				return r, nil
+			},
+			DeleteFunc: func(ctx context.Context, r rss.Rss) error {
+				deleted = append(deleted, r)
+				return nil
+			},
+		}
+
+		// Act
+		result, err := app_service.Migrate(ctx, &logger, &rssRepository, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, app_service.Result{Migrated: 1, Skipped: 0}, result)
+
+		assert.Len(t, saved, 1)
+		assert.Equal(t, legacyRss.ID, saved[0].ID)
+		assert.Equal(t, expectedSource, saved[0].Source)
+		assert.Equal(t, legacyRss.CreatedAt, saved[0].CreatedAt)
+
+		assert.Len(t, deleted, 1)
+		assert.Equal(t, "example.com", deleted[0].Source)
+	})
+
+	t.Run("should carry the fetch status over to the new source", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		legacyRss := newStoredRss(t, "example.com", "https://example.com/feed")
+		legacyRss.FetchStatus = rss.FetchStatus{
+			LastFailureAt:       time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			LastError:           "404 Not Found",
+			ConsecutiveFailures: 5,
+			HTTPStatus:          404,
+			DisabledAt:          time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		}
+		expectedSource, _ := rss.NewSource(legacyRss.Link)
+
+		var calls []string
+		var savedStatus rss.FetchStatus
+		var savedStatusSource string
+		rssRepository := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{legacyRss}, nil
+			},
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{}, nil
+			},
+			FindItemsFunc: func(ctx context.Context, r rss.Rss) (rss.Rss, error) {
+				return r, nil
+			},
+			SaveFunc: func(ctx context.Context, r rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				calls = append(calls, "Save")
+				return r, nil
+			},
+			SaveFetchStatusFunc: func(ctx context.Context, r rss.Rss, status rss.FetchStatus) error {
+				calls = append(calls, "SaveFetchStatus")
+				savedStatus, savedStatusSource = status, r.Source
+				return nil
+			},
+			DeleteFunc: func(ctx context.Context, r rss.Rss) error {
+				calls = append(calls, "Delete")
+				return nil
+			},
+		}
+
+		// Act
+		_, err := app_service.Migrate(ctx, &logger, &rssRepository, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Save", "SaveFetchStatus", "Delete"}, calls)
+		assert.Equal(t, expectedSource, savedStatusSource)
+		assert.Equal(t, legacyRss.FetchStatus, savedStatus)
+	})
+
+	t.Run("should not write anything on dry run", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		legacyRss := newStoredRss(t, "example.com", "https://example.com/feed")
+		rssRepository := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{legacyRss}, nil
+			},
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{}, nil
+			},
+		}
+
+		// Act
+		result, err := app_service.Migrate(ctx, &logger, &rssRepository, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, app_service.Result{Migrated: 1, Skipped: 0}, result)
+	})
+
+	t.Run("should skip feeds whose new source is taken by another feed", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		legacyRss := newStoredRss(t, "example.com", "https://example.com/feed")
+		rssRepository := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{legacyRss}, nil
+			},
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{ID: uuid.New(), Source: source}, nil
+			},
+		}
+
+		// Act
+		result, err := app_service.Migrate(ctx, &logger, &rssRepository, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, app_service.Result{Migrated: 0, Skipped: 1}, result)
+	})
+}
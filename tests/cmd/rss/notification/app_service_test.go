@@ -154,6 +154,104 @@ func TestAppService_Notification(t *testing.T) {
 	})
 }
 
+func TestAppService_Notification_UpdatedItem(t *testing.T) {
+	t.Run("should label revised items as updated", func(t *testing.T) {
+		// Arrange
+		dummy_rss := generatorTestRss(t)
+		guid := rss.Guid{Value: "http://www.example.com/dummy-guid2"}
+		item := dummy_rss.Items[guid]
+		previous := item
+		previous.Title = "ダミー記事2(誤字)"
+		item.Revise(previous, time.Date(2024, time.July, 3, 14, 0, 0, 0, time.UTC))
+		dummy_rss.Items[guid] = item
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return dummy_rss, nil
+			},
+			FindItemsFunc: func(ctx context.Context, r rss.Rss) (rss.Rss, error) {
+				return dummy_rss, nil
+			},
+		}
+		slackChannelClient := spySlackChannelClient{}
+
+		conditions := app_service.RssConditions{
+			Target: func(rss.Rss) bool { return true },
+			ItemFilter: func(item rss.Item) bool {
+				return item.Guid == guid
+			},
+		}
+
+		// Act
+		err := app_service.Notification(ctx, &logger, &repo, &slackChannelClient, conditions, "127.0.0.1:8080")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, slackChannelClient.Calls, 1)
+		assert.Equal(t, `*フィードタイトル:* <http://127.0.0.1:8080|ダミーニュースのフィード>
+*フィード詳細:* このフィードはダミーニュースを提供します。
+*最終更新日:* 2024-07-03T13:00:00Z
+
+*最新の記事:*
+1. *記事タイトル(更新):* <http://www.example.com/dummy-article2|ダミー記事2>
+    *公開日:* 2024-07-03T12:30:00Z
+    *更新日:* 2024-07-03T14:00:00Z
+    *概要:* これはダミー記事2の概要です。詳細はリンクをクリックしてください。
+
+`, slackChannelClient.Calls[0].Text)
+	})
+}
+
+func TestAppService_NewRssConditions_UpdatedItems(t *testing.T) {
+	now := time.Date(2024, time.July, 4, 13, 0, 0, 0, time.UTC)
+	guid := rss.Guid{Value: "http://www.example.com/dummy-guid2"}
+
+	testCases := []struct {
+		name      string
+		revisedAt time.Time
+		expected  int
+	}{
+		{"should not notify a feed saved recently whose items were not revised", time.Time{}, 0},
+		{"should notify a feed whose item was revised within the threshold", now.Add(-time.Minute), 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			dummy_rss := generatorTestRss(t)
+			dummy_rss.UpdatedAt = now
+			if !tc.revisedAt.IsZero() {
+				item := dummy_rss.Items[guid]
+				previous := item
+				previous.Title = "ダミー記事2(誤字)"
+				item.Revise(previous, tc.revisedAt)
+				dummy_rss.Items[guid] = item
+			}
+
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			repo := helper.SpyRssRepository{
+				FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+					return dummy_rss, nil
+				},
+				FindItemsFunc: func(ctx context.Context, r rss.Rss) (rss.Rss, error) {
+					return dummy_rss, nil
+				},
+			}
+			slackChannelClient := spySlackChannelClient{}
+			conditions := app_service.NewRssConditions(&logger, now, false, true)
+
+			// Act
+			err := app_service.Notification(ctx, &logger, &repo, &slackChannelClient, conditions, "127.0.0.1:8080")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, slackChannelClient.Calls, tc.expected)
+		})
+	}
+}
+
 func generatorTestRss(t *testing.T) rss.Rss {
 	var dummy_rss rss.Rss
 	helper.MustSucceed(t, func() error {
@@ -179,3 +277,69 @@ func generatorTestRss(t *testing.T) rss.Rss {
 
 	return dummy_rss
 }
+
+func TestAppService_IsNewFeed(t *testing.T) {
+	now := time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		saved    app_service.SavedFeed
+		expected bool
+	}{
+		{"should be new when the row was just inserted", app_service.SavedFeed{Inserted: true, CreatedAt: now.Add(-time.Minute), Version: 1}, true},
+		{"should not be new when the row was updated", app_service.SavedFeed{CreatedAt: now.Add(-time.Minute), Version: 2}, false},
+		{"should not be new when the row was re-inserted by the source migration", app_service.SavedFeed{Inserted: true, CreatedAt: now.Add(-24 * time.Hour), Version: 1}, false},
+		{"should be new when the save completes a fetch in parts that created the feed", app_service.SavedFeed{CreatedAt: now.Add(-time.Minute), Version: 2, Parts: &rss.FetchParts{FetchID: "fetch", Parts: 2, Written: []int{1, 2}, NewFeed: true, CompletedVersion: 2}}, true},
+		{"should not be new when a later save keeps the parts of a fetch that created the feed", app_service.SavedFeed{CreatedAt: now.Add(-time.Minute), Version: 3, Parts: &rss.FetchParts{FetchID: "fetch", Parts: 2, Written: []int{1, 2}, NewFeed: true, CompletedVersion: 2}}, false},
+		{"should not be new when the save completes a fetch in parts of a registered feed", app_service.SavedFeed{CreatedAt: now.Add(-time.Minute), Version: 5, Parts: &rss.FetchParts{FetchID: "fetch", Parts: 2, Written: []int{1, 2}, CompletedVersion: 5}}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual := app_service.IsNewFeed(now, tc.saved)
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAppService_IsAnnounced(t *testing.T) {
+	testCases := []struct {
+		name      string
+		updatedBy string
+		parts     *rss.FetchParts
+		expected  bool
+	}{
+		{"should announce a fetch that was not made in parts", "www.example.com", nil, true},
+		{"should not announce a save by the API", "api", nil, false},
+		{"should not announce a fetch in parts before every part is written", "www.example.com", &rss.FetchParts{FetchID: "fetch", Parts: 3, Written: []int{1, 3}}, false},
+		{"should announce a fetch in parts once every part is written", "www.example.com", &rss.FetchParts{FetchID: "fetch", Parts: 3, Written: []int{1, 2, 3}, CompletedVersion: 4}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual := app_service.IsAnnounced(app_service.SavedFeed{Source: "www.example.com", Version: 4, Parts: tc.parts, UpdatedBy: tc.updatedBy})
+
+			// Assert
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestAppService_NewRssConditions(t *testing.T) {
+	t.Run("should filter the items of a feed that is not new without calling Target first", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC)
+		logger := helper.MockLogger{}
+		conditions := app_service.NewRssConditions(&logger, now, app_service.IsNewFeed(now, app_service.SavedFeed{Inserted: true, CreatedAt: now.Add(-24 * time.Hour), Version: 1}), false)
+
+		// Act
+		oldItem := conditions.ItemFilter(rss.Item{PubDate: now.Add(-24 * time.Hour)})
+		newItem := conditions.ItemFilter(rss.Item{PubDate: now.Add(-time.Minute)})
+
+		// Assert
+		assert.False(t, oldItem)
+		assert.True(t, newItem)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/patch/app_service"
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validator"
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/YamazakiNorihito/workday/tests/helper"
@@ -17,7 +18,7 @@ import (
 type spyMessageClient struct{ Messages []string }
 
 func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
-	r.Messages = append(r.Messages, message)
+	r.Messages = append(r.Messages, helper.PayloadOf(message))
 	return nil
 }
 
@@ -87,6 +88,22 @@ func TestPatchCommand_Validation(t *testing.T) {
 			})
 		}
 	})
+	t.Run("should reject an invalid exclude keyword with its position", func(t *testing.T) {
+		// Arrange
+		command := app_service.PatchCommand{
+			Source:             "connpass.com",
+			SourceLanguageCode: "en",
+		}
+		command.ItemFilter.ExcludeKeywords = []string{".*勉強会.*", ".*(PHP|php.*"}
+
+		// Act
+		err := validator.Validate(context.Background(), command)
+
+		// Assert
+		var validationErr *validation_error.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, validationErr.Errors()["ExcludeKeywords[1]"], "at position 2: missing closing )")
+	})
 }
 
 func TestAppService_Update(t *testing.T) {
@@ -94,6 +111,7 @@ func TestAppService_Update(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		logger := helper.MockLogger{}
+		var savedRss rss.Rss
 		repo := helper.SpyRssRepository{
 			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
 				dummy_rss1, err := rss.New(
@@ -106,6 +124,10 @@ func TestAppService_Update(t *testing.T) {
 				)
 				return dummy_rss1, err
 			},
+			SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				savedRss = entryRss
+				return entryRss, nil
+			},
 		}
 		messageClient := spyMessageClient{}
 		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
@@ -113,23 +135,21 @@ func TestAppService_Update(t *testing.T) {
 		command := app_service.PatchCommand{
 			Source:             "connpass.com",
 			SourceLanguageCode: "en",
-			ItemFilter: struct {
-				IncludeKeywords []string
-				ExcludeKeywords []string
-			}{
-				IncludeKeywords: []string{"Azure", "Cloud", "Microsoft"},
-				ExcludeKeywords: []string{"AWS", "Google Cloud"},
-			},
 		}
+		command.ItemFilter.IncludeKeywords = []string{"Azure", "Cloud", "Microsoft"}
+		command.ItemFilter.ExcludeKeywords = []string{"AWS", "Google Cloud"}
 
 		// Act
 		err := app_service.Update(ctx, &logger, &repo, *subscribeMessagePublisher, command)
 
 		// Assert
 		assert.NoError(t, err)
+		assert.Equal(t, "en", savedRss.Language)
+		assert.Equal(t, []string{"Azure", "Cloud", "Microsoft"}, savedRss.ItemFilter.IncludeKeywords)
+		assert.Equal(t, []string{"AWS", "Google Cloud"}, savedRss.ItemFilter.ExcludeKeywords)
 		assert.Len(t, messageClient.Messages, 1)
 		assert.ElementsMatch(t, messageClient.Messages, []string{
-			"{\"feed_url\":\"https://connpass.com/explore/ja.atom\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]}}",
+			"{\"source\":\"connpass.com\",\"feed_url\":\"https://connpass.com/explore/ja.atom\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]},\"retention\":{}}",
 		})
 	})
 }
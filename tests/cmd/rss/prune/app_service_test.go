@@ -0,0 +1,113 @@
+package prune
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/prune/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRss(t *testing.T, source string, retention rss.Retention) rss.Rss {
+	var testRss rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		testRss, err = rss.New("ダミーニュースのフィード", source, "http://"+source, "このフィードはダミーニュースを提供します。", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+		return err
+	})
+	testRss.SetRetention(retention)
+	return testRss
+}
+
+func newTestItem(guid string, pubDate time.Time) rss.Item {
+	return rss.Item{Guid: rss.Guid{Value: guid}, Title: guid, Link: "http://example.com/" + guid, PubDate: pubDate}
+}
+
+func TestAppService_Prune(t *testing.T) {
+	now := time.Date(2024, time.August, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should delete expired items and items beyond the max items", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		feed := newTestRss(t, "127.0.0.1:8080", rss.NewRetention(7, 2))
+		unlimitedRss := newTestRss(t, "127.0.0.1:8081", rss.Retention{})
+		trashedRss := newTestRss(t, "127.0.0.1:8082", rss.NewRetention(7, 2))
+		trashedRss.Trash(now, 30*24*time.Hour)
+
+		deleted := map[string][]rss.Guid{}
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{feed, unlimitedRss, trashedRss}, nil
+			},
+			FindItemsPagesFunc: func(ctx context.Context, r rss.Rss, fn func(items []rss.Item) bool) error {
+				if r.Source != feed.Source {
+					t.Fatalf("unexpected feed: %s", r.Source)
+				}
+				fn([]rss.Item{
+					newTestItem("expired", now.Add(-8*24*time.Hour)),
+					newTestItem("oldest", now.Add(-3*24*time.Hour)),
+				})
+				fn([]rss.Item{
+					newTestItem("older", now.Add(-2*24*time.Hour)),
+					newTestItem("newest", now.Add(-1*24*time.Hour)),
+				})
+				return nil
+			},
+			DeleteItemsFunc: func(ctx context.Context, r rss.Rss, guids []rss.Guid) error {
+				deleted[r.Source] = guids
+				return nil
+			},
+		}
+
+		// Act
+		pruned, err := app_service.Prune(ctx, &logger, &repo, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, pruned)
+		assert.Equal(t, map[string][]rss.Guid{
+			"127.0.0.1:8080": {{Value: "expired"}, {Value: "oldest"}},
+		}, deleted)
+	})
+
+	t.Run("should try every feed and return the first error", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		failingRss := newTestRss(t, "127.0.0.1:8081", rss.NewRetention(7, 0))
+		feed := newTestRss(t, "127.0.0.1:8082", rss.NewRetention(7, 0))
+
+		var deleted []string
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{failingRss, feed}, nil
+			},
+			FindItemsPagesFunc: func(ctx context.Context, r rss.Rss, fn func(items []rss.Item) bool) error {
+				fn([]rss.Item{newTestItem("expired", now.Add(-8*24*time.Hour))})
+				return nil
+			},
+			DeleteItemsFunc: func(ctx context.Context, r rss.Rss, guids []rss.Guid) error {
+				if r.Source == failingRss.Source {
+					return errors.New("failed to delete items")
+				}
+				deleted = append(deleted, r.Source)
+				return nil
+			},
+		}
+
+		// Act
+		pruned, err := app_service.Prune(ctx, &logger, &repo, now)
+
+		// Assert
+		assert.EqualError(t, err, "failed to delete items")
+		assert.Equal(t, 1, pruned)
+		assert.Equal(t, []string{"127.0.0.1:8082"}, deleted)
+	})
+}
@@ -0,0 +1,81 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/purge/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppService_Purge(t *testing.T) {
+	now := time.Date(2024, time.August, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should delete only trashed feeds whose retention has expired", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		activeRss := helper.NewRss(t, "127.0.0.1:8080", "http://127.0.0.1:8080")
+		expiredRss := helper.NewRss(t, "127.0.0.1:8081", "http://127.0.0.1:8081")
+		expiredRss.Trash(now.Add(-31*24*time.Hour), 30*24*time.Hour)
+		retainedRss := helper.NewRss(t, "127.0.0.1:8082", "http://127.0.0.1:8082")
+		retainedRss.Trash(now.Add(-time.Hour), 30*24*time.Hour)
+
+		var deleted []string
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{activeRss, expiredRss, retainedRss}, nil
+			},
+			DeleteFunc: func(ctx context.Context, r rss.Rss) error {
+				deleted = append(deleted, r.Source)
+				return nil
+			},
+		}
+
+		// Act
+		purged, err := app_service.Purge(ctx, &logger, &repo, now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, []string{"127.0.0.1:8081"}, deleted)
+	})
+
+	t.Run("should try every expired feed and return the first error", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		failingRss := helper.NewRss(t, "127.0.0.1:8081", "http://127.0.0.1:8081")
+		failingRss.Trash(now.Add(-31*24*time.Hour), 30*24*time.Hour)
+		expiredRss := helper.NewRss(t, "127.0.0.1:8082", "http://127.0.0.1:8082")
+		expiredRss.Trash(now.Add(-31*24*time.Hour), 30*24*time.Hour)
+
+		var deleted []string
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{failingRss, expiredRss}, nil
+			},
+			DeleteFunc: func(ctx context.Context, r rss.Rss) error {
+				if r.Source == failingRss.Source {
+					return errors.New("failed to delete RSS feed")
+				}
+				deleted = append(deleted, r.Source)
+				return nil
+			},
+		}
+
+		// Act
+		purged, err := app_service.Purge(ctx, &logger, &repo, now)
+
+		// Assert
+		assert.EqualError(t, err, "failed to delete RSS feed")
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, []string{"127.0.0.1:8082"}, deleted)
+	})
+}
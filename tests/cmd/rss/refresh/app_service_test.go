@@ -0,0 +1,149 @@
+package refresh
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/refresh/app_service"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+type spyMessageClient struct{ Messages []string }
+
+func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
+	r.Messages = append(r.Messages, helper.PayloadOf(message))
+	return nil
+}
+
+func newTestRss(t *testing.T) rss.Rss {
+	var testRss rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		testRss, err = rss.New("ダミーニュースのフィード", "connpass.com-0123456789ab", "https://connpass.com/explore/ja.atom", "このフィードはダミーニュースを提供します。", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+		return err
+	})
+	testRss.SetItemFilter(nil, []string{".*勉強会.*"})
+	testRss.SetCacheValidators(`"v1"`, "Wed, 03 Jul 2024 13:00:00 GMT")
+	return testRss
+}
+
+func TestAppService_Refresh(t *testing.T) {
+	now := time.Date(2024, time.July, 10, 9, 0, 0, 0, time.UTC)
+	minInterval := 5 * time.Minute
+
+	t.Run("should publish a subscribe message for the feed", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := newTestRss(t)
+		messageClient := spyMessageClient{}
+		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+
+		var reservedAt time.Time
+		var reservedInterval time.Duration
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+			ReserveRefreshFunc: func(ctx context.Context, r rss.Rss, now time.Time, minInterval time.Duration) (bool, error) {
+				reservedAt = now
+				reservedInterval = minInterval
+				return true, nil
+			},
+		}
+		command := app_service.RefreshCommand{Source: testRss.Source}
+
+		// Act
+		err := app_service.Refresh(ctx, &logger, &repo, *subscribeMessagePublisher, minInterval, now, command)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, now, reservedAt)
+		assert.Equal(t, minInterval, reservedInterval)
+
+		assert.Len(t, messageClient.Messages, 1)
+		var actual message.Subscribe
+		assert.NoError(t, json.Unmarshal([]byte(messageClient.Messages[0]), &actual))
+		assert.Equal(t, message.Subscribe{
+			Source:       "connpass.com-0123456789ab",
+			FeedURL:      "https://connpass.com/explore/ja.atom",
+			Language:     "ja",
+			ItemFilter:   testRss.ItemFilter,
+			ETag:         `"v1"`,
+			LastModified: "Wed, 03 Jul 2024 13:00:00 GMT",
+		}, actual)
+	})
+
+	t.Run("should return ErrTooManyRequests and not publish when rate limited", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := newTestRss(t)
+		messageClient := spyMessageClient{}
+		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+			ReserveRefreshFunc: func(ctx context.Context, r rss.Rss, now time.Time, minInterval time.Duration) (bool, error) {
+				return false, nil
+			},
+		}
+		command := app_service.RefreshCommand{Source: testRss.Source}
+
+		// Act
+		err := app_service.Refresh(ctx, &logger, &repo, *subscribeMessagePublisher, minInterval, now, command)
+
+		// Assert
+		assert.ErrorIs(t, err, app_service.ErrTooManyRequests)
+		assert.Empty(t, messageClient.Messages)
+	})
+
+	testCases := []struct {
+		name   string
+		modify func(r *rss.Rss)
+		find   func(r rss.Rss) rss.Rss
+	}{
+		{"should return a validation error for an unknown source", nil, func(r rss.Rss) rss.Rss { return rss.Rss{} }},
+		{"should return a validation error for a paused feed", func(r *rss.Rss) { r.Pause() }, nil},
+		{"should return a validation error for a trashed feed", func(r *rss.Rss) { r.Trash(now, 24*time.Hour) }, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			testRss := newTestRss(t)
+			if tc.modify != nil {
+				tc.modify(&testRss)
+			}
+			if tc.find != nil {
+				testRss = tc.find(testRss)
+			}
+			messageClient := spyMessageClient{}
+			subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+
+			repo := helper.SpyRssRepository{
+				FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+					return testRss, nil
+				},
+			}
+			command := app_service.RefreshCommand{Source: "connpass.com-0123456789ab"}
+
+			// Act
+			err := app_service.Refresh(ctx, &logger, &repo, *subscribeMessagePublisher, minInterval, now, command)
+
+			// Assert
+			assert.IsType(t, &validation_error.ValidationError{}, err)
+			assert.Empty(t, messageClient.Messages)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/refresh/handler"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func main() {
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Path:       "/feeds/connpass.com/refresh",
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		PathParameters: map[string]string{
+			"source": "connpass.com",
+		},
+	}
+
+	response, err := handler.Handler(context.Background(), event)
+
+	// 結果をコンソールに表示
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Response: %+v\n", response)
+	}
+}
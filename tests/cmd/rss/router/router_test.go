@@ -0,0 +1,124 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/api_gateway/router"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(received *events.APIGatewayProxyRequest) *router.Router {
+	sut := router.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	record := func(name string) router.ProxyHandler {
+		return func(ctx context.Context, logger infrastructure.Logger, request events.APIGatewayProxyRequest) events.APIGatewayProxyResponse {
+			*received = request
+			return events.APIGatewayProxyResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Content-Type": "application/json"},
+				Body:       `{"handler":"` + name + `"}`,
+			}
+		}
+	}
+	sut.Handle(http.MethodGet, "/api/v1/rss", record("feeds"))
+	sut.Handle(http.MethodPost, "/api/v1/rss", record("create"))
+	sut.Handle(http.MethodGet, "/api/v1/rss/trash", record("trash"))
+	sut.Handle(http.MethodGet, "/api/v1/rss/{source}", record("feed_id"))
+	sut.Handle(http.MethodPatch, "/api/v1/rss/{source}", record("patch"))
+	sut.Handle(http.MethodGet, "/api/v1/rss/{source}/items", record("items"))
+	return sut
+}
+
+func serve(sut http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	sut.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func handlerOf(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	var body struct {
+		Handler string `json:"handler"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return body.Handler
+}
+
+func TestRouter_ServeHTTP(t *testing.T) {
+	t.Run("should route by method and resource", func(t *testing.T) {
+		// Arrange
+		var received events.APIGatewayProxyRequest
+		sut := newRouter(&received)
+
+		// Act
+		feeds := serve(sut, http.MethodGet, "/api/v1/rss", "")
+		create := serve(sut, http.MethodPost, "/api/v1/rss", `{"feed_url":"http://www.example.com/feed"}`)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, feeds.Code)
+		assert.Equal(t, "feeds", handlerOf(t, feeds))
+		assert.Equal(t, "create", handlerOf(t, create))
+		assert.Equal(t, `{"feed_url":"http://www.example.com/feed"}`, received.Body)
+		assert.Equal(t, http.MethodPost, received.HTTPMethod)
+		assert.Equal(t, "/api/v1/rss", received.Resource)
+		assert.Equal(t, "application/json", create.Header().Get("Content-Type"))
+	})
+
+	t.Run("should pass the path and query parameters", func(t *testing.T) {
+		// Arrange
+		var received events.APIGatewayProxyRequest
+		sut := newRouter(&received)
+
+		// Act
+		recorder := serve(sut, http.MethodGet, "/api/v1/rss/www.example.com%2Ffeed/items?limit=10&tag=go&tag=aws", "")
+
+		// Assert
+		assert.Equal(t, "items", handlerOf(t, recorder))
+		assert.Equal(t, "/api/v1/rss/{source}/items", received.Resource)
+		assert.Equal(t, map[string]string{"source": "www.example.com/feed"}, received.PathParameters)
+		assert.Equal(t, "10", received.QueryStringParameters["limit"])
+		assert.Equal(t, []string{"go", "aws"}, received.MultiValueQueryStringParameters["tag"])
+		assert.Equal(t, "application/json", received.Headers["Content-Type"])
+	})
+
+	t.Run("should prefer a literal segment over a path parameter", func(t *testing.T) {
+		// Arrange
+		var received events.APIGatewayProxyRequest
+		sut := newRouter(&received)
+
+		// Act
+		trash := serve(sut, http.MethodGet, "/api/v1/rss/trash", "")
+		feedId := serve(sut, http.MethodGet, "/api/v1/rss/www.example.com", "")
+
+		// Assert
+		assert.Equal(t, "trash", handlerOf(t, trash))
+		assert.Equal(t, "feed_id", handlerOf(t, feedId))
+		assert.Equal(t, "www.example.com", received.PathParameters["source"])
+	})
+
+	t.Run("should answer unknown resources and methods like API Gateway", func(t *testing.T) {
+		// Arrange
+		var received events.APIGatewayProxyRequest
+		sut := newRouter(&received)
+
+		// Act
+		notFound := serve(sut, http.MethodGet, "/api/v1/unknown", "")
+		notAllowed := serve(sut, http.MethodPut, "/api/v1/rss/www.example.com", "")
+		preflight := serve(sut, http.MethodOptions, "/api/v1/rss/www.example.com", "")
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, notFound.Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, notAllowed.Code)
+		assert.Equal(t, http.StatusNoContent, preflight.Code)
+		assert.Equal(t, "*", preflight.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
@@ -0,0 +1,245 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/shared"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+type spyMessageClient struct {
+	Messages []string
+	Err      error
+}
+
+func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	r.Messages = append(r.Messages, message)
+	return nil
+}
+
+// newMaxPartMessage returns the Write message of the first part of a feed too large
+// for one message, which is as large as a part gets.
+func newMaxPartMessage(t *testing.T) string {
+	var envelopeJson []byte
+	helper.MustSucceed(t, func() error {
+		feed, err := rss.New("ダミーニュースのフィード", "www.example.com", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 200; i++ {
+			item, err := rss.NewItem(rss.Guid{Value: fmt.Sprintf("http://www.example.com/dummy-guid%d", i)}, fmt.Sprintf("ダミー記事%d", i), fmt.Sprintf("http://www.example.com/dummy-article%d", i), strings.Repeat("ダミー本文", 200), "item@dummy.com", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			feed.AddOrUpdateItem(item)
+		}
+
+		writeMessages, err := message.NewWriteMessages(context.Background(), feed, nil)
+		if err != nil {
+			return err
+		}
+		envelope, err := message.NewEnvelope(context.Background(), message.TypeWrite, writeMessages[0])
+		if err != nil {
+			return err
+		}
+		envelopeJson, err = json.Marshal(envelope)
+		return err
+	})
+	return string(envelopeJson)
+}
+
+func TestHandleFailure(t *testing.T) {
+	t.Run("should return a retryable error without dead-lettering the message", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(&messageClient)
+		cause := fmt.Errorf("save: %w", &rss.ConflictError{Source: "www.example.com", Version: 2})
+
+		// Act
+		err := shared.HandleFailure(ctx, &logger, *deadLetterPublisher, nil, message.StageWrite, "message-1", `{"rss":{}}`, cause)
+
+		// Assert
+		assert.ErrorIs(t, err, cause)
+		assert.Empty(t, messageClient.Messages)
+	})
+
+	t.Run("should dead-letter a permanent failure with the original message and reason", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(&messageClient)
+
+		// Act
+		err := shared.HandleFailure(ctx, &logger, *deadLetterPublisher, nil, message.StageWrite, "message-1", `{"rss":`, errors.New("unexpected end of JSON input"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageClient.Messages, 1)
+
+		var deadLetter message.DeadLetter
+		_, err = message.Decode(messageClient.Messages[0], message.TypeDeadLetter, &deadLetter)
+		assert.NoError(t, err)
+		assert.Equal(t, message.StageWrite, deadLetter.Stage)
+		assert.Equal(t, "message-1", deadLetter.MessageID)
+		assert.Equal(t, `{"rss":`, deadLetter.Message)
+		assert.Equal(t, "unexpected end of JSON input", deadLetter.Reason)
+		assert.False(t, deadLetter.FailedAt.IsZero())
+	})
+
+	t.Run("should dead-letter a message at the maximum part size as a claim check", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(&messageClient)
+		claimCheckStore := claimcheck.NewFileStore(t.TempDir())
+		receiveMessage := newMaxPartMessage(t)
+
+		// Act
+		err := shared.HandleFailure(ctx, &logger, *deadLetterPublisher, claimCheckStore, message.StageWrite, "message-1", receiveMessage, errors.New("invalid item"))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageClient.Messages, 1)
+		assert.LessOrEqual(t, len(messageClient.Messages[0]), message.MaxMessageSize)
+
+		var deadLetter message.DeadLetter
+		_, err = message.Decode(messageClient.Messages[0], message.TypeDeadLetter, &deadLetter)
+		assert.NoError(t, err)
+		assert.Empty(t, deadLetter.Message)
+		assert.NotEmpty(t, deadLetter.ClaimCheck)
+
+		deadLetter, err = message.ResolveDeadLetter(ctx, deadLetter, claimCheckStore)
+		assert.NoError(t, err)
+		assert.Equal(t, receiveMessage, deadLetter.Message)
+	})
+
+	t.Run("should return an error when the dead-letter publish fails", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		publishErr := errors.New("sns unavailable")
+		messageClient := spyMessageClient{Err: publishErr}
+		deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(&messageClient)
+
+		// Act
+		err := shared.HandleFailure(ctx, &logger, *deadLetterPublisher, nil, message.StageWrite, "message-1", `{"rss":`, errors.New("unexpected end of JSON input"))
+
+		// Assert
+		assert.ErrorIs(t, err, publishErr)
+	})
+}
+
+func TestWithTrace(t *testing.T) {
+	t.Run("should pass the correlation ID of the message on with the stage as the origin", func(t *testing.T) {
+		// Arrange
+		ctx := message.ContextWithTrace(context.Background(), message.Trace{CorrelationID: "correlation-1", Origin: message.OriginTrigger})
+		envelope, _ := message.NewEnvelope(ctx, message.TypeSubscribe, message.Subscribe{})
+		envelopeJson, _ := json.Marshal(envelope)
+
+		// Act
+		tracedCtx, _ := shared.WithTrace(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), message.StageSubscribe, string(envelopeJson))
+
+		// Assert
+		assert.Equal(t, message.Trace{CorrelationID: "correlation-1", Origin: message.StageSubscribe}, message.TraceFromContext(tracedCtx))
+	})
+
+	t.Run("should start a new correlation ID for a legacy message", func(t *testing.T) {
+		// Act
+		tracedCtx, _ := shared.WithTrace(context.Background(), slog.New(slog.NewTextHandler(io.Discard, nil)), message.StageDelete, `{"source":"www.example.com"}`)
+
+		// Assert
+		assert.NotEmpty(t, message.TraceFromContext(tracedCtx).CorrelationID)
+	})
+}
+
+func TestDeadLetterFromDestination(t *testing.T) {
+	t.Run("should return the SNS message of an asynchronous invocation whose retries ran out", func(t *testing.T) {
+		// Arrange
+		record := `{
+			"version": "1.0",
+			"timestamp": "2024-07-03T13:00:00.000Z",
+			"requestContext": {
+				"requestId": "request-1",
+				"functionArn": "arn:aws:lambda:ap-northeast-1:123456789012:function:RssWriteFunction:$LATEST",
+				"condition": "RetriesExhausted",
+				"approximateInvokeCount": 3
+			},
+			"requestPayload": {"Records": [{"Sns": {"MessageId": "message-1", "Message": "{\"rss\":{}}"}}]},
+			"responseContext": {"statusCode": 200, "executedVersion": "$LATEST", "functionError": "Unhandled"},
+			"responsePayload": {"errorMessage": "operation error DynamoDB: PutItem, throttled", "errorType": "wrapError"}
+		}`
+
+		// Act
+		deadLetter, ok := shared.DeadLetterFromDestination(record)
+
+		// Assert
+		assert.True(t, ok)
+		assert.Equal(t, message.DeadLetter{
+			Stage:     message.StageWrite,
+			MessageID: "message-1",
+			Message:   `{"rss":{}}`,
+			Reason:    "RetriesExhausted: operation error DynamoDB: PutItem, throttled",
+			FailedAt:  time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC),
+		}, deadLetter)
+	})
+
+	t.Run("should keep the record of a stream batch whose retries ran out as the message", func(t *testing.T) {
+		// Arrange
+		record := `{
+			"version": "1.0",
+			"timestamp": "2024-07-03T13:00:00.000Z",
+			"requestContext": {
+				"requestId": "request-1",
+				"functionArn": "arn:aws:lambda:ap-northeast-1:123456789012:function:RssNotificationFunction",
+				"condition": "RetryAttemptsExhausted",
+				"approximateInvokeCount": 4
+			},
+			"DDBStreamBatchInfo": {"shardId": "shard-1", "startSequenceNumber": "100", "endSequenceNumber": "100", "batchSize": 1}
+		}`
+
+		// Act
+		deadLetter, ok := shared.DeadLetterFromDestination(record)
+
+		// Assert
+		assert.True(t, ok)
+		assert.Equal(t, message.StageNotification, deadLetter.Stage)
+		assert.Equal(t, "request-1", deadLetter.MessageID)
+		assert.Equal(t, record, deadLetter.Message)
+		assert.Equal(t, "RetryAttemptsExhausted", deadLetter.Reason)
+	})
+
+	t.Run("should not take a dead letter published by a stage for a destination record", func(t *testing.T) {
+		// Arrange
+		messageClient := spyMessageClient{}
+		deadLetterPublisher := publisher.NewDeadLetterMessagePublisher(&messageClient)
+		helper.MustSucceed(t, func() error {
+			return deadLetterPublisher.Publish(context.Background(), message.DeadLetter{Stage: message.StageWrite, MessageID: "message-1", Message: "{}", FailedAt: time.Now().UTC()})
+		})
+
+		// Act
+		_, ok := shared.DeadLetterFromDestination(messageClient.Messages[0])
+
+		// Assert
+		assert.False(t, ok)
+	})
+}
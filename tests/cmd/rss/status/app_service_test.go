@@ -0,0 +1,161 @@
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/shared/validation_error"
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/status/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppService_ChangeStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		current  rss.Status
+		action   string
+		expected rss.Status
+	}{
+		{"should pause an active feed", rss.StatusActive, app_service.ActionPause, rss.StatusPaused},
+		{"should resume a paused feed", rss.StatusPaused, app_service.ActionResume, rss.StatusActive},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			testRss := helper.NewRss(t, "connpass.com", "https://connpass.com/explore/ja.atom")
+			testRss.Status = tc.current
+
+			var savedRss rss.Rss
+			repo := helper.SpyRssRepository{
+				FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+					return testRss, nil
+				},
+				SaveFunc: func(ctx context.Context, r rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+					savedRss = r
+					return r, nil
+				},
+			}
+			command := app_service.StatusCommand{Source: "connpass.com", Action: tc.action}
+
+			// Act
+			err := app_service.ChangeStatus(ctx, &logger, &repo, command)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, testRss.ID, savedRss.ID)
+			assert.Equal(t, tc.expected, savedRss.Status)
+		})
+	}
+
+	t.Run("should not save when the feed already has the requested status", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := helper.NewRss(t, "connpass.com", "https://connpass.com/explore/ja.atom")
+		testRss.Pause()
+
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+		}
+		command := app_service.StatusCommand{Source: "connpass.com", Action: app_service.ActionPause}
+
+		// Act
+		err := app_service.ChangeStatus(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return a validation error for an unknown source", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return rss.Rss{}, nil
+			},
+		}
+		command := app_service.StatusCommand{Source: "unknown.com", Action: app_service.ActionPause}
+
+		// Act
+		err := app_service.ChangeStatus(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.IsType(t, &validation_error.ValidationError{}, err)
+		assert.Contains(t, err.(*validation_error.ValidationError).Errors(), "source")
+	})
+
+	t.Run("should return a validation error for an unknown action", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{}
+		command := app_service.StatusCommand{Source: "connpass.com", Action: "stop"}
+
+		// Act
+		err := app_service.ChangeStatus(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.IsType(t, &validation_error.ValidationError{}, err)
+	})
+
+	t.Run("should restore a trashed feed with its status", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := helper.NewRss(t, "connpass.com", "https://connpass.com/explore/ja.atom")
+		testRss.Pause()
+		testRss.Trash(time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC), 30*24*time.Hour)
+
+		var savedRss rss.Rss
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+			SaveFunc: func(ctx context.Context, r rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				savedRss = r
+				return r, nil
+			},
+		}
+		command := app_service.StatusCommand{Source: "connpass.com", Action: app_service.ActionRestore}
+
+		// Act
+		err := app_service.ChangeStatus(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, savedRss.IsTrashed())
+		assert.True(t, savedRss.PurgeAt.IsZero())
+		assert.Equal(t, rss.StatusPaused, savedRss.Status)
+	})
+
+	t.Run("should reject pausing a trashed feed", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		testRss := helper.NewRss(t, "connpass.com", "https://connpass.com/explore/ja.atom")
+		testRss.Trash(time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC), 30*24*time.Hour)
+
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return testRss, nil
+			},
+		}
+		command := app_service.StatusCommand{Source: "connpass.com", Action: app_service.ActionPause}
+
+		// Act
+		err := app_service.ChangeStatus(ctx, &logger, &repo, command)
+
+		// Assert
+		assert.IsType(t, &validation_error.ValidationError{}, err)
+	})
+}
@@ -4,12 +4,13 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
-	"net/url"
 	"testing"
 	"time"
 
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/subscribe/app_service"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/YamazakiNorihito/workday/tests/helper"
 	"github.com/stretchr/testify/assert"
 )
@@ -74,8 +75,8 @@ func TestAppService_Subscribe(t *testing.T) {
 
 		assert.NotEmpty(t, act_rss.ID)
 		assert.Equal(t, "ダミーニュースのフィード", act_rss.Title)
-		port := getPort(server.URL)
-		assert.Equal(t, "127.0.0.1:"+port, act_rss.Source)
+		expectedSource, _ := rss.NewSource(server.URL)
+		assert.Equal(t, expectedSource, act_rss.Source)
 		assert.Equal(t, server.URL, act_rss.Link)
 		assert.Equal(t, "このフィードはダミーニュースを提供します。", act_rss.Description)
 		assert.Equal(t, "ja", act_rss.Language)
@@ -119,7 +120,443 @@ func TestAppService_Subscribe(t *testing.T) {
 	})
 }
 
-func getPort(rawURL string) (port string) {
-	u, _ := url.Parse(rawURL)
-	return u.Port()
+func TestAppService_Subscribe_Tags(t *testing.T) {
+	t.Run("should tag items with their categories before filtering them", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mockFeed := `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <description>このフィードはダミーニュースを提供します。</description>
+  <item>
+    <title>ダミー記事1</title>
+    <guid>http://www.example.com/dummy-guid1</guid>
+    <link>http://www.example.com/dummy-article1</link>
+    <description>これはダミー記事1の概要です。</description>
+    <pubDate>Mon, 03 Jul 2024 12:00:00 GMT</pubDate>
+    <category>golang</category>
+    <category> cloud </category>
+    <category>golang</category>
+  </item>
+  <item>
+    <title>ダミー記事2</title>
+    <guid>http://www.example.com/dummy-guid2</guid>
+    <link>http://www.example.com/dummy-article2</link>
+    <description>これはダミー記事2の概要です。</description>
+    <pubDate>Mon, 03 Jul 2024 12:30:00 GMT</pubDate>
+    <category>php</category>
+  </item>
+</channel>
+</rss>`
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(mockFeed))
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		itemFilter, err := rss.NewItemFilterWithExpression(nil, nil, "NOT tag=php")
+		assert.NoError(t, err)
+		repo := app_service.NewFeedRepository(server.Client(), server.URL+"/feed", "ja", itemFilter)
+
+		// Act
+		act_rss, err := app_service.Subscribe(ctx, &logger, &repo)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, act_rss.Items, 1)
+		item1 := act_rss.Items[rss.Guid{Value: "http://www.example.com/dummy-guid1"}]
+		assert.Equal(t, []string{"golang", "cloud"}, item1.Tags)
+	})
+}
+
+func TestAppService_Subscribe_Source(t *testing.T) {
+	t.Run("should keep the source pinned by the message", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mockFeed := `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <description>このフィードはダミーニュースを提供します。</description>
+  <item>
+    <title>ダミー記事1</title>
+    <guid>http://www.example.com/dummy-guid1</guid>
+    <link>http://www.example.com/dummy-article1</link>
+    <description>これはダミー記事1の概要です。</description>
+    <pubDate>Mon, 03 Jul 2024 12:00:00 GMT</pubDate>
+  </item>
+</channel>
+</rss>`
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(mockFeed))
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+
+		repo := app_service.NewFeedRepository(server.Client(), server.URL+"/feed", "ja", rss.NewItemFilter(nil, nil))
+		repo.SetSource("registered-source")
+
+		// Act
+		act_rss, err := app_service.Subscribe(ctx, &logger, &repo)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "registered-source", act_rss.Source)
+		assert.Equal(t, server.URL+"/feed", act_rss.Link)
+	})
+
+	t.Run("should derive different sources for feeds on the same host", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <item>
+    <title>ダミー記事1</title>
+    <guid>http://www.example.com/dummy-guid1</guid>
+    <link>http://www.example.com/dummy-article1</link>
+    <pubDate>Mon, 03 Jul 2024 12:00:00 GMT</pubDate>
+  </item>
+</channel>
+</rss>`))
+		}))
+		defer server.Close()
+
+		feedRepo := app_service.NewFeedRepository(server.Client(), server.URL+"/feed", "ja", rss.NewItemFilter(nil, nil))
+		tagRepo := app_service.NewFeedRepository(server.Client(), server.URL+"/feed/tag/go", "ja", rss.NewItemFilter(nil, nil))
+
+		// Act
+		feedRss, feedErr := app_service.Subscribe(ctx, &logger, &feedRepo)
+		tagRss, tagErr := app_service.Subscribe(ctx, &logger, &tagRepo)
+
+		// Assert
+		assert.NoError(t, feedErr)
+		assert.NoError(t, tagErr)
+		assert.NotEqual(t, feedRss.Source, tagRss.Source)
+	})
+}
+
+type spyMessageClient struct{ Messages []string }
+
+func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
+	r.Messages = append(r.Messages, message)
+	return nil
+}
+
+func TestAppService_Subscribe_ConditionalGet(t *testing.T) {
+	mockFeed := `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <item>
+    <title>ダミー記事1</title>
+    <guid>http://www.example.com/dummy-guid1</guid>
+    <link>http://www.example.com/dummy-article1</link>
+    <pubDate>Mon, 03 Jul 2024 12:00:00 GMT</pubDate>
+  </item>
+</channel>
+</rss>`
+	etag := `"v1"`
+	lastModified := "Wed, 03 Jul 2024 12:00:00 GMT"
+
+	newServer := func(t *testing.T) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", lastModified)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(mockFeed))
+		}))
+	}
+
+	t.Run("should store the cache validators of the response", func(t *testing.T) {
+		// Arrange
+		server := newServer(t)
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+
+		// Act
+		act_rss, err := app_service.Subscribe(ctx, &logger, &repo)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, etag, act_rss.ETag)
+		assert.Equal(t, lastModified, act_rss.LastModified)
+	})
+
+	t.Run("should not publish when the feed is not modified", func(t *testing.T) {
+		// Arrange
+		server := newServer(t)
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+		repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+		repo.SetCacheValidators(etag, lastModified)
+
+		// Act
+		err := app_service.Execute(ctx, &logger, &repo, *writerPublisher, newUnregisteredFetchStatusRecorder())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, messageClient.Messages)
+	})
+
+	t.Run("should publish when the cache validators are outdated", func(t *testing.T) {
+		// Arrange
+		server := newServer(t)
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+		repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+		repo.SetCacheValidators(`"v0"`, lastModified)
+
+		// Act
+		err := app_service.Execute(ctx, &logger, &repo, *writerPublisher, newUnregisteredFetchStatusRecorder())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageClient.Messages, 1)
+	})
+}
+
+type spySlackSender struct{ Messages []string }
+
+func (s *spySlackSender) PostMessageContext(ctx context.Context, text string, username string) (string, string, error) {
+	s.Messages = append(s.Messages, text)
+	return "channel", "timestamp", nil
+}
+
+func newUnregisteredFetchStatusRecorder() *app_service.FetchStatusRecorder {
+	rssRepository := helper.SpyRssRepository{
+		FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+			return rss.Rss{}, nil
+		},
+	}
+	return app_service.NewFetchStatusRecorder(&rssRepository, &spySlackSender{}, 3)
+}
+
+func TestAppService_FetchStatusRecorder(t *testing.T) {
+	newStoredRss := func(t *testing.T, status rss.FetchStatus) rss.Rss {
+		var storedRss rss.Rss
+		helper.MustSucceed(t, func() error {
+			var err error
+			storedRss, err = rss.New("ダミーニュースのフィード", "registered-source", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+			return err
+		})
+		storedRss.FetchStatus = status
+		return storedRss
+	}
+
+	t.Run("should record failures of the fetch and disable the feed once", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+		slackSender := spySlackSender{}
+
+		storedRss := newStoredRss(t, rss.FetchStatus{})
+		rssRepository := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return storedRss, nil
+			},
+			SaveFetchStatusFunc: func(ctx context.Context, r rss.Rss, status rss.FetchStatus) error {
+				storedRss.FetchStatus = status
+				return nil
+			},
+		}
+		recorder := app_service.NewFetchStatusRecorder(&rssRepository, &slackSender, 3)
+
+		// Act
+		var errs []error
+		for i := 0; i < 4; i++ {
+			repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+			repo.SetSource("registered-source")
+			errs = append(errs, app_service.Execute(ctx, &logger, &repo, *writerPublisher, recorder))
+		}
+
+		// Assert
+		for _, err := range errs {
+			assert.Error(t, err)
+			assert.True(t, infrastructure.IsRetryable(err))
+		}
+		assert.Empty(t, messageClient.Messages)
+
+		assert.Equal(t, 4, storedRss.FetchStatus.ConsecutiveFailures)
+		assert.Equal(t, http.StatusInternalServerError, storedRss.FetchStatus.HTTPStatus)
+		assert.NotEmpty(t, storedRss.FetchStatus.LastError)
+		assert.True(t, storedRss.FetchStatus.IsDisabled())
+
+		assert.Len(t, slackSender.Messages, 1)
+		assert.Contains(t, slackSender.Messages[0], "http://www.example.com/feed")
+	})
+
+	t.Run("should reset failures and enable the feed on success", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <item>
+    <title>ダミー記事1</title>
+    <guid>http://www.example.com/dummy-guid1</guid>
+    <link>http://www.example.com/dummy-article1</link>
+    <pubDate>Mon, 03 Jul 2024 12:00:00 GMT</pubDate>
+  </item>
+</channel>
+</rss>`))
+		}))
+		defer server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+		slackSender := spySlackSender{}
+
+		storedRss := newStoredRss(t, rss.FetchStatus{
+			ConsecutiveFailures: 3,
+			HTTPStatus:          http.StatusInternalServerError,
+			DisabledAt:          time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC),
+		})
+		rssRepository := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return storedRss, nil
+			},
+			SaveFetchStatusFunc: func(ctx context.Context, r rss.Rss, status rss.FetchStatus) error {
+				storedRss.FetchStatus = status
+				return nil
+			},
+		}
+		recorder := app_service.NewFetchStatusRecorder(&rssRepository, &slackSender, 3)
+		repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+		repo.SetSource("registered-source")
+
+		// Act
+		err := app_service.Execute(ctx, &logger, &repo, *writerPublisher, recorder)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageClient.Messages, 1)
+		assert.Equal(t, 0, storedRss.FetchStatus.ConsecutiveFailures)
+		assert.Equal(t, http.StatusOK, storedRss.FetchStatus.HTTPStatus)
+		assert.False(t, storedRss.FetchStatus.IsDisabled())
+		assert.False(t, storedRss.FetchStatus.LastSuccessAt.IsZero())
+		assert.Empty(t, slackSender.Messages)
+	})
+}
+
+func TestAppService_Execute_FetchError(t *testing.T) {
+	testCases := []struct {
+		name      string
+		status    int
+		body      string
+		retryable bool
+	}{
+		{"should retry a 5xx", http.StatusServiceUnavailable, "", true},
+		{"should retry a 429", http.StatusTooManyRequests, "", true},
+		{"should not retry a 404", http.StatusNotFound, "", false},
+		{"should not retry a feed that cannot be parsed", http.StatusOK, "<html></html>", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			messageClient := spyMessageClient{}
+			writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+			repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+
+			// Act
+			err := app_service.Execute(ctx, &logger, &repo, *writerPublisher, newUnregisteredFetchStatusRecorder())
+
+			// Assert
+			assert.Error(t, err)
+			assert.Equal(t, tc.retryable, infrastructure.IsRetryable(err))
+			assert.Empty(t, messageClient.Messages)
+		})
+	}
+
+	t.Run("should retry a connection that could not be made", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+		repo := app_service.NewFeedRepository(server.Client(), server.URL, "ja", rss.NewItemFilter(nil, nil))
+
+		// Act
+		err := app_service.Execute(ctx, &logger, &repo, *writerPublisher, newUnregisteredFetchStatusRecorder())
+
+		// Assert
+		assert.Error(t, err)
+		assert.True(t, infrastructure.IsRetryable(err))
+	})
+
+	t.Run("should retry an origin that does not answer within the timeout", func(t *testing.T) {
+		// Arrange
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		writerPublisher := publisher.NewWriterMessagePublisher(&messageClient)
+		httpClient := server.Client()
+		httpClient.Timeout = 50 * time.Millisecond
+		repo := app_service.NewFeedRepository(httpClient, server.URL, "ja", rss.NewItemFilter(nil, nil))
+
+		// Act
+		err := app_service.Execute(ctx, &logger, &repo, *writerPublisher, newUnregisteredFetchStatusRecorder())
+
+		// Assert
+		assert.Error(t, err)
+		assert.True(t, infrastructure.IsRetryable(err))
+	})
 }
@@ -0,0 +1,76 @@
+package trash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/trash/app_service"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppService_TrashedRssFeeds(t *testing.T) {
+	t.Run("should return only trashed feeds ordered by purge time", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		retention := 30 * 24 * time.Hour
+
+		activeRss := helper.NewRss(t, "127.0.0.1:8080", "http://127.0.0.1:8080")
+		laterRss := helper.NewRss(t, "127.0.0.1:8081", "http://127.0.0.1:8081")
+		laterRss.Trash(time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC), retention)
+		soonerRss := helper.NewRss(t, "127.0.0.1:8082", "http://127.0.0.1:8082")
+		soonerRss.Trash(time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC), retention)
+
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{activeRss, laterRss, soonerRss}, nil
+			},
+		}
+
+		// Act
+		response, err := app_service.TrashedRssFeeds(ctx, &logger, &repo)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []app_service.TrashedRssResponse{
+			{
+				ID:        soonerRss.ID,
+				Source:    "127.0.0.1:8082",
+				Title:     "ダミーニュースのフィード",
+				Link:      "http://127.0.0.1:8082",
+				TrashedAt: time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC),
+				PurgeAt:   time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:        laterRss.ID,
+				Source:    "127.0.0.1:8081",
+				Title:     "ダミーニュースのフィード",
+				Link:      "http://127.0.0.1:8081",
+				TrashedAt: time.Date(2024, time.July, 5, 0, 0, 0, 0, time.UTC),
+				PurgeAt:   time.Date(2024, time.August, 4, 0, 0, 0, 0, time.UTC),
+			},
+		}, response)
+	})
+
+	t.Run("should return an empty list when nothing is in the trash", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				return []rss.Rss{helper.NewRss(t, "127.0.0.1:8080", "http://127.0.0.1:8080")}, nil
+			},
+		}
+
+		// Act
+		response, err := app_service.TrashedRssFeeds(ctx, &logger, &repo)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, response)
+		assert.NotNil(t, response)
+	})
+}
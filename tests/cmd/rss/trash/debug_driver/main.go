@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/api/trash/handler"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func main() {
+	event := events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/feeds/trash",
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+
+	response, err := handler.Handler(context.Background(), event)
+
+	// 結果をコンソールに表示
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Response: %+v\n", response)
+	}
+}
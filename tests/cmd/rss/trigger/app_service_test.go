@@ -17,7 +17,7 @@ import (
 type spyMessageClient struct{ Messages []string }
 
 func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
-	r.Messages = append(r.Messages, message)
+	r.Messages = append(r.Messages, helper.PayloadOf(message))
 	return nil
 }
 
@@ -150,18 +150,18 @@ func TestAppService_Execute(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Len(t, messageClient.Messages, 12)
 		assert.ElementsMatch(t, messageClient.Messages, []string{
-			"{\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed/\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]}}",
-			"{\"feed_url\":\"https://aws.amazon.com/jp/blogs/news/feed/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"AWS\",\"Lambda\",\"S3\"],\"exclude_keywords\":[]}}",
-			"{\"feed_url\":\"https://developers-jp.googleblog.com/atom.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Azure\",\"AWS\"]}}",
-			"{\"feed_url\":\"https://techblog.nhn-techorus.com/feed\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]}}",
-			"{\"feed_url\":\"https://buildersbox.corp-sansan.com/rss\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Sansan\",\"Cloud\",\"API\"],\"exclude_keywords\":[\"AWS\",\"Google\"]}}",
-			"{\"feed_url\":\"https://knowledge.sakura.ad.jp/rss/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Microsoft\",\"Amazon\"]}}",
-			"{\"feed_url\":\"https://www.oreilly.co.jp/catalog/soon.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"O'Reilly\",\"Books\",\"Technology\"],\"exclude_keywords\":[]}}",
-			"{\"feed_url\":\"https://go.dev/blog/feed.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]}}",
-			"{\"feed_url\":\"https://connpass.com/explore/ja.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Connpass\",\"Events\",\"Tech\"],\"exclude_keywords\":[\"Non-Tech\",\"Marketing\"]}}",
-			"{\"feed_url\":\"https://www.ipa.go.jp/security/alert-rss.rdf\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Security\",\"IPA\",\"Alerts\"],\"exclude_keywords\":[\"Old Versions\"]}}",
-			"{\"feed_url\":\"https://feed.infoq.com\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"InfoQ\",\"Technology\",\"Software\"],\"exclude_keywords\":[]}}",
-			"{\"feed_url\":\"https://techcrunch.com/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Non-Tech\",\"Startups\"]}}",
+			"{\"source\":\"127.0.0.1:8081\",\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed/\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8082\",\"feed_url\":\"https://aws.amazon.com/jp/blogs/news/feed/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"AWS\",\"Lambda\",\"S3\"],\"exclude_keywords\":[]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8083\",\"feed_url\":\"https://developers-jp.googleblog.com/atom.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Azure\",\"AWS\"]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8084\",\"feed_url\":\"https://techblog.nhn-techorus.com/feed\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8085\",\"feed_url\":\"https://buildersbox.corp-sansan.com/rss\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Sansan\",\"Cloud\",\"API\"],\"exclude_keywords\":[\"AWS\",\"Google\"]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8086\",\"feed_url\":\"https://knowledge.sakura.ad.jp/rss/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Microsoft\",\"Amazon\"]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8087\",\"feed_url\":\"https://www.oreilly.co.jp/catalog/soon.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"O'Reilly\",\"Books\",\"Technology\"],\"exclude_keywords\":[]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8088\",\"feed_url\":\"https://go.dev/blog/feed.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:8089\",\"feed_url\":\"https://connpass.com/explore/ja.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Connpass\",\"Events\",\"Tech\"],\"exclude_keywords\":[\"Non-Tech\",\"Marketing\"]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:80810\",\"feed_url\":\"https://www.ipa.go.jp/security/alert-rss.rdf\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Security\",\"IPA\",\"Alerts\"],\"exclude_keywords\":[\"Old Versions\"]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:80811\",\"feed_url\":\"https://feed.infoq.com\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"InfoQ\",\"Technology\",\"Software\"],\"exclude_keywords\":[]},\"retention\":{}}",
+			"{\"source\":\"127.0.0.1:80812\",\"feed_url\":\"https://techcrunch.com/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Non-Tech\",\"Startups\"]},\"retention\":{}}",
 		})
 	})
 
@@ -306,20 +306,102 @@ func TestAppService_Execute(t *testing.T) {
 				// the specified batch size.
 				assert.Equal(t, tc.expectedSleepCount, actSleepCount)
 				assert.ElementsMatch(t, messageClient.Messages, []string{
-					"{\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed/\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]}}",
-					"{\"feed_url\":\"https://aws.amazon.com/jp/blogs/news/feed/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"AWS\",\"Lambda\",\"S3\"],\"exclude_keywords\":[]}}",
-					"{\"feed_url\":\"https://developers-jp.googleblog.com/atom.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Azure\",\"AWS\"]}}",
-					"{\"feed_url\":\"https://techblog.nhn-techorus.com/feed\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]}}",
-					"{\"feed_url\":\"https://buildersbox.corp-sansan.com/rss\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Sansan\",\"Cloud\",\"API\"],\"exclude_keywords\":[\"AWS\",\"Google\"]}}",
-					"{\"feed_url\":\"https://knowledge.sakura.ad.jp/rss/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Microsoft\",\"Amazon\"]}}",
-					"{\"feed_url\":\"https://www.oreilly.co.jp/catalog/soon.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"O'Reilly\",\"Books\",\"Technology\"],\"exclude_keywords\":[]}}",
-					"{\"feed_url\":\"https://go.dev/blog/feed.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]}}",
-					"{\"feed_url\":\"https://connpass.com/explore/ja.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Connpass\",\"Events\",\"Tech\"],\"exclude_keywords\":[\"Non-Tech\",\"Marketing\"]}}",
-					"{\"feed_url\":\"https://www.ipa.go.jp/security/alert-rss.rdf\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Security\",\"IPA\",\"Alerts\"],\"exclude_keywords\":[\"Old Versions\"]}}",
-					"{\"feed_url\":\"https://feed.infoq.com\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"InfoQ\",\"Technology\",\"Software\"],\"exclude_keywords\":[]}}",
-					"{\"feed_url\":\"https://techcrunch.com/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Non-Tech\",\"Startups\"]}}",
+					"{\"source\":\"127.0.0.1:8081\",\"feed_url\":\"https://azure.microsoft.com/ja-jp/blog/feed/\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"Azure\",\"Cloud\",\"Microsoft\"],\"exclude_keywords\":[\"AWS\",\"Google Cloud\"]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8082\",\"feed_url\":\"https://aws.amazon.com/jp/blogs/news/feed/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"AWS\",\"Lambda\",\"S3\"],\"exclude_keywords\":[]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8083\",\"feed_url\":\"https://developers-jp.googleblog.com/atom.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Azure\",\"AWS\"]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8084\",\"feed_url\":\"https://techblog.nhn-techorus.com/feed\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8085\",\"feed_url\":\"https://buildersbox.corp-sansan.com/rss\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Sansan\",\"Cloud\",\"API\"],\"exclude_keywords\":[\"AWS\",\"Google\"]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8086\",\"feed_url\":\"https://knowledge.sakura.ad.jp/rss/\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Microsoft\",\"Amazon\"]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8087\",\"feed_url\":\"https://www.oreilly.co.jp/catalog/soon.xml\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"O'Reilly\",\"Books\",\"Technology\"],\"exclude_keywords\":[]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8088\",\"feed_url\":\"https://go.dev/blog/feed.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:8089\",\"feed_url\":\"https://connpass.com/explore/ja.atom\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Connpass\",\"Events\",\"Tech\"],\"exclude_keywords\":[\"Non-Tech\",\"Marketing\"]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:80810\",\"feed_url\":\"https://www.ipa.go.jp/security/alert-rss.rdf\",\"language\":\"ja\",\"item_filter\":{\"include_keywords\":[\"Security\",\"IPA\",\"Alerts\"],\"exclude_keywords\":[\"Old Versions\"]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:80811\",\"feed_url\":\"https://feed.infoq.com\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[\"InfoQ\",\"Technology\",\"Software\"],\"exclude_keywords\":[]},\"retention\":{}}",
+					"{\"source\":\"127.0.0.1:80812\",\"feed_url\":\"https://techcrunch.com/feed\",\"language\":\"en\",\"item_filter\":{\"include_keywords\":[],\"exclude_keywords\":[\"Non-Tech\",\"Startups\"]},\"retention\":{}}",
 				})
 			})
 		}
 	})
 }
+
+func TestAppService_Trigger_DisabledFeed(t *testing.T) {
+	t.Run("should skip feeds disabled by consecutive fetch failures", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+		throttle := throttle.Config{
+			BatchSize: 10,
+			Sleep:     func() {},
+		}
+
+		rssRepository := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				activeRss, err := rss.New("ダミーニュースのフィード1", "127.0.0.1:8081", "https://go.dev/blog/feed.atom", "", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+				if err != nil {
+					return nil, err
+				}
+				activeRss.FetchStatus = rss.FetchStatus{ConsecutiveFailures: 2}
+
+				disabledRss, err := rss.New("ダミーニュースのフィード2", "127.0.0.1:8082", "https://feed.infoq.com", "", "en", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+				if err != nil {
+					return nil, err
+				}
+				disabledRss.FetchStatus = rss.FetchStatus{
+					ConsecutiveFailures: 5,
+					DisabledAt:          time.Date(2024, time.July, 3, 14, 0, 0, 0, time.UTC),
+				}
+
+				return []rss.Rss{activeRss, disabledRss}, nil
+			},
+		}
+
+		// Act
+		err := app_service.Trigger(ctx, &logger, *subscribeMessagePublisher, throttle, &rssRepository)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageClient.Messages, 1)
+		assert.Contains(t, messageClient.Messages[0], "https://go.dev/blog/feed.atom")
+	})
+}
+
+func TestAppService_Trigger_PausedFeed(t *testing.T) {
+	t.Run("should skip paused feeds", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		messageClient := spyMessageClient{}
+		subscribeMessagePublisher := publisher.NewSubscribeMessagePublisher(&messageClient)
+		throttle := throttle.Config{
+			BatchSize: 10,
+			Sleep:     func() {},
+		}
+
+		rssRepository := helper.SpyRssRepository{
+			FindAllFunc: func(ctx context.Context) ([]rss.Rss, error) {
+				activeRss, err := rss.New("ダミーニュースのフィード1", "127.0.0.1:8081", "https://go.dev/blog/feed.atom", "", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+				if err != nil {
+					return nil, err
+				}
+
+				pausedRss, err := rss.New("ダミーニュースのフィード2", "127.0.0.1:8082", "https://feed.infoq.com", "", "en", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+				if err != nil {
+					return nil, err
+				}
+				pausedRss.Pause()
+
+				return []rss.Rss{activeRss, pausedRss}, nil
+			},
+		}
+
+		// Act
+		err := app_service.Trigger(ctx, &logger, *subscribeMessagePublisher, throttle, &rssRepository)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, messageClient.Messages, 1)
+		assert.Contains(t, messageClient.Messages[0], "https://go.dev/blog/feed.atom")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/YamazakiNorihito/workday/cmd/rss/lambda/event/write/app_service"
 	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 	"github.com/YamazakiNorihito/workday/tests/helper"
 	"github.com/stretchr/testify/assert"
 )
@@ -78,6 +79,332 @@ func TestAppService_Clean(t *testing.T) {
 	})
 }
 
+func TestAppService_Write_CacheValidators(t *testing.T) {
+	testCases := []struct {
+		name         string
+		etag         string
+		lastModified string
+		expectSave   bool
+	}{
+		{"should save when ETag changed", `"v2"`, "Wed, 03 Jul 2024 13:00:00 GMT", true},
+		{"should save when Last-Modified changed", `"v1"`, "Wed, 03 Jul 2024 14:00:00 GMT", true},
+		{"should not save when nothing changed", `"v1"`, "Wed, 03 Jul 2024 13:00:00 GMT", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			existingRss := generatorTestRss(t)
+			existingRss.SetCacheValidators(`"v1"`, "Wed, 03 Jul 2024 13:00:00 GMT")
+
+			// The clean stage forwards no items when all of them are already stored.
+			test_rss := existingRss
+			test_rss.Items = map[rss.Guid]rss.Item{}
+			test_rss.SetCacheValidators(tc.etag, tc.lastModified)
+
+			ctx := context.Background()
+			logger := helper.MockLogger{}
+			saved := false
+			repo := helper.SpyRssRepository{
+				FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+					return existingRss, nil
+				},
+				SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+					saved = true
+					return entryRss, nil
+				},
+			}
+
+			// Act
+			act_rss, err := app_service.Write(ctx, &logger, &repo, test_rss)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectSave, saved)
+			if tc.expectSave {
+				assert.Equal(t, tc.etag, act_rss.ETag)
+				assert.Equal(t, tc.lastModified, act_rss.LastModified)
+			}
+		})
+	}
+}
+
+func TestAppService_Write_Status(t *testing.T) {
+	t.Run("should keep the stored status when the feed was paused during processing", func(t *testing.T) {
+		// Arrange
+		existingRss := generatorTestRss(t)
+		existingRss.Pause()
+
+		test_rss := generatorTestRss(t)
+		test_rss.ID = existingRss.ID
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		var savedRss rss.Rss
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return existingRss, nil
+			},
+			SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				savedRss = entryRss
+				return entryRss, nil
+			},
+		}
+
+		// Act
+		_, err := app_service.Write(ctx, &logger, &repo, test_rss)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rss.StatusPaused, savedRss.Status)
+	})
+}
+
+func TestAppService_Write_Settings(t *testing.T) {
+	t.Run("should keep the stored settings when they were patched during processing", func(t *testing.T) {
+		// Arrange
+		existingRss := generatorTestRss(t)
+		existingRss.SetLanguage("en")
+		existingRss.SetItemFilter([]string{"Go"}, nil)
+		existingRss.SetRetention(rss.NewRetention(30, 100))
+
+		test_rss := generatorTestRss(t)
+		test_rss.ID = existingRss.ID
+		test_rss.LastBuildDate = existingRss.LastBuildDate.Add(time.Hour)
+		test_rss.SetCacheValidators(`"v2"`, "Wed, 03 Jul 2024 14:00:00 GMT")
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		var savedRss rss.Rss
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return existingRss, nil
+			},
+			SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				savedRss = entryRss
+				return entryRss, nil
+			},
+		}
+
+		// Act
+		_, err := app_service.Write(ctx, &logger, &repo, test_rss)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "en", savedRss.Language)
+		assert.Equal(t, []string{"Go"}, savedRss.ItemFilter.IncludeKeywords)
+		assert.Equal(t, rss.NewRetention(30, 100), savedRss.Retention)
+		assert.Equal(t, test_rss.LastBuildDate, savedRss.LastBuildDate)
+		assert.Equal(t, `"v2"`, savedRss.ETag)
+		assert.Equal(t, test_rss.Items, savedRss.Items)
+	})
+}
+
+func TestAppService_Write_Conflict(t *testing.T) {
+	t.Run("should re-read and merge the stored feed when it was saved concurrently", func(t *testing.T) {
+		// Arrange
+		existingRss := generatorTestRss(t)
+		existingRss.Version = 1
+
+		pausedRss := generatorTestRss(t)
+		pausedRss.ID = existingRss.ID
+		pausedRss.Pause()
+		pausedRss.Version = 2
+
+		test_rss := generatorTestRss(t)
+		test_rss.ID = existingRss.ID
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		stored := []rss.Rss{existingRss, pausedRss}
+		var savedVersions []int
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				current := stored[0]
+				stored = stored[1:]
+				return current, nil
+			},
+			SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				savedVersions = append(savedVersions, entryRss.Version)
+				if entryRss.Version != pausedRss.Version {
+					return entryRss, &rss.ConflictError{Source: entryRss.Source, Version: entryRss.Version}
+				}
+				entryRss.Version++
+				return entryRss, nil
+			},
+		}
+
+		// Act
+		act_rss, err := app_service.Write(ctx, &logger, &repo, test_rss)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, savedVersions)
+		assert.Equal(t, 3, act_rss.Version)
+		assert.Equal(t, rss.StatusPaused, act_rss.Status)
+	})
+
+	t.Run("should give up after repeated conflicts", func(t *testing.T) {
+		// Arrange
+		existingRss := generatorTestRss(t)
+		test_rss := generatorTestRss(t)
+		test_rss.ID = existingRss.ID
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		saveCount := 0
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return existingRss, nil
+			},
+			SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				saveCount++
+				return entryRss, &rss.ConflictError{Source: entryRss.Source, Version: entryRss.Version}
+			},
+		}
+
+		// Act
+		_, err := app_service.Write(ctx, &logger, &repo, test_rss)
+
+		// Assert
+		var conflict *rss.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Equal(t, 3, saveCount)
+	})
+
+	t.Run("should keep retrying a part while the other parts of its fetch win the races", func(t *testing.T) {
+		// Arrange
+		existingRss := generatorTestRss(t)
+		test_rss := generatorTestRss(t)
+		test_rss.ID = existingRss.ID
+		test_rss.Parts = rss.NewFetchParts("fetch-1", 6, 6)
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		saveCount := 0
+		repo := helper.SpyRssRepository{
+			FindBySourceFunc: func(ctx context.Context, source string) (rss.Rss, error) {
+				return existingRss, nil
+			},
+			SaveFunc: func(ctx context.Context, entryRss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
+				saveCount++
+				if saveCount < 6 {
+					return entryRss, &rss.ConflictError{Source: entryRss.Source, Version: entryRss.Version}
+				}
+				entryRss.Version++
+				return entryRss, nil
+			},
+		}
+
+		// Act
+		_, err := app_service.Write(ctx, &logger, &repo, test_rss)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 6, saveCount)
+	})
+}
+
+func TestAppService_Write_Parts(t *testing.T) {
+	t.Run("should keep the items of every part whatever order the parts arrive in", func(t *testing.T) {
+		// Arrange
+		test_rss := generatorTestRss(t)
+		var parts []rss.Rss
+		helper.MustSucceed(t, func() error {
+			var err error
+			parts, err = message.SplitRss(test_rss, 1)
+			return err
+		})
+		assert.Len(t, parts, 2)
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := rss.NewInMemoryRssRepository()
+
+		// Act
+		_, err1 := app_service.Write(ctx, &logger, repo, parts[1])
+		_, err2 := app_service.Write(ctx, &logger, repo, parts[0])
+		_, err3 := app_service.Write(ctx, &logger, repo, parts[1])
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NoError(t, err3)
+
+		storedRss, err := repo.FindBySource(ctx, test_rss.Source)
+		assert.NoError(t, err)
+		assert.Equal(t, test_rss.ID, storedRss.ID)
+		storedRss, err = repo.FindItems(ctx, storedRss)
+		assert.NoError(t, err)
+		assert.Len(t, storedRss.Items, 2)
+	})
+
+	t.Run("should save the cache validators and the completing version once every part is written", func(t *testing.T) {
+		// Arrange
+		test_rss := generatorTestRss(t)
+		test_rss.SetCacheValidators(`"v1"`, "Wed, 03 Jul 2024 13:00:00 GMT")
+		var parts []rss.Rss
+		helper.MustSucceed(t, func() error {
+			var err error
+			parts, err = message.SplitRss(test_rss, 1)
+			return err
+		})
+
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := rss.NewInMemoryRssRepository()
+
+		// Act
+		firstRss, err1 := app_service.Write(ctx, &logger, repo, parts[0])
+		lastRss, err2 := app_service.Write(ctx, &logger, repo, parts[1])
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.Empty(t, firstRss.ETag)
+		assert.Empty(t, firstRss.LastModified)
+		assert.False(t, firstRss.Parts.IsComplete())
+
+		assert.NoError(t, err2)
+		assert.Equal(t, `"v1"`, lastRss.ETag)
+		assert.Equal(t, "Wed, 03 Jul 2024 13:00:00 GMT", lastRss.LastModified)
+		assert.Equal(t, []int{1, 2}, lastRss.Parts.Written)
+		assert.True(t, lastRss.Parts.NewFeed)
+		assert.Equal(t, lastRss.Version, lastRss.Parts.CompletedVersion)
+	})
+
+	t.Run("should not mark the parts of a registered feed as a new feed", func(t *testing.T) {
+		// Arrange
+		test_rss := generatorTestRss(t)
+		ctx := context.Background()
+		logger := helper.MockLogger{}
+		repo := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			header := test_rss
+			header.Items = map[rss.Guid]rss.Item{}
+			_, err := repo.Save(ctx, header, metadata.UserMeta{ID: test_rss.Source, Name: test_rss.Source})
+			return err
+		})
+
+		var parts []rss.Rss
+		helper.MustSucceed(t, func() error {
+			var err error
+			parts, err = message.SplitRss(test_rss, 1)
+			return err
+		})
+
+		// Act
+		_, err1 := app_service.Write(ctx, &logger, repo, parts[0])
+		lastRss, err2 := app_service.Write(ctx, &logger, repo, parts[1])
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.True(t, lastRss.Parts.IsComplete())
+		assert.False(t, lastRss.Parts.NewFeed)
+		assert.Equal(t, lastRss.Version, lastRss.Parts.CompletedVersion)
+	})
+}
+
 func generatorTestRss(t *testing.T) rss.Rss {
 	var dummy_rss rss.Rss
 	helper.MustSucceed(t, func() error {
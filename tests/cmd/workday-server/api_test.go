@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_APIHandler(t *testing.T) {
+	t.Run("should serve the stored feeds and move a deleted one to the trash", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		sut := newServer(rssRepository, &spySlackSender{})
+		api := httptest.NewServer(sut.APIHandler())
+		defer api.Close()
+
+		// Act
+		feedsResponse, err := http.Get(api.URL + "/api/v1/rss")
+		assert.NoError(t, err)
+		defer feedsResponse.Body.Close()
+
+		deleteRequest, _ := http.NewRequest(http.MethodDelete, api.URL+"/api/v1/rss/www.example.com", nil)
+		deleteResponse, err := http.DefaultClient.Do(deleteRequest)
+		assert.NoError(t, err)
+		defer deleteResponse.Body.Close()
+		sut.Wait()
+
+		// Assert
+		assert.Equal(t, http.StatusOK, feedsResponse.StatusCode)
+		var feeds []map[string]any
+		assert.NoError(t, json.NewDecoder(feedsResponse.Body).Decode(&feeds))
+		assert.Len(t, feeds, 1)
+
+		assert.Equal(t, http.StatusNoContent, deleteResponse.StatusCode)
+		storedRss, err := rssRepository.FindBySource(ctx, "www.example.com")
+		assert.NoError(t, err)
+		assert.True(t, storedRss.IsTrashed())
+	})
+
+	t.Run("should answer a validation error with 400", func(t *testing.T) {
+		// Arrange
+		sut := newServer(rss.NewInMemoryRssRepository(), &spySlackSender{})
+		api := httptest.NewServer(sut.APIHandler())
+		defer api.Close()
+
+		// Act
+		response, err := http.Post(api.URL+"/api/v1/rss/www.example.com", "application/json", nil)
+
+		// Assert
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("should preview an item filter on the stored items", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			item, err := rss.NewItem(rss.Guid{Value: "http://www.example.com/1"}, "PHP 勉強会", "http://www.example.com/1", "", "", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			storedRss.AddOrUpdateItem(item)
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		sut := newServer(rssRepository, &spySlackSender{})
+		api := httptest.NewServer(sut.APIHandler())
+		defer api.Close()
+
+		// Act
+		response, err := http.Post(api.URL+"/api/v1/rss/www.example.com/filter:preview", "application/json", strings.NewReader(`{"item_filter": {"exclude_keywords": ["PHP"]}}`))
+
+		// Assert
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		var preview struct {
+			Kept    int `json:"kept"`
+			Dropped int `json:"dropped"`
+			Items   []struct {
+				Reason  string `json:"reason"`
+				Pattern string `json:"pattern"`
+			} `json:"items"`
+		}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&preview))
+		assert.Equal(t, 0, preview.Kept)
+		assert.Equal(t, 1, preview.Dropped)
+		assert.Len(t, preview.Items, 1)
+		assert.Equal(t, rss.FilterReasonExcluded, preview.Items[0].Reason)
+		assert.Equal(t, "PHP", preview.Items[0].Pattern)
+	})
+	t.Run("should enable a disabled feed again when it is refreshed", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		feedServer := newFeedServer(time.Now().UTC())
+		defer feedServer.Close()
+
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", feedServer.URL, "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			storedRss, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			if err != nil {
+				return err
+			}
+			status := rss.FetchStatus{}
+			status.RecordFailure(time.Now().UTC(), http.StatusServiceUnavailable, errors.New("unavailable"), 1)
+			return rssRepository.SaveFetchStatus(ctx, storedRss, status)
+		})
+		sut := newServer(rssRepository, &spySlackSender{})
+		api := httptest.NewServer(sut.APIHandler())
+		defer api.Close()
+
+		// Act
+		response, err := http.Post(api.URL+"/api/v1/rss/www.example.com/refresh", "application/json", nil)
+		assert.NoError(t, err)
+		response.Body.Close()
+		sut.Wait()
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		storedRss, err := rssRepository.FindBySource(ctx, "www.example.com")
+		assert.NoError(t, err)
+		assert.False(t, storedRss.FetchStatus.IsDisabled())
+		assert.Zero(t, storedRss.FetchStatus.ConsecutiveFailures)
+	})
+}
@@ -0,0 +1,296 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/workday-server/server"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+type spySlackSender struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (s *spySlackSender) PostMessageContext(ctx context.Context, text string, username string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, text)
+	return "", "", nil
+}
+
+func (s *spySlackSender) Messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func newFeedServer(now time.Time) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <description>このフィードはダミーニュースを提供します。</description>
+  <language>ja</language>
+  <lastBuildDate>%[1]s</lastBuildDate>
+
+  <item>
+    <title>ダミー記事1</title>
+    <guid>http://www.example.com/dummy-guid1</guid>
+    <link>http://www.example.com/dummy-article1</link>
+    <description>これはダミー記事1の概要です。</description>
+    <pubDate>%[1]s</pubDate>
+  </item>
+</channel>
+</rss>`, now.Format(time.RFC1123Z))
+	}))
+}
+
+func newServer(rssRepository rss.IRssRepository, slackSender server.SlackSender) *server.Server {
+	config := server.DefaultConfig()
+	config.BatchSleep = 0
+
+	return server.New(config, server.Dependencies{
+		Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RssRepository: rssRepository,
+		HttpClient:    &http.Client{},
+		Translator:    server.NoopTranslator{},
+		SlackSender:   slackSender,
+	})
+}
+
+func TestServer_Trigger(t *testing.T) {
+	t.Run("should run a stored feed through the whole pipeline and notify its new items", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		feedServer := newFeedServer(time.Now().UTC())
+		defer feedServer.Close()
+
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", feedServer.URL, "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		slackSender := &spySlackSender{}
+		sut := newServer(rssRepository, slackSender)
+
+		// Act
+		err := sut.Trigger(ctx)
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+
+		storedRss, err := rssRepository.FindBySource(ctx, "www.example.com")
+		assert.NoError(t, err)
+		storedRss, err = rssRepository.FindItems(ctx, storedRss)
+		assert.NoError(t, err)
+		assert.Len(t, storedRss.Items, 1)
+		assert.Equal(t, 2, storedRss.Version)
+
+		messages := slackSender.Messages()
+		assert.Len(t, messages, 1)
+		assert.Contains(t, messages[0], "ダミー記事1")
+	})
+
+	t.Run("should not notify the items again when a freshly fetched feed is paused and resumed", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		feedServer := newFeedServer(time.Now().UTC())
+		defer feedServer.Close()
+
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", feedServer.URL, "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		slackSender := &spySlackSender{}
+		sut := newServer(rssRepository, slackSender)
+		api := httptest.NewServer(sut.APIHandler())
+		defer api.Close()
+
+		helper.MustSucceed(t, func() error {
+			err := sut.Trigger(ctx)
+			sut.Wait()
+			return err
+		})
+		assert.Len(t, slackSender.Messages(), 1)
+
+		// Act
+		for _, action := range []string{"pause", "resume"} {
+			response, err := http.Post(api.URL+"/api/v1/rss/www.example.com:"+action, "application/json", nil)
+			assert.NoError(t, err)
+			response.Body.Close()
+			assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		}
+		sut.Wait()
+
+		// Assert
+		storedRss, err := rssRepository.FindBySource(ctx, "www.example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, rss.StatusActive, storedRss.Status)
+		assert.Len(t, slackSender.Messages(), 1)
+	})
+
+	t.Run("should not publish anything for paused feeds", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		var requested atomic.Bool
+		feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested.Store(true)
+		}))
+		defer feedServer.Close()
+
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", feedServer.URL, "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			storedRss.Pause()
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		slackSender := &spySlackSender{}
+		sut := newServer(rssRepository, slackSender)
+
+		// Act
+		err := sut.Trigger(ctx)
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, requested.Load())
+		assert.Empty(t, slackSender.Messages())
+	})
+
+	t.Run("should import a feed too large for one message in parts", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		now := time.Now().UTC()
+		feedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0">
+<channel>
+  <title>ダミーニュースのフィード</title>
+  <link>http://www.example.com/</link>
+  <language>ja</language>
+  <lastBuildDate>%s</lastBuildDate>
+`, now.Format(time.RFC1123Z))
+			for i := 0; i < 500; i++ {
+				fmt.Fprintf(w, `  <item>
+    <title>ダミー記事%[1]d</title>
+    <guid>http://www.example.com/dummy-guid%[1]d</guid>
+    <link>http://www.example.com/dummy-article%[1]d</link>
+    <description>%[2]s</description>
+    <pubDate>%[3]s</pubDate>
+  </item>
+`, i, strings.Repeat("これはダミー記事の概要です。", 40), now.Format(time.RFC1123Z))
+			}
+			fmt.Fprint(w, "</channel>\n</rss>")
+		}))
+		defer feedServer.Close()
+
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", feedServer.URL, "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		sut := newServer(rssRepository, &spySlackSender{})
+
+		// Act
+		err := sut.Trigger(ctx)
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+
+		storedRss, err := rssRepository.FindBySource(ctx, "www.example.com")
+		assert.NoError(t, err)
+		storedRss, err = rssRepository.FindItems(ctx, storedRss)
+		assert.NoError(t, err)
+		assert.Len(t, storedRss.Items, 500)
+
+		deadLetters, err := sut.DeadLetterStore().FindAll(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, deadLetters)
+	})
+}
+
+func TestServer_Delete(t *testing.T) {
+	t.Run("should move the feed to the trash when a delete message is published", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		rssRepository := rss.NewInMemoryRssRepository()
+		helper.MustSucceed(t, func() error {
+			storedRss, err := rss.New("ダミーニュースのフィード", "www.example.com", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			_, err = rssRepository.Save(ctx, storedRss, helper.ContractUser)
+			return err
+		})
+		sut := newServer(rssRepository, &spySlackSender{})
+
+		// Act
+		err := sut.DeletePublisher().Publish(ctx, "www.example.com")
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		storedRss, err := rssRepository.FindBySource(ctx, "www.example.com")
+		assert.NoError(t, err)
+		assert.True(t, storedRss.IsTrashed())
+	})
+}
+
+func TestServer_DeadLetter(t *testing.T) {
+	t.Run("should keep a message that fails permanently as a dead letter", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		sut := newServer(rss.NewInMemoryRssRepository(), &spySlackSender{})
+
+		// Act
+		err := sut.DeletePublisher().Publish(ctx, "www.example.com")
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		deadLetters, err := sut.DeadLetterStore().FindAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, message.StageDelete, deadLetters[0].Stage)
+		var deleteMessage message.Delete
+		_, err = message.Decode(deadLetters[0].Message, message.TypeDelete, &deleteMessage)
+		assert.NoError(t, err)
+		assert.Equal(t, "www.example.com", deleteMessage.Source)
+		assert.Contains(t, deadLetters[0].Reason, "not found source")
+	})
+}
@@ -0,0 +1,266 @@
+package workdayctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/cmd/workdayctl/dlq"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+type spyMessageClient struct{ Messages []string }
+
+func (r *spyMessageClient) Publish(ctx context.Context, message string) error {
+	r.Messages = append(r.Messages, message)
+	return nil
+}
+
+func newWriteDeadLetter(t *testing.T, stage string, messageID string, failedAt time.Time) message.DeadLetter {
+	var envelope message.Envelope
+	helper.MustSucceed(t, func() error {
+		rssEntry, err := rss.New("ダミーニュースのフィード", "www.example.com", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+		writeMessage, err := message.NewWriteMessage(rssEntry)
+		if err != nil {
+			return err
+		}
+		ctx := message.ContextWithTrace(context.Background(), message.Trace{CorrelationID: "correlation-1", Origin: message.StageClean})
+		envelope, err = message.NewEnvelope(ctx, message.TypeWrite, writeMessage)
+		return err
+	})
+	messageJson, _ := json.Marshal(envelope)
+	return message.DeadLetter{Stage: stage, MessageID: messageID, Message: string(messageJson), Reason: "translate api returned 400", FailedAt: failedAt}
+}
+
+func newStore(t *testing.T, deadLetters ...message.DeadLetter) *deadletter.InMemoryStore {
+	store := deadletter.NewInMemoryStore()
+	for _, deadLetter := range deadLetters {
+		helper.MustSucceed(t, func() error { return store.Save(context.Background(), deadLetter) })
+	}
+	return store
+}
+
+func TestDlq_List(t *testing.T) {
+	t.Run("should list the dead letters of the given stage", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		failedAt := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+		store := newStore(t,
+			newWriteDeadLetter(t, message.StageTranslate, "message-1", failedAt),
+			message.DeadLetter{Stage: message.StageDelete, MessageID: "message-2", Message: `{"source":"www.example.com"}`, Reason: "not found source: www.example.com", FailedAt: failedAt},
+		)
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"list", "-stage", message.StageTranslate}, dlq.Dependencies{Store: store, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Contains(t, stdout.String(), "message-1")
+		assert.Contains(t, stdout.String(), "translate api returned 400")
+		assert.NotContains(t, stdout.String(), "message-2")
+	})
+}
+
+func TestDlq_Show(t *testing.T) {
+	t.Run("should print the dead letter with its message as JSON", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := newStore(t, message.DeadLetter{Stage: message.StageDelete, MessageID: "message-1", Message: `{"source":"www.example.com"}`, Reason: "reason", FailedAt: time.Now().UTC()})
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"show", "message-1"}, dlq.Dependencies{Store: store, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		var shown struct {
+			Stage   string         `json:"stage"`
+			Message message.Delete `json:"message"`
+		}
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &shown))
+		assert.Equal(t, message.StageDelete, shown.Stage)
+		assert.Equal(t, "www.example.com", shown.Message.Source)
+	})
+
+	t.Run("should fail for an unknown message ID", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"show", "message-1"}, dlq.Dependencies{Store: newStore(t), Stdout: &stdout})
+
+		// Assert
+		assert.ErrorIs(t, err, deadletter.ErrNotFound)
+	})
+}
+
+func TestDlq_Replay(t *testing.T) {
+	t.Run("should republish to the topic of the failed stage and remove the dead letter", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		deadLetter := newWriteDeadLetter(t, message.StageTranslate, "message-1", time.Now().UTC())
+		store := newStore(t, deadLetter)
+		translateClient := spyMessageClient{}
+		writeClient := spyMessageClient{}
+		publishers := dlq.Publishers{
+			Translate: publisher.NewWriterMessagePublisher(&translateClient),
+			Write:     publisher.NewWriterMessagePublisher(&writeClient),
+		}
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"replay", "message-1"}, dlq.Dependencies{Store: store, Publishers: publishers, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, translateClient.Messages, 1)
+		assert.Empty(t, writeClient.Messages)
+
+		var writeMessage message.Write
+		envelope, err := message.Decode(translateClient.Messages[0], message.TypeWrite, &writeMessage)
+		assert.NoError(t, err)
+		assert.Equal(t, "www.example.com", writeMessage.RssFeed.Source)
+		assert.Equal(t, "correlation-1", envelope.CorrelationID)
+		assert.Equal(t, message.OriginReplay, envelope.Origin)
+
+		_, err = store.Find(ctx, "message-1")
+		assert.ErrorIs(t, err, deadletter.ErrNotFound)
+	})
+
+	t.Run("should republish the message of a dead letter sent as a claim check", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		claimCheckStore := claimcheck.NewFileStore(t.TempDir())
+		deadLetter := newWriteDeadLetter(t, message.StageWrite, "message-1", time.Now().UTC())
+		helper.MustSucceed(t, func() error {
+			if err := claimCheckStore.Put(ctx, "dead_letter/message-1.json", []byte(deadLetter.Message)); err != nil {
+				return err
+			}
+			deadLetter.Message = ""
+			deadLetter.ClaimCheck = "dead_letter/message-1.json"
+			return nil
+		})
+		store := newStore(t, deadLetter)
+		writeClient := spyMessageClient{}
+		publishers := dlq.Publishers{Write: publisher.NewWriterMessagePublisher(&writeClient)}
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"replay", "message-1"}, dlq.Dependencies{Store: store, Publishers: publishers, ClaimCheckStore: claimCheckStore, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, writeClient.Messages, 1)
+
+		var writeMessage message.Write
+		_, err = message.Decode(writeClient.Messages[0], message.TypeWrite, &writeMessage)
+		assert.NoError(t, err)
+		assert.Equal(t, "www.example.com", writeMessage.RssFeed.Source)
+	})
+
+	t.Run("should republish a legacy delete message through the delete publisher", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := newStore(t, message.DeadLetter{Stage: message.StageDelete, MessageID: "message-1", Message: `{"source":"www.example.com"}`, FailedAt: time.Now().UTC()})
+		deleteClient := spyMessageClient{}
+		publishers := dlq.Publishers{Delete: publisher.NewDeleteMessagePublisher(&deleteClient)}
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"replay", "-all"}, dlq.Dependencies{Store: store, Publishers: publishers, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, deleteClient.Messages, 1)
+
+		var deleteMessage message.Delete
+		_, err = message.Decode(deleteClient.Messages[0], message.TypeDelete, &deleteMessage)
+		assert.NoError(t, err)
+		assert.Equal(t, "www.example.com", deleteMessage.Source)
+	})
+
+	t.Run("should keep the dead letters it cannot replay", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := newStore(t,
+			message.DeadLetter{Stage: message.StageNotification, MessageID: "message-1", Message: "{}", FailedAt: time.Now().UTC()},
+			newWriteDeadLetter(t, message.StageWrite, "message-2", time.Now().UTC()),
+		)
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"replay", "-all"}, dlq.Dependencies{Store: store, Stdout: &stdout})
+
+		// Assert
+		assert.ErrorContains(t, err, "message-1")
+		assert.ErrorContains(t, err, "message-2")
+		deadLetters, _ := store.FindAll(ctx)
+		assert.Len(t, deadLetters, 2)
+	})
+
+	t.Run("should not publish on a dry run", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := newStore(t, newWriteDeadLetter(t, message.StageWrite, "message-1", time.Now().UTC()))
+		writeClient := spyMessageClient{}
+		publishers := dlq.Publishers{Write: publisher.NewWriterMessagePublisher(&writeClient)}
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"replay", "-dry-run", "-all"}, dlq.Dependencies{Store: store, Publishers: publishers, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, writeClient.Messages)
+		assert.Contains(t, stdout.String(), "would replay message-1 to write")
+	})
+}
+
+func TestDlq_Purge(t *testing.T) {
+	t.Run("should refuse to purge without -all or message IDs", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := newStore(t, newWriteDeadLetter(t, message.StageWrite, "message-1", time.Now().UTC()))
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"purge"}, dlq.Dependencies{Store: store, Stdout: &stdout})
+
+		// Assert
+		assert.Error(t, err)
+		deadLetters, _ := store.FindAll(ctx)
+		assert.Len(t, deadLetters, 1)
+	})
+
+	t.Run("should delete the dead letters of the given stage", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		store := newStore(t,
+			newWriteDeadLetter(t, message.StageWrite, "message-1", time.Now().UTC()),
+			newWriteDeadLetter(t, message.StageClean, "message-2", time.Now().UTC()),
+		)
+		var stdout bytes.Buffer
+
+		// Act
+		err := dlq.Run(ctx, []string{"purge", "-stage", message.StageWrite, "-all"}, dlq.Dependencies{Store: store, Stdout: &stdout})
+
+		// Assert
+		assert.NoError(t, err)
+		deadLetters, _ := store.FindAll(ctx)
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, "message-2", deadLetters[0].MessageID)
+	})
+}
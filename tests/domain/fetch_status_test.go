@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchStatus_RecordFailure(t *testing.T) {
+	t.Run("should disable the feed when failures reach the limit", func(t *testing.T) {
+		// Arrange
+		status := rss.FetchStatus{}
+		now := time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC)
+
+		// Act
+		disabled := []bool{
+			status.RecordFailure(now, 500, errors.New("error 1"), 3),
+			status.RecordFailure(now, 500, errors.New("error 2"), 3),
+			status.RecordFailure(now, 404, errors.New("error 3"), 3),
+			status.RecordFailure(now, 404, errors.New("error 4"), 3),
+		}
+
+		// Assert
+		assert.Equal(t, []bool{false, false, true, false}, disabled)
+		assert.Equal(t, 4, status.ConsecutiveFailures)
+		assert.Equal(t, 404, status.HTTPStatus)
+		assert.Equal(t, "error 4", status.LastError)
+		assert.Equal(t, now, status.LastFailureAt)
+		assert.Equal(t, now, status.DisabledAt)
+		assert.True(t, status.IsDisabled())
+	})
+
+	t.Run("should never disable the feed when the limit is zero", func(t *testing.T) {
+		// Arrange
+		status := rss.FetchStatus{}
+		now := time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC)
+
+		// Act
+		disabled := status.RecordFailure(now, 500, errors.New("error"), 0)
+
+		// Assert
+		assert.False(t, disabled)
+		assert.False(t, status.IsDisabled())
+	})
+}
+
+func TestFetchStatus_RecordSuccess(t *testing.T) {
+	t.Run("should reset failures and enable the feed", func(t *testing.T) {
+		// Arrange
+		failedAt := time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC)
+		now := failedAt.Add(time.Hour)
+		status := rss.FetchStatus{
+			LastFailureAt:       failedAt,
+			LastError:           "error",
+			ConsecutiveFailures: 5,
+			HTTPStatus:          500,
+			DisabledAt:          failedAt,
+		}
+
+		// Act
+		status.RecordSuccess(now, 200)
+
+		// Assert
+		assert.Equal(t, now, status.LastSuccessAt)
+		assert.Equal(t, 0, status.ConsecutiveFailures)
+		assert.Equal(t, 200, status.HTTPStatus)
+		assert.False(t, status.IsDisabled())
+		assert.Equal(t, failedAt, status.LastFailureAt)
+		assert.Equal(t, "error", status.LastError)
+	})
+}
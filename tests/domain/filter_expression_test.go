@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFilterTestItem(t *testing.T) rss.Item {
+	item, err := rss.NewItem(rss.Guid{Value: "http://www.example.com/dummy-guid1"}, "Go 1.23 is released", "http://www.example.com/blog/go1.23", "The Go team announces golang 1.23.", "Jane Doe", time.Date(2024, time.July, 3, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item.Tags = []string{"golang", "release"}
+	return item
+}
+
+func TestParseFilterExpression_Match(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		expected   bool
+	}{
+		{name: "unscoped term in the title", expression: "released", expected: true},
+		{name: "unscoped term in the description", expression: "announces", expected: true},
+		{name: "unscoped term is case-sensitive", expression: "RELEASED", expected: false},
+		{name: "quoted phrase", expression: `"Go team"`, expected: true},
+		{name: "title scope", expression: "title:team", expected: false},
+		{name: "description scope", expression: "description:team", expected: true},
+		{name: "author scope", expression: `author:"Jane Doe"`, expected: true},
+		{name: "link scope", expression: "link:/blog/", expected: true},
+		{name: "tag contains", expression: "tag:lang", expected: true},
+		{name: "tag whole word", expression: "tag=lang", expected: false},
+		{name: "case-insensitive", expression: "title~go", expected: true},
+		{name: "case-insensitive miss", expression: "title~rust", expected: false},
+		{name: "whole word", expression: "description=golang", expected: true},
+		{name: "whole word does not match inside a word", expression: "description=gola", expected: false},
+		{name: "whole word is case-sensitive", expression: "title=go", expected: false},
+		{name: "whole word ignoring case", expression: "title~=go", expected: true},
+		{name: "AND", expression: "title:Go AND tag=release", expected: true},
+		{name: "implicit AND", expression: "title:Go tag=rust", expected: false},
+		{name: "OR", expression: "tag=rust OR tag=golang", expected: true},
+		{name: "NOT", expression: "NOT author:bot", expected: true},
+		{name: "AND binds tighter than OR", expression: "tag=rust AND tag=python OR title:Go", expected: true},
+		{name: "parentheses", expression: "tag=rust AND (tag=python OR title:Go)", expected: false},
+		{name: "nested NOT", expression: "NOT (title:Go AND NOT tag=golang)", expected: true},
+		{name: "date on", expression: "pubdate=2024-07-03", expected: true},
+		{name: "date before", expression: "pubdate<2024-07-03", expected: false},
+		{name: "date before or on", expression: "pubdate<=2024-07-03", expected: true},
+		{name: "date after", expression: "pubdate>2024-07-02", expected: true},
+		{name: "date after the same day", expression: "pubdate>2024-07-03", expected: false},
+		{name: "date after or on", expression: "pubdate>=2024-07-03", expected: true},
+		{name: "time with an offset", expression: "pubdate<2024-07-03T20:00:00+09:00", expected: false},
+		{name: "time instant", expression: "pubdate=2024-07-03T12:00:00Z", expected: true},
+		{name: "lowercase keywords are words", expression: "title:Go and", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			item := newFilterTestItem(t)
+
+			// Act
+			expression, err := rss.ParseFilterExpression(tc.expression)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, expression.Match(item))
+		})
+	}
+}
+
+func TestParseFilterExpression_AST(t *testing.T) {
+	t.Run("should build the AST with AND binding tighter than OR", func(t *testing.T) {
+		// Act
+		expression, err := rss.ParseFilterExpression(`title~go OR NOT tag=draft author:"Jane Doe"`)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rss.FilterOr{
+			Left: rss.FilterText{Field: rss.FieldTitle, Operator: rss.OperatorContainsFold, Value: "go"},
+			Right: rss.FilterAnd{
+				Left:  rss.FilterNot{Operand: rss.FilterText{Field: rss.FieldTag, Operator: rss.OperatorWord, Value: "draft"}},
+				Right: rss.FilterText{Field: rss.FieldAuthor, Operator: rss.OperatorContains, Value: "Jane Doe"},
+			},
+		}, expression)
+	})
+
+	t.Run("should take a prefix that is not a field as part of an unscoped value", func(t *testing.T) {
+		for _, source := range []string{"https://go.dev", "foo:bar", "Note~="} {
+			// Act
+			expression, err := rss.ParseFilterExpression(source)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, rss.FilterText{Field: rss.FieldAny, Operator: rss.OperatorContains, Value: source}, expression)
+		}
+	})
+
+	t.Run("should print a canonical form that parses back to the same AST", func(t *testing.T) {
+		for _, source := range []string{
+			`(title~go OR tag=golang) AND NOT author:"bot \"x\"" pubdate>=2024-01-01`,
+			`go pubdate<2024-07-03T20:00:00+09:00`,
+		} {
+			// Arrange
+			expression, err := rss.ParseFilterExpression(source)
+			assert.NoError(t, err)
+
+			// Act
+			reparsed, err := rss.ParseFilterExpression(expression.String())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, expression.String(), reparsed.String())
+		}
+	})
+}
+
+func TestParseFilterExpression_Error(t *testing.T) {
+	testCases := []struct {
+		expression string
+		position   int
+	}{
+		{expression: "", position: 0},
+		{expression: "title:go AND", position: 12},
+		{expression: "(title:go", position: 9},
+		{expression: "title:go)", position: 8},
+		{expression: "title:", position: 6},
+		{expression: `author:"Jane`, position: 7},
+		{expression: "title<go", position: 0},
+		{expression: "go pubdate:2024-07-03", position: 3},
+		{expression: "pubdate>yesterday", position: 0},
+		{expression: "OR go", position: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			// Act
+			_, err := rss.ParseFilterExpression(tc.expression)
+
+			// Assert
+			var expressionErr *rss.FilterExpressionError
+			assert.ErrorAs(t, err, &expressionErr)
+			assert.Equal(t, tc.position, expressionErr.Position)
+		})
+	}
+}
+
+func TestParseFilterExpression_Limits(t *testing.T) {
+	t.Run("should accept NOT and parentheses nested up to the limit", func(t *testing.T) {
+		// Arrange
+		depth := rss.MaxFilterExpressionDepth / 2
+		source := strings.Repeat("NOT (", depth) + "go" + strings.Repeat(")", depth)
+
+		// Act
+		_, err := rss.ParseFilterExpression(source)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	testCases := []struct {
+		name       string
+		expression string
+		position   int
+	}{
+		{"parentheses nested too deep", strings.Repeat("(", rss.MaxFilterExpressionDepth+1) + "go" + strings.Repeat(")", rss.MaxFilterExpressionDepth+1), rss.MaxFilterExpressionDepth},
+		{"NOT nested too deep", strings.Repeat("NOT ", rss.MaxFilterExpressionDepth+1) + "go", rss.MaxFilterExpressionDepth * len("NOT ")},
+		{"too long", strings.Repeat("go ", rss.MaxFilterExpressionLength/3+1), rss.MaxFilterExpressionLength},
+	}
+	for _, tc := range testCases {
+		t.Run("should reject "+tc.name, func(t *testing.T) {
+			// Act
+			_, err := rss.ParseFilterExpression(tc.expression)
+
+			// Assert
+			var expressionErr *rss.FilterExpressionError
+			assert.ErrorAs(t, err, &expressionErr)
+			assert.Equal(t, tc.position, expressionErr.Position)
+		})
+	}
+}
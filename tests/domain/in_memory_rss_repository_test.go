@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+)
+
+func TestInMemoryRssRepository_Contract(t *testing.T) {
+	helper.RunRssRepositoryContract(t, func(t *testing.T) rss.IRssRepository {
+		return rss.NewInMemoryRssRepository()
+	})
+}
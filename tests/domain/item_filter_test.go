@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"sync"
 	"testing"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
@@ -192,6 +193,130 @@ func TestItemFilter_IsMatch(t *testing.T) {
 		// Assert
 		assert.False(t, result)
 	})
+
+	t.Run("should match the keywords set after the filter was built", func(t *testing.T) {
+		// Arrange
+		filter := rss.NewItemFilter([]string{"python"}, nil)
+		filter.IncludeKeywords = []string{"go"}
+		item := rss.Item{
+			Title:       "Go Programming",
+			Description: "An article about golang",
+		}
+
+		// Act
+		result := filter.IsMatch(item)
+
+		// Assert
+		assert.True(t, result)
+	})
+
+	t.Run("should be safe to match with copies of a filter concurrently", func(t *testing.T) {
+		// Arrange
+		var filter rss.ItemFilter
+		err := json.Unmarshal([]byte(`{"include_keywords":["go"],"exclude_keywords":["python"],"expression":"title~=go"}`), &filter)
+		assert.NoError(t, err)
+		item := rss.Item{
+			Title:       "Go Programming",
+			Description: "An article about golang",
+		}
+
+		// Act
+		results := make([]bool, 8)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func(filter rss.ItemFilter) {
+				defer wg.Done()
+				results[i] = filter.IsMatch(item)
+			}(filter)
+		}
+		wg.Wait()
+
+		// Assert
+		for _, result := range results {
+			assert.True(t, result)
+		}
+	})
+}
+
+func TestItemFilter_Explain(t *testing.T) {
+	item := rss.Item{
+		Title:       "Go 勉強会 #12",
+		Description: "An article about golang",
+		Author:      "bot",
+	}
+
+	testCases := []struct {
+		name     string
+		filter   func(t *testing.T) rss.ItemFilter
+		expected rss.FilterVerdict
+	}{
+		{
+			name:     "should keep an item with no pattern when the filter is empty",
+			filter:   func(t *testing.T) rss.ItemFilter { return rss.NewItemFilter(nil, nil) },
+			expected: rss.FilterVerdict{Kept: true, Reason: rss.FilterReasonKept},
+		},
+		{
+			name:     "should keep an item with the first include keyword it matches",
+			filter:   func(t *testing.T) rss.ItemFilter { return rss.NewItemFilter([]string{"python", "golang", "Go"}, nil) },
+			expected: rss.FilterVerdict{Kept: true, Reason: rss.FilterReasonKept, Pattern: "golang"},
+		},
+		{
+			name:     "should keep an item with the empty include keyword, which matches anything",
+			filter:   func(t *testing.T) rss.ItemFilter { return rss.NewItemFilter([]string{""}, nil) },
+			expected: rss.FilterVerdict{Kept: true, Reason: rss.FilterReasonKept, Pattern: ""},
+		},
+		{
+			name:     "should drop an item that matches none of the include keywords",
+			filter:   func(t *testing.T) rss.ItemFilter { return rss.NewItemFilter([]string{"python", "ruby"}, nil) },
+			expected: rss.FilterVerdict{Reason: rss.FilterReasonNotIncluded},
+		},
+		{
+			name: "should drop an item with the exclude keyword it matches",
+			filter: func(t *testing.T) rss.ItemFilter {
+				return rss.NewItemFilter([]string{"golang"}, []string{"PHP", ".*勉強会.*"})
+			},
+			expected: rss.FilterVerdict{Reason: rss.FilterReasonExcluded, Pattern: ".*勉強会.*"},
+		},
+		{
+			name: "should skip an invalid keyword",
+			filter: func(t *testing.T) rss.ItemFilter {
+				return rss.NewItemFilter([]string{"(go", "golang"}, []string{"(勉強会"})
+			},
+			expected: rss.FilterVerdict{Kept: true, Reason: rss.FilterReasonKept, Pattern: "golang"},
+		},
+		{
+			name: "should drop an item with the expression it does not match",
+			filter: func(t *testing.T) rss.ItemFilter {
+				filter, err := rss.NewItemFilterWithExpression([]string{"golang"}, nil, "NOT author:bot")
+				assert.NoError(t, err)
+				return filter
+			},
+			expected: rss.FilterVerdict{Reason: rss.FilterReasonExpression, Pattern: "NOT author:bot"},
+		},
+		{
+			name: "should keep an item that matches the expression",
+			filter: func(t *testing.T) rss.ItemFilter {
+				filter, err := rss.NewItemFilterWithExpression(nil, nil, "title~go")
+				assert.NoError(t, err)
+				return filter
+			},
+			expected: rss.FilterVerdict{Kept: true, Reason: rss.FilterReasonKept},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			filter := tc.filter(t)
+
+			// Act
+			verdict := filter.Explain(item)
+
+			// Assert
+			assert.Equal(t, tc.expected, verdict)
+			assert.Equal(t, verdict.Kept, filter.IsMatch(item))
+		})
+	}
 }
 
 func TestItemFilter_Serialize(t *testing.T) {
@@ -285,3 +410,78 @@ func TestItemFilter_Equal(t *testing.T) {
 		assert.False(t, result)
 	})
 }
+
+func TestItemFilter_Expression(t *testing.T) {
+	t.Run("should require an item to match both the keywords and the expression", func(t *testing.T) {
+		// Arrange
+		filter, err := rss.NewItemFilterWithExpression([]string{"go"}, nil, "NOT tag=draft")
+		assert.NoError(t, err)
+		released := rss.Item{Title: "go 1.23", Tags: []string{"release"}}
+		draft := rss.Item{Title: "go 1.24", Tags: []string{"draft"}}
+		python := rss.Item{Title: "python 3.13", Tags: []string{"release"}}
+
+		// Act & Assert
+		assert.True(t, filter.IsMatch(released))
+		assert.False(t, filter.IsMatch(draft))
+		assert.False(t, filter.IsMatch(python))
+	})
+
+	t.Run("should return a FilterExpressionError for an invalid expression", func(t *testing.T) {
+		// Act
+		_, err := rss.NewItemFilterWithExpression(nil, nil, "title:go AND (")
+
+		// Assert
+		var expressionErr *rss.FilterExpressionError
+		assert.ErrorAs(t, err, &expressionErr)
+	})
+
+	t.Run("should keep the previous expression when the new one is invalid", func(t *testing.T) {
+		// Arrange
+		filter, err := rss.NewItemFilterWithExpression(nil, nil, "title:go")
+		assert.NoError(t, err)
+
+		// Act
+		err = filter.SetExpression("title:")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "title:go", filter.Expression)
+	})
+
+	t.Run("should evaluate the expression of a deserialized filter", func(t *testing.T) {
+		// Arrange
+		var filter rss.ItemFilter
+		err := json.Unmarshal([]byte(`{"include_keywords":[],"exclude_keywords":[],"expression":"title~=go"}`), &filter)
+		assert.NoError(t, err)
+
+		// Act & Assert
+		assert.True(t, filter.IsMatch(rss.Item{Title: "Go 1.23"}))
+		assert.False(t, filter.IsMatch(rss.Item{Title: "golang 1.23"}))
+	})
+
+	t.Run("should serialize the expression", func(t *testing.T) {
+		// Arrange
+		filter, err := rss.NewItemFilterWithExpression(nil, nil, "tag=go")
+		assert.NoError(t, err)
+
+		// Act
+		jsonData, err := json.Marshal(filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"include_keywords":[],"exclude_keywords":[],"expression":"tag=go"}`, string(jsonData))
+	})
+
+	t.Run("should not be equal to a filter with another expression", func(t *testing.T) {
+		// Arrange
+		filter1, err := rss.NewItemFilterWithExpression([]string{"go"}, nil, "tag=go")
+		assert.NoError(t, err)
+		filter2 := rss.NewItemFilter([]string{"go"}, nil)
+
+		// Act
+		result := filter1.Equal(filter2)
+
+		// Assert
+		assert.False(t, result)
+	})
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -231,7 +232,7 @@ func TestItem_Serialize(t *testing.T) {
 
 		// Assert
 		assert.Equal(t,
-			`{"guid":"guid-12345","title":"Test Title","link":"http://example.com","description":"Test description","author":"Test Author","pubDate":"2024-06-01T13:30:00Z","tags":["tag1","tag2"]}`,
+			`{"guid":"guid-12345","title":"Test Title","link":"http://example.com","description":"Test description","author":"Test Author","pubDate":"2024-06-01T13:30:00Z","tags":["tag1","tag2"],"contentHash":"`+rss.ContentHash("Test Title", "http://example.com", "Test description")+`"}`,
 			string(jsonData))
 	})
 }
@@ -262,3 +263,47 @@ func TestItem_Deserialize(t *testing.T) {
 		assert.Equal(t, expectedItem, test_item)
 	})
 }
+
+func TestItem_Revise(t *testing.T) {
+	t.Run("should detect changed content by hash", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC)
+		stored, _ := rss.NewItem(rss.Guid{Value: "guid-12345"}, "Test Title", "http://example.com", "Test description", "Test Author", now)
+		unchanged, _ := rss.NewItem(rss.Guid{Value: "guid-12345"}, "Test Title", "http://example.com", "Test description", "Other Author", now)
+		changed, _ := rss.NewItem(rss.Guid{Value: "guid-12345"}, "Corrected Title", "http://example.com", "Test description", "Test Author", now)
+		legacy := stored
+		legacy.ContentHash = ""
+
+		// Act & Assert
+		assert.False(t, unchanged.HasChangedFrom(stored))
+		assert.True(t, changed.HasChangedFrom(stored))
+		assert.False(t, changed.HasChangedFrom(legacy))
+	})
+
+	t.Run("should keep the previous versions up to MaxItemRevisions", func(t *testing.T) {
+		// Arrange
+		pubDate := time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC)
+		current, _ := rss.NewItem(rss.Guid{Value: "guid-12345"}, "Title 0", "http://example.com", "", "", pubDate)
+
+		// Act
+		for i := 1; i <= rss.MaxItemRevisions+2; i++ {
+			next, _ := rss.NewItem(rss.Guid{Value: "guid-12345"}, fmt.Sprintf("Title %d", i), "http://example.com", "", "", pubDate)
+			next.Revise(current, pubDate.Add(time.Duration(i)*time.Hour))
+			current = next
+		}
+
+		// Assert
+		assert.Len(t, current.Revisions, rss.MaxItemRevisions)
+		assert.Equal(t, "Title 2", current.Revisions[0].Title)
+		assert.Equal(t, fmt.Sprintf("Title %d", rss.MaxItemRevisions+1), current.Revisions[rss.MaxItemRevisions-1].Title)
+		assert.Equal(t, pubDate.Add(time.Duration(rss.MaxItemRevisions+2)*time.Hour), current.RevisedAt())
+	})
+
+	t.Run("should return zero RevisedAt when never revised", func(t *testing.T) {
+		// Arrange
+		item, _ := rss.NewItem(rss.Guid{Value: "guid-12345"}, "Title", "http://example.com", "", "", time.Now())
+
+		// Act & Assert
+		assert.True(t, item.RevisedAt().IsZero())
+	})
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileKeyword(t *testing.T) {
+	t.Run("should compile a valid pattern", func(t *testing.T) {
+		// Act
+		re, err := rss.CompileKeyword(".*(PHP|php).*")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, re.MatchString("Laravel PHP"))
+	})
+
+	testCases := []struct {
+		name     string
+		pattern  string
+		position int
+		message  string
+	}{
+		{name: "unclosed parenthesis", pattern: ".*(PHP|php.*", position: 2, message: "missing closing )"},
+		{name: "unopened parenthesis", pattern: "go)lang", position: 2, message: "unexpected )"},
+		{name: "nested repetition", pattern: "a**", position: 1, message: "invalid nested repetition operator"},
+		{name: "unclosed character class", pattern: "foo[a-", position: 3, message: "missing closing ]"},
+		{name: "invalid escape", pattern: `go\q`, position: 2, message: "invalid escape sequence"},
+		{name: "invalid range", pattern: "ab[z-a]", position: 3, message: "invalid character class range"},
+		{name: "too long", pattern: strings.Repeat("a", rss.MaxKeywordLength+1), position: rss.MaxKeywordLength, message: "longer than"},
+		{name: "too complex", pattern: `(\w{100}){10}`, position: 0, message: "too complex"},
+	}
+
+	for _, tc := range testCases {
+		t.Run("should reject a pattern with "+tc.name, func(t *testing.T) {
+			// Act
+			_, err := rss.CompileKeyword(tc.pattern)
+
+			// Assert
+			var keywordErr *rss.KeywordError
+			assert.ErrorAs(t, err, &keywordErr)
+			assert.Equal(t, tc.pattern, keywordErr.Pattern)
+			assert.Equal(t, tc.position, keywordErr.Position)
+			assert.Contains(t, keywordErr.Message, tc.message)
+		})
+	}
+}
+
+func TestItemFilter_IsMatch_ManyKeywords(t *testing.T) {
+	t.Run("should keep matching once more patterns are tried than the cache holds", func(t *testing.T) {
+		// Arrange
+		item := rss.Item{Title: "event-0"}
+		first := rss.NewItemFilter([]string{"^event-0$"}, nil)
+		assert.True(t, first.IsMatch(item))
+
+		// Act
+		for i := 0; i < 2000; i++ {
+			filter := rss.NewItemFilter([]string{fmt.Sprintf("^event-%d-candidate$", i)}, nil)
+			filter.IsMatch(item)
+		}
+
+		// Assert
+		other := rss.NewItemFilter([]string{"^event-1$"}, nil)
+		assert.True(t, first.IsMatch(item))
+		assert.False(t, other.IsMatch(item))
+	})
+}
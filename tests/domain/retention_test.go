@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetentionItem(guid string, pubDate time.Time) rss.Item {
+	return rss.Item{Guid: rss.Guid{Value: guid}, Title: guid, Link: "http://example.com/" + guid, PubDate: pubDate}
+}
+
+func TestRetention_IsExpired(t *testing.T) {
+	now := time.Date(2024, time.August, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should expire items older than the max age", func(t *testing.T) {
+		// Arrange
+		retention := rss.NewRetention(30, 0)
+		oldItem := newRetentionItem("old", now.Add(-31*24*time.Hour))
+		newItem := newRetentionItem("new", now.Add(-29*24*time.Hour))
+
+		// Act
+		oldExpired := retention.IsExpired(oldItem, now)
+		newExpired := retention.IsExpired(newItem, now)
+
+		// Assert
+		assert.True(t, oldExpired)
+		assert.False(t, newExpired)
+		assert.Equal(t, oldItem.PubDate.Add(30*24*time.Hour), retention.ExpiresAt(oldItem))
+	})
+
+	t.Run("should never expire items when there is no max age", func(t *testing.T) {
+		// Arrange
+		retention := rss.NewRetention(0, 10)
+		item := newRetentionItem("old", now.Add(-3650*24*time.Hour))
+
+		// Act
+		expired := retention.IsExpired(item, now)
+
+		// Assert
+		assert.False(t, expired)
+		assert.True(t, retention.ExpiresAt(item).IsZero())
+	})
+}
+
+func TestRetention_Overflow(t *testing.T) {
+	t.Run("should return the oldest items beyond the max items", func(t *testing.T) {
+		// Arrange
+		base := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+		retention := rss.NewRetention(0, 2)
+		items := []rss.Item{
+			newRetentionItem("2", base.Add(2*time.Hour)),
+			newRetentionItem("0", base),
+			newRetentionItem("3", base.Add(3*time.Hour)),
+			newRetentionItem("1", base.Add(time.Hour)),
+		}
+
+		// Act
+		overflow := retention.Overflow(items)
+
+		// Assert
+		assert.Equal(t, []rss.Item{items[3], items[1]}, overflow)
+	})
+
+	t.Run("should return nothing when the items fit", func(t *testing.T) {
+		// Arrange
+		base := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+		retention := rss.NewRetention(0, 2)
+		items := []rss.Item{newRetentionItem("0", base), newRetentionItem("1", base)}
+
+		// Act
+		overflow := retention.Overflow(items)
+
+		// Assert
+		assert.Empty(t, overflow)
+	})
+}
+
+func TestRss_ApplyRetention(t *testing.T) {
+	t.Run("should remove expired items and then the oldest items beyond the max items", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, time.August, 10, 9, 0, 0, 0, time.UTC)
+		feed, err := rss.New("Title", "example.com", "http://example.com", "Description", "ja", now)
+		assert.NoError(t, err)
+		feed.SetRetention(rss.NewRetention(7, 2))
+		feed.AddOrUpdateItem(newRetentionItem("expired", now.Add(-8*24*time.Hour)))
+		feed.AddOrUpdateItem(newRetentionItem("oldest", now.Add(-3*24*time.Hour)))
+		feed.AddOrUpdateItem(newRetentionItem("older", now.Add(-2*24*time.Hour)))
+		feed.AddOrUpdateItem(newRetentionItem("newest", now.Add(-1*24*time.Hour)))
+
+		// Act
+		removed := feed.ApplyRetention(now)
+
+		// Assert
+		assert.Equal(t, 2, removed)
+		assert.Len(t, feed.Items, 2)
+		assert.Contains(t, feed.Items, rss.Guid{Value: "older"})
+		assert.Contains(t, feed.Items, rss.Guid{Value: "newest"})
+	})
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +31,14 @@ func schemaProvider() ([]types.AttributeDefinition, []types.KeySchemaElement, []
 			AttributeName: aws.String("sortKey"),
 			AttributeType: types.ScalarAttributeTypeS,
 		},
+		{
+			AttributeName: aws.String("rss_id"),
+			AttributeType: types.ScalarAttributeTypeS,
+		},
+		{
+			AttributeName: aws.String("pub_date"),
+			AttributeType: types.ScalarAttributeTypeN,
+		},
 	}
 
 	keySchema := []types.KeySchemaElement{
@@ -60,6 +69,26 @@ func schemaProvider() ([]types.AttributeDefinition, []types.KeySchemaElement, []
 				WriteCapacityUnits: aws.Int64(10),
 			},
 		},
+		{
+			IndexName: aws.String("RssIdPubDateIndex"),
+			KeySchema: []types.KeySchemaElement{
+				{
+					AttributeName: aws.String("rss_id"),
+					KeyType:       types.KeyTypeHash,
+				},
+				{
+					AttributeName: aws.String("pub_date"),
+					KeyType:       types.KeyTypeRange,
+				},
+			},
+			Projection: &types.Projection{
+				ProjectionType: types.ProjectionTypeAll,
+			},
+			ProvisionedThroughput: &types.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(10),
+				WriteCapacityUnits: aws.Int64(10),
+			},
+		},
 	}
 
 	return attributeDefinitions, keySchema, gsi
@@ -69,7 +98,7 @@ func TestRssRepository_Save(t *testing.T) {
 	t.Run("should save new rss when CreatedBy is empty", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		test_rss := getTestRss(t)
 
 		// Act
@@ -140,7 +169,7 @@ func TestRssRepository_Save(t *testing.T) {
 	t.Run("should update existing rss when RSS with same ID exists", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		initial_rss := getTestRss(t)
 
 		// Save initial RSS
@@ -220,13 +249,88 @@ func TestRssRepository_Save(t *testing.T) {
 		assert.Equal(t, "Test Author 3", actual_item3["author"])
 		assert_helper.EqualUnixTime(t, time.Date(2023, time.June, 3, 13, 30, 0, 0, time.UTC), actual_item3["pub_date"])
 	})
+
+	t.Run("should save items across several batch writes", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+
+		// Act
+		setUpRss := setupLargeRss(t, ctx, rssRepository, 60)
+
+		// Assert
+		foundRss, err := rssRepository.FindItems(ctx, setUpRss)
+		assert.NoError(t, err)
+		assert.Len(t, foundRss.Items, 60)
+
+		actual_rss, _ := helper.GetItem(ctx, client, "Rss", "Test_Source", "rss")
+		assert_helper.EqualUUID(t, setUpRss.ID, actual_rss["rss_id"])
+	})
+}
+
+func TestRssRepository_Save_Conflict(t *testing.T) {
+	t.Run("should return ConflictError when the rss was saved since it was read", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		savedRss, err := rssRepository.Save(ctx, getTestRss(t), metadata.UserMeta{ID: "test-id", Name: "test-user"})
+		require.NoError(t, err)
+
+		firstReader, err := rssRepository.FindBySource(ctx, savedRss.Source)
+		require.NoError(t, err)
+		secondReader, err := rssRepository.FindBySource(ctx, savedRss.Source)
+		require.NoError(t, err)
+
+		// Act
+		firstSaved, firstErr := rssRepository.Save(ctx, firstReader, metadata.UserMeta{ID: "test-id", Name: "test-user"})
+		_, secondErr := rssRepository.Save(ctx, secondReader, metadata.UserMeta{ID: "test-id", Name: "test-user"})
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.Equal(t, 1, savedRss.Version)
+		assert.Equal(t, 2, firstSaved.Version)
+
+		var conflict *rss.ConflictError
+		assert.ErrorAs(t, secondErr, &conflict)
+		assert.Equal(t, 1, conflict.Version)
+	})
+
+	t.Run("should write no items on a stale save of a feed too large for a transaction", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		savedRss, err := rssRepository.Save(ctx, getTestRss(t), metadata.UserMeta{ID: "test-id", Name: "test-user"})
+		require.NoError(t, err)
+		_, err = rssRepository.Save(ctx, savedRss, metadata.UserMeta{ID: "test-id", Name: "test-user"})
+		require.NoError(t, err)
+
+		staleRss := savedRss
+		staleRss.Items = map[rss.Guid]rss.Item{}
+		for i := 0; i < 30; i++ {
+			item, err := rss.NewItem(rss.Guid{Value: fmt.Sprintf("http://www.example.com/stale-guid%d", i)}, "Stale", "http://www.example.com/stale", "Stale", "stale@dummy.com", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			require.NoError(t, err)
+			staleRss.AddOrUpdateItem(item)
+		}
+
+		// Act
+		_, err = rssRepository.Save(ctx, staleRss, metadata.UserMeta{ID: "test-id", Name: "test-user"})
+
+		// Assert
+		var conflict *rss.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+		foundRss, err := rssRepository.FindItems(ctx, savedRss)
+		assert.NoError(t, err)
+		for guid := range staleRss.Items {
+			assert.NotContains(t, foundRss.Items, guid)
+		}
+	})
 }
 
 func TestRssRepository_FindBySource(t *testing.T) {
 	t.Run("should return error when source is empty", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -240,7 +344,7 @@ func TestRssRepository_FindBySource(t *testing.T) {
 	t.Run("should return Rss when source exists", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -269,10 +373,31 @@ func TestRssRepository_FindBySource(t *testing.T) {
 		assert.Len(t, actual_rss.Items, 0)
 	})
 
+	t.Run("should return the status and trash of the stored Rss", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupExpectedRss(t, ctx, rssRepository)
+		setUpRss.Items = map[rss.Guid]rss.Item{}
+		setUpRss.Pause()
+		setUpRss.Trash(time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), 30*24*time.Hour)
+		_, err := rssRepository.Save(ctx, setUpRss, metadata.UserMeta{ID: "test-id", Name: "test-user"})
+		require.NoError(t, err)
+
+		// Act
+		actual_rss, err := rssRepository.FindBySource(ctx, "Test_Source")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rss.StatusPaused, actual_rss.Status)
+		assert.Equal(t, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), actual_rss.TrashedAt)
+		assert.Equal(t, time.Date(2024, time.July, 31, 0, 0, 0, 0, time.UTC), actual_rss.PurgeAt)
+	})
+
 	t.Run("should return empty Rss when source does not exist", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -290,7 +415,7 @@ func TestRssRepository_FindAll(t *testing.T) {
 	t.Run("should return error when source is empty", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 
 		// Act
 		actual_rss_list, err := rssRepository.FindAll(ctx)
@@ -302,7 +427,7 @@ func TestRssRepository_FindAll(t *testing.T) {
 	t.Run("should return error when source is empty1", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -332,7 +457,7 @@ func TestRssRepository_FindAll(t *testing.T) {
 	t.Run("should return Rss when source exists", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 
 		var testRSS1 rss.Rss
 		helper.MustSucceed(t, func() error {
@@ -451,7 +576,7 @@ func TestRssRepository_FindItems(t *testing.T) {
 	t.Run("should return error when rss is empty", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -466,7 +591,7 @@ func TestRssRepository_FindItems(t *testing.T) {
 	t.Run("should return Items when rss exists", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setUpRss := setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -499,7 +624,7 @@ func TestRssRepository_FindItem(t *testing.T) {
 	t.Run("should return error when rss is empty", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -514,7 +639,7 @@ func TestRssRepository_FindItem(t *testing.T) {
 	t.Run("should return Items when rss exists", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setUpRss := setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -536,7 +661,7 @@ func TestRssRepository_FindItem(t *testing.T) {
 	t.Run("should return empty Items when GUID does not exist", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setUpRss := setupExpectedRss(t, ctx, rssRepository)
 
 		// Act
@@ -551,11 +676,202 @@ func TestRssRepository_FindItem(t *testing.T) {
 	})
 }
 
+func TestRssRepository_FindItemsPage(t *testing.T) {
+	t.Run("should page through items in PubDate order", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupExpectedRss(t, ctx, rssRepository)
+
+		// Act
+		firstPage, err := rssRepository.FindItemsPage(ctx, setUpRss, rss.ItemQuery{Ascending: true, Limit: 1})
+		require.NoError(t, err)
+		secondPage, err := rssRepository.FindItemsPage(ctx, setUpRss, rss.ItemQuery{Ascending: true, Limit: 1, Cursor: firstPage.NextCursor})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Len(t, firstPage.Items, 1)
+		assert.Equal(t, "guid-12345", firstPage.Items[0].Guid.Value)
+		assert.NotEmpty(t, firstPage.NextCursor)
+
+		assert.Len(t, secondPage.Items, 1)
+		assert.Equal(t, "guid-67890", secondPage.Items[0].Guid.Value)
+	})
+
+	t.Run("should return newest items first by default", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupExpectedRss(t, ctx, rssRepository)
+
+		// Act
+		page, err := rssRepository.FindItemsPage(ctx, setUpRss, rss.ItemQuery{Limit: 10})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+		assert.Equal(t, "guid-67890", page.Items[0].Guid.Value)
+		assert.Equal(t, "guid-12345", page.Items[1].Guid.Value)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("should filter items by PubDate range", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupExpectedRss(t, ctx, rssRepository)
+
+		// Act
+		page, err := rssRepository.FindItemsPage(ctx, setUpRss, rss.ItemQuery{
+			Since: time.Date(2023, time.June, 2, 0, 0, 0, 0, time.UTC),
+			Limit: 10,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		assert.Equal(t, "guid-67890", page.Items[0].Guid.Value)
+	})
+
+	t.Run("should return ErrInvalidCursor when cursor is broken", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupExpectedRss(t, ctx, rssRepository)
+
+		// Act
+		_, err := rssRepository.FindItemsPage(ctx, setUpRss, rss.ItemQuery{Limit: 10, Cursor: "broken"})
+
+		// Assert
+		assert.ErrorIs(t, err, rss.ErrInvalidCursor)
+	})
+}
+
+func TestRssRepository_Pages(t *testing.T) {
+	t.Run("should read and delete items beyond a single 1MB query page", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupLargeRss(t, ctx, rssRepository, 30)
+
+		// Act
+		foundRss, err := rssRepository.FindItems(ctx, setUpRss)
+		require.NoError(t, err)
+
+		pageCount := 0
+		pagedItemCount := 0
+		err = rssRepository.FindItemsPages(ctx, setUpRss, func(items []rss.Item) bool {
+			pageCount++
+			pagedItemCount += len(items)
+			return true
+		})
+		require.NoError(t, err)
+
+		err = rssRepository.Delete(ctx, setUpRss)
+
+		// Assert
+		assert.Len(t, foundRss.Items, 30)
+		assert.Greater(t, pageCount, 1)
+		assert.Equal(t, 30, pagedItemCount)
+
+		assert.NoError(t, err)
+		items, err := helper.GetItems(ctx, client, "Rss")
+		assert.NoError(t, err)
+		assert.Empty(t, items)
+	})
+
+	t.Run("should stop iterating when callback returns false", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupLargeRss(t, ctx, rssRepository, 30)
+
+		// Act
+		pageCount := 0
+		err := rssRepository.FindItemsPages(ctx, setUpRss, func(items []rss.Item) bool {
+			pageCount++
+			return false
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, pageCount)
+	})
+
+	t.Run("should return every rss through FindAllPages", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setupExpectedRss(t, ctx, rssRepository)
+
+		// Act
+		var rssFeeds []rss.Rss
+		err := rssRepository.FindAllPages(ctx, func(page []rss.Rss) bool {
+			rssFeeds = append(rssFeeds, page...)
+			return true
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, rssFeeds, 1)
+		assert.Equal(t, "Test_Source", rssFeeds[0].Source)
+	})
+}
+
+func setupLargeRss(t *testing.T, ctx context.Context, rssSaver rssSaver, itemCount int) rss.Rss {
+	var test_rss rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		test_rss, err = rss.New("Test Title", "Test_Source", "http://example.com", "Test Description", "en", time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+
+		// 40KB per item, so that the items of the feed do not fit in a single 1MB page.
+		description := strings.Repeat("a", 40*1024)
+		for i := 0; i < itemCount; i++ {
+			rssItem, err := rss.NewItem(rss.Guid{Value: fmt.Sprintf("guid-%d", i)}, fmt.Sprintf("Test Title %d", i), fmt.Sprintf("http://example.com/%d", i), description, "Test Author", time.Date(2023, time.June, 1, 13, 30, i, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			test_rss.AddOrUpdateItem(rssItem)
+		}
+
+		test_rss, err = rssSaver.Save(ctx, test_rss, metadata.UserMeta{ID: "test_user", Name: "Test User"})
+		return err
+	})
+
+	return test_rss
+}
+
+func TestRssRepository_ReserveRefresh(t *testing.T) {
+	t.Run("should reserve only once within the interval", func(t *testing.T) {
+		// Arrange
+		ctx, client := setUp()
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+		setUpRss := setupExpectedRss(t, ctx, rssRepository)
+		now := time.Date(2024, time.July, 10, 9, 0, 0, 0, time.UTC)
+
+		// Act
+		first, firstErr := rssRepository.ReserveRefresh(ctx, setUpRss, now, 5*time.Minute)
+		second, secondErr := rssRepository.ReserveRefresh(ctx, setUpRss, now.Add(4*time.Minute), 5*time.Minute)
+		third, thirdErr := rssRepository.ReserveRefresh(ctx, setUpRss, now.Add(5*time.Minute), 5*time.Minute)
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.NoError(t, thirdErr)
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.True(t, third)
+	})
+}
+
 func TestRssRepository_Delete(t *testing.T) {
 	t.Run("should return error if rss ID is invalid", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		invalidRss := rss.Rss{ID: uuid.Nil, Source: "Test_Source"}
 
 		// Act
@@ -568,7 +884,7 @@ func TestRssRepository_Delete(t *testing.T) {
 	t.Run("should return error if Source is empty", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		invalidRss := rss.Rss{ID: uuid.New(), Source: ""}
 
 		// Act
@@ -581,7 +897,7 @@ func TestRssRepository_Delete(t *testing.T) {
 	t.Run("should delete rss successfully", func(t *testing.T) {
 		// Arrange
 		ctx, client := setUp()
-		rssRepository := rss.NewDynamoDBRssRepository(client)
+		rssRepository := rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
 		setupRss := setupExpectedRss(t, ctx, rssRepository) // 正常なRSSをセットアップ
 
 		// Act
@@ -602,6 +918,13 @@ func TestRssRepository_Delete(t *testing.T) {
 	})
 }
 
+func TestRssRepository_Contract(t *testing.T) {
+	helper.RunRssRepositoryContract(t, func(t *testing.T) rss.IRssRepository {
+		_, client := setUp()
+		return rss.NewDynamoDBRssRepository(client, &helper.MockLogger{})
+	})
+}
+
 func setUp() (ctx context.Context, client *dynamodb.Client) {
 	ctx = context.Background()
 	cfg, err := config.LoadDefaultConfig(ctx, func(o *config.LoadOptions) error {
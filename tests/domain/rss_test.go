@@ -450,6 +450,99 @@ func TestRss_AddOrUpdateItem(t *testing.T) {
 		// Assert
 		assert.Len(t, test_rss.Items, 0)
 	})
+
+	t.Run("should add only the items matching the filter expression", func(t *testing.T) {
+		// Arrange
+		var test_rss rss.Rss
+		var matching, other rss.Item
+		helper.MustSucceed(t, func() error {
+			var err error
+			test_rss, err = rss.New("Test Title", "Test Source", "http://example.com", "Test Description", "en", time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			if err := test_rss.SetItemFilterExpression("(title~=go OR author:gopher) AND pubdate>=2024-01-01"); err != nil {
+				return err
+			}
+			matching, err = rss.NewItem(rss.Guid{Value: "guid-1"}, "Go generics", "http://example.com/1", "Test description", "Test Author", time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			other, err = rss.NewItem(rss.Guid{Value: "guid-2"}, "Go generics", "http://example.com/2", "Test description", "Test Author", time.Date(2023, time.June, 1, 13, 30, 0, 0, time.UTC))
+			return err
+		})
+
+		// Act
+		test_rss.AddOrUpdateItem(matching)
+		test_rss.AddOrUpdateItem(other)
+
+		// Assert
+		assert.Len(t, test_rss.Items, 1)
+		assert.Contains(t, test_rss.Items, rss.Guid{Value: "guid-1"})
+	})
+
+	t.Run("should keep the filter expression when the keywords are replaced", func(t *testing.T) {
+		// Arrange
+		var test_rss rss.Rss
+		helper.MustSucceed(t, func() error {
+			var err error
+			test_rss, err = rss.New("Test Title", "Test Source", "http://example.com", "Test Description", "en", time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			return test_rss.SetItemFilterExpression("tag=go")
+		})
+
+		// Act
+		test_rss.SetItemFilter([]string{"include_keyword"}, nil)
+
+		// Assert
+		assert.Equal(t, "tag=go", test_rss.ItemFilter.Expression)
+		assert.Equal(t, []string{"include_keyword"}, test_rss.ItemFilter.IncludeKeywords)
+	})
+}
+
+func TestRss_Status(t *testing.T) {
+	t.Run("should be active when created and toggled by Pause and Resume", func(t *testing.T) {
+		// Arrange
+		test_rss, err := rss.New("Test Title", "Test Source", "http://example.com", "Test Description", "en", time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC))
+		assert.NoError(t, err)
+
+		// Act
+		initial := test_rss.Status
+		test_rss.Pause()
+		paused := test_rss.IsPaused()
+		test_rss.Resume()
+
+		// Assert
+		assert.Equal(t, rss.StatusActive, initial)
+		assert.True(t, paused)
+		assert.Equal(t, rss.StatusActive, test_rss.Status)
+		assert.False(t, test_rss.IsPaused())
+	})
+}
+
+func TestRss_Trash(t *testing.T) {
+	t.Run("should be purged only after the retention and restorable before then", func(t *testing.T) {
+		// Arrange
+		test_rss, err := rss.New("Test Title", "Test Source", "http://example.com", "Test Description", "en", time.Date(2024, time.June, 1, 13, 30, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		trashedAt := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		test_rss.Trash(trashedAt, 24*time.Hour)
+		trashed := test_rss.IsTrashed()
+		purgeBefore := test_rss.ShouldPurge(trashedAt.Add(23 * time.Hour))
+		purgeAfter := test_rss.ShouldPurge(trashedAt.Add(24 * time.Hour))
+		test_rss.Restore()
+
+		// Assert
+		assert.True(t, trashed)
+		assert.False(t, purgeBefore)
+		assert.True(t, purgeAfter)
+		assert.False(t, test_rss.IsTrashed())
+		assert.False(t, test_rss.ShouldPurge(trashedAt.Add(48*time.Hour)))
+	})
 }
 
 func TestRss_Serialize(t *testing.T) {
@@ -502,13 +595,16 @@ func TestRss_Serialize(t *testing.T) {
 					"description":"Original description",
 					"author":"Original Author",
 					"pubDate":"2023-01-01T13:30:00Z",
-					"tags":["tag1","tag2"]
+					"tags":["tag1","tag2"],
+					"contentHash":"` + original_item.ContentHash + `"
 				}
 			},
 			"item_filter":{
 				"include_keywords":["go","golang"],
 				"exclude_keywords":["python","ruby"]
 			},
+			"retention":{},
+			"status":"active",
 			"create_by":{"id":"","name":""},
 			"create_at":"0001-01-01T00:00:00Z",
 			"update_by":{"id":"","name":""},
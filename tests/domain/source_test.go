@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSource_NormalizeFeedURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		feedURL  string
+		expected string
+	}{
+		{name: "lower-cases the host", feedURL: "https://Dev.TO/feed", expected: "https://dev.to/feed"},
+		{name: "ignores the scheme", feedURL: "http://dev.to/feed", expected: "https://dev.to/feed"},
+		{name: "drops default ports", feedURL: "https://dev.to:443/feed", expected: "https://dev.to/feed"},
+		{name: "keeps custom ports", feedURL: "http://127.0.0.1:8080/feed", expected: "https://127.0.0.1:8080/feed"},
+		{name: "drops trailing slashes", feedURL: "https://dev.to/feed/", expected: "https://dev.to/feed"},
+		{name: "drops fragments", feedURL: "https://dev.to/feed#top", expected: "https://dev.to/feed"},
+		{name: "sorts query parameters", feedURL: "https://dev.to/feed?b=2&a=1", expected: "https://dev.to/feed?a=1&b=2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			actual, err := rss.NormalizeFeedURL(tc.feedURL)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("should return error for invalid URLs", func(t *testing.T) {
+		for _, feedURL := range []string{"", "ftp://dev.to/feed", "https:///feed", "dev.to/feed"} {
+			_, err := rss.NormalizeFeedURL(feedURL)
+			assert.Error(t, err, feedURL)
+		}
+	})
+}
+
+func TestSource_NewSource(t *testing.T) {
+	t.Run("should keep the host name as prefix", func(t *testing.T) {
+		// Act
+		source, err := rss.NewSource("https://dev.to/feed")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(source, "dev.to-"), source)
+		assert.Len(t, source, len("dev.to-")+12)
+	})
+
+	t.Run("should return different sources for feeds on the same host", func(t *testing.T) {
+		// Act
+		feedSource, err1 := rss.NewSource("https://dev.to/feed")
+		tagSource, err2 := rss.NewSource("https://dev.to/feed/tag/go")
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NotEqual(t, feedSource, tagSource)
+	})
+
+	t.Run("should return the same source for equivalent URLs", func(t *testing.T) {
+		// Act
+		source1, err1 := rss.NewSource("https://dev.to/feed/")
+		source2, err2 := rss.NewSource("HTTP://DEV.TO:80/feed")
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, source1, source2)
+	})
+}
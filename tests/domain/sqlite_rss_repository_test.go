@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure/sqlite"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteRssRepository(t *testing.T, path string) *rss.SQLiteRssRepository {
+	db, err := sqlite.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repository, err := rss.NewSQLiteRssRepository(context.Background(), db)
+	require.NoError(t, err)
+	return repository
+}
+
+func TestSQLiteRssRepository_Contract(t *testing.T) {
+	helper.RunRssRepositoryContract(t, func(t *testing.T) rss.IRssRepository {
+		return newSQLiteRssRepository(t, filepath.Join(t.TempDir(), "workday.db"))
+	})
+}
+
+func TestSQLiteRssRepository_Migrate(t *testing.T) {
+	t.Run("should keep the stored feeds when the database is reopened", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "workday.db")
+		first := newSQLiteRssRepository(t, path)
+		feed := getTestRss(t)
+		_, err := first.Save(ctx, feed, helper.ContractUser)
+		require.NoError(t, err)
+
+		// Act
+		second := newSQLiteRssRepository(t, path)
+		found, err := second.FindItems(ctx, feed)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, found.Items, 2)
+	})
+}
@@ -0,0 +1,18 @@
+package helper
+
+import (
+	"encoding/json"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+)
+
+// PayloadOf returns the payload of a message the publishers wrapped in a
+// message.Envelope, so that tests can compare what was sent without the envelope's
+// generated IDs and timestamps. A message without an envelope is returned as is.
+func PayloadOf(published string) string {
+	var envelope message.Envelope
+	if err := json.Unmarshal([]byte(published), &envelope); err != nil || envelope.Payload == nil {
+		return published
+	}
+	return string(envelope.Payload)
+}
@@ -0,0 +1,20 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+)
+
+// NewRss returns a feed without items for the given source and link.
+func NewRss(t *testing.T, source string, link string) rss.Rss {
+	t.Helper()
+	var testRss rss.Rss
+	MustSucceed(t, func() error {
+		var err error
+		testRss, err = rss.New("ダミーニュースのフィード", source, link, "このフィードはダミーニュースを提供します。", "ja", time.Date(2024, time.July, 3, 13, 0, 0, 0, time.UTC))
+		return err
+	})
+	return testRss
+}
@@ -0,0 +1,451 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ContractUser is the user the contract saves feeds as.
+var ContractUser = metadata.UserMeta{ID: "contract-id", Name: "contract-user"}
+
+// RunRssRepositoryContract runs the behaviour every rss.IRssRepository must share
+// against the repositories returned by newRepository, which must be empty.
+func RunRssRepositoryContract(t *testing.T, newRepository func(t *testing.T) rss.IRssRepository) {
+	ctx := context.Background()
+	user := ContractUser
+
+	t.Run("should return an error for an empty source", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+
+		// Act
+		_, err := repository.FindBySource(ctx, "")
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("should return an empty Rss for an unknown source", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+
+		// Act
+		found, err := repository.FindBySource(ctx, "unknown.example.com")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, found.ID)
+		assert.Empty(t, found.Items)
+	})
+
+	t.Run("should stamp metadata on Save and find the rss by source without items", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 3)
+		feed.SetItemFilter([]string{"include"}, []string{"exclude"})
+		require.NoError(t, feed.SetItemFilterExpression("title~go AND NOT tag=draft"))
+		feed.SetRetention(rss.NewRetention(30, 100))
+
+		// Act
+		saved, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+		found, findErr := repository.FindBySource(ctx, feed.Source)
+
+		// Assert
+		assert.NoError(t, findErr)
+		assert.Equal(t, metadata.CreateBy(user), saved.CreatedBy)
+		assert.Equal(t, metadata.UpdateBy(user), saved.UpdatedBy)
+		assert.False(t, saved.CreatedAt.IsZero())
+		assert.Equal(t, 1, saved.Version)
+
+		assert.Equal(t, feed.ID, found.ID)
+		assert.Equal(t, feed.Source, found.Source)
+		assert.Equal(t, feed.Title, found.Title)
+		assert.Equal(t, feed.Link, found.Link)
+		assert.Equal(t, feed.Language, found.Language)
+		assert.True(t, feed.LastBuildDate.Equal(found.LastBuildDate))
+		assert.True(t, found.ItemFilter.Equal(feed.ItemFilter))
+		assert.Equal(t, feed.Retention, found.Retention)
+		assert.Equal(t, rss.StatusActive, found.Status)
+		assert.Equal(t, metadata.CreateBy(user), found.CreatedBy)
+		assert.Equal(t, saved.CreatedAt.Unix(), found.CreatedAt.Unix())
+		assert.Equal(t, 1, found.Version)
+		assert.Empty(t, found.Items)
+	})
+
+	t.Run("should keep the creator and bump the version on later saves", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		saved, err := repository.Save(ctx, newContractRss(t, "contract.example.com", 0), user)
+		require.NoError(t, err)
+		updater := metadata.UserMeta{ID: "updater-id", Name: "updater"}
+
+		// Act
+		updated, err := repository.Save(ctx, saved, updater)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, metadata.CreateBy(user), updated.CreatedBy)
+		assert.Equal(t, metadata.UpdateBy(updater), updated.UpdatedBy)
+		assert.Equal(t, 2, updated.Version)
+	})
+
+	t.Run("should keep the parts of a fetch written so far", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		entry := newContractRss(t, "contract.example.com", 0)
+		entry.Parts = &rss.FetchParts{FetchID: "fetch-id", Parts: 3, Written: []int{1, 3}, NewFeed: true}
+		_, err := repository.Save(ctx, entry, user)
+		require.NoError(t, err)
+
+		// Act
+		found, err := repository.FindBySource(ctx, "contract.example.com")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, entry.Parts, found.Parts)
+	})
+
+	t.Run("should return ConflictError when saving a stale rss", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		saved, err := repository.Save(ctx, newContractRss(t, "contract.example.com", 0), user)
+		require.NoError(t, err)
+		_, err = repository.Save(ctx, saved, user)
+		require.NoError(t, err)
+
+		// Act
+		_, err = repository.Save(ctx, saved, user)
+
+		// Assert
+		var conflict *rss.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Equal(t, saved.Version, conflict.Version)
+	})
+
+	t.Run("should return ConflictError when saving a read rss that no longer exists", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		gone := newContractRss(t, "contract.example.com", 0)
+		gone.Version = 1
+
+		// Act
+		_, err := repository.Save(ctx, gone, user)
+
+		// Assert
+		var conflict *rss.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+		found, err := repository.FindBySource(ctx, "contract.example.com")
+		assert.NoError(t, err)
+		assert.Empty(t, found.ID)
+	})
+
+	t.Run("should write no items on a stale save", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		saved, err := repository.Save(ctx, newContractRss(t, "contract.example.com", 1), user)
+		require.NoError(t, err)
+		_, err = repository.Save(ctx, saved, user)
+		require.NoError(t, err)
+
+		stale := newContractRss(t, "contract.example.com", 3)
+		stale.ID = saved.ID
+		stale.Version = saved.Version
+
+		// Act
+		_, err = repository.Save(ctx, stale, user)
+
+		// Assert
+		var conflict *rss.ConflictError
+		assert.ErrorAs(t, err, &conflict)
+		found, err := repository.FindItems(ctx, saved)
+		assert.NoError(t, err)
+		assert.Len(t, found.Items, 1)
+	})
+
+	t.Run("should let only one of concurrent saves of the same version win", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		saved, err := repository.Save(ctx, newContractRss(t, "contract.example.com", 0), user)
+		require.NoError(t, err)
+
+		// Act
+		var wg sync.WaitGroup
+		errs := make([]error, 10)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = repository.Save(ctx, saved, user)
+			}(i)
+		}
+		wg.Wait()
+
+		// Assert
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			var conflict *rss.ConflictError
+			assert.ErrorAs(t, err, &conflict)
+		}
+		assert.Equal(t, 1, succeeded)
+	})
+
+	t.Run("should find the items of an rss only", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 3)
+		other := newContractRss(t, "other.example.com", 2)
+		_, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+		_, err = repository.Save(ctx, other, user)
+		require.NoError(t, err)
+
+		// Act
+		withItems, err := repository.FindItems(ctx, feed)
+		require.NoError(t, err)
+
+		pagedCount := 0
+		err = repository.FindItemsPages(ctx, feed, func(items []rss.Item) bool {
+			pagedCount += len(items)
+			return true
+		})
+		require.NoError(t, err)
+
+		byPk, err := repository.FindItemsByPk(ctx, feed, rss.Guid{Value: "guid-1"})
+		require.NoError(t, err)
+		missing, err := repository.FindItemsByPk(ctx, feed, rss.Guid{Value: "missing"})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Len(t, withItems.Items, 3)
+		assert.Equal(t, 3, pagedCount)
+		item := withItems.Items[rss.Guid{Value: "guid-1"}]
+		assert.Equal(t, "Title 1", item.Title)
+		assert.Equal(t, []string{"tag-odd"}, item.Tags)
+		assert.Equal(t, time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC), item.PubDate)
+
+		assert.Len(t, byPk.Items, 1)
+		assert.Contains(t, byPk.Items, rss.Guid{Value: "guid-1"})
+		assert.Empty(t, missing.Items)
+	})
+
+	t.Run("should page through items in PubDate order", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 5)
+		_, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+
+		// Act
+		var guids []string
+		pages := 0
+		query := rss.ItemQuery{Ascending: true, Limit: 2}
+		for {
+			page, err := repository.FindItemsPage(ctx, feed, query)
+			require.NoError(t, err)
+			pages++
+			for _, item := range page.Items {
+				guids = append(guids, item.Guid.Value)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+
+		// Assert
+		assert.Equal(t, []string{"guid-0", "guid-1", "guid-2", "guid-3", "guid-4"}, guids)
+		assert.Equal(t, 3, pages)
+	})
+
+	t.Run("should filter items by PubDate range and tags, newest first", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 5)
+		_, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+
+		// Act
+		page, err := repository.FindItemsPage(ctx, feed, rss.ItemQuery{
+			Since: time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC),
+			Until: time.Date(2024, time.June, 5, 0, 0, 0, 0, time.UTC),
+			Tags:  []string{"tag-odd"},
+			Limit: 10,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		var guids []string
+		for _, item := range page.Items {
+			guids = append(guids, item.Guid.Value)
+		}
+		assert.Equal(t, []string{"guid-3", "guid-1"}, guids)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("should reject a broken cursor", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 1)
+		_, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+
+		// Act
+		_, err = repository.FindItemsPage(ctx, feed, rss.ItemQuery{Limit: 1, Cursor: "broken"})
+
+		// Assert
+		assert.ErrorIs(t, err, rss.ErrInvalidCursor)
+	})
+
+	t.Run("should find every rss with its fetch status", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 1)
+		other := newContractRss(t, "other.example.com", 1)
+		_, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+		_, err = repository.Save(ctx, other, user)
+		require.NoError(t, err)
+		failedAt := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+		err = repository.SaveFetchStatus(ctx, feed, rss.FetchStatus{LastFailureAt: failedAt, LastError: "timeout", ConsecutiveFailures: 2, HTTPStatus: 504})
+		require.NoError(t, err)
+
+		// Act
+		feeds, err := repository.FindAll(ctx)
+		require.NoError(t, err)
+		found, findErr := repository.FindBySource(ctx, feed.Source)
+
+		// Assert
+		assert.NoError(t, findErr)
+		assert.Len(t, feeds, 2)
+		statuses := map[string]rss.FetchStatus{}
+		for _, f := range feeds {
+			assert.Empty(t, f.Items)
+			statuses[f.Source] = f.FetchStatus
+		}
+		assert.Equal(t, 2, statuses[feed.Source].ConsecutiveFailures)
+		assert.Equal(t, rss.FetchStatus{}, statuses[other.Source])
+		assert.Equal(t, "timeout", found.FetchStatus.LastError)
+		assert.Equal(t, 504, found.FetchStatus.HTTPStatus)
+		assert.True(t, failedAt.Equal(found.FetchStatus.LastFailureAt))
+	})
+
+	t.Run("should reserve a refresh only once within the interval", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 0)
+		now := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		first, err := repository.ReserveRefresh(ctx, feed, now, 5*time.Minute)
+		require.NoError(t, err)
+		second, err := repository.ReserveRefresh(ctx, feed, now.Add(time.Minute), 5*time.Minute)
+		require.NoError(t, err)
+		third, err := repository.ReserveRefresh(ctx, feed, now.Add(5*time.Minute), 5*time.Minute)
+		require.NoError(t, err)
+
+		// Assert
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.True(t, third)
+	})
+
+	t.Run("should delete only the given items", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 3)
+		saved, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+
+		// Act
+		err = repository.DeleteItems(ctx, saved, []rss.Guid{{Value: "guid-0"}, {Value: "guid-2"}})
+		require.NoError(t, err)
+		withItems, findErr := repository.FindItems(ctx, saved)
+		found, _ := repository.FindBySource(ctx, feed.Source)
+
+		// Assert
+		assert.NoError(t, findErr)
+		assert.Len(t, withItems.Items, 1)
+		assert.Contains(t, withItems.Items, rss.Guid{Value: "guid-1"})
+		assert.Equal(t, feed.ID, found.ID)
+	})
+
+	t.Run("should delete the rss with its items, fetch status and refresh", func(t *testing.T) {
+		// Arrange
+		repository := newRepository(t)
+		feed := newContractRss(t, "contract.example.com", 3)
+		other := newContractRss(t, "other.example.com", 2)
+		saved, err := repository.Save(ctx, feed, user)
+		require.NoError(t, err)
+		_, err = repository.Save(ctx, other, user)
+		require.NoError(t, err)
+		require.NoError(t, repository.SaveFetchStatus(ctx, feed, rss.FetchStatus{ConsecutiveFailures: 1}))
+		now := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+		_, err = repository.ReserveRefresh(ctx, feed, now, time.Hour)
+		require.NoError(t, err)
+
+		// Act
+		err = repository.Delete(ctx, saved)
+		require.NoError(t, err)
+
+		// Assert
+		found, err := repository.FindBySource(ctx, feed.Source)
+		assert.NoError(t, err)
+		assert.Empty(t, found.ID)
+
+		withItems, err := repository.FindItems(ctx, saved)
+		assert.NoError(t, err)
+		assert.Empty(t, withItems.Items)
+
+		reserved, err := repository.ReserveRefresh(ctx, feed, now, time.Hour)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+
+		feeds, err := repository.FindAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, feeds, 1)
+		assert.Equal(t, other.Source, feeds[0].Source)
+
+		otherItems, err := repository.FindItems(ctx, other)
+		assert.NoError(t, err)
+		assert.Len(t, otherItems.Items, 2)
+	})
+}
+
+// newContractRss returns a feed with itemCount items published a day apart from
+// 2024-06-01. Odd items are tagged "tag-odd".
+func newContractRss(t *testing.T, source string, itemCount int) rss.Rss {
+	var feed rss.Rss
+	MustSucceed(t, func() error {
+		var err error
+		feed, err = rss.New("Contract Title", source, "http://"+source, "Contract Description", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < itemCount; i++ {
+			item, err := rss.NewItem(rss.Guid{Value: fmt.Sprintf("guid-%d", i)}, fmt.Sprintf("Title %d", i), fmt.Sprintf("http://%s/%d", source, i), fmt.Sprintf("Description %d", i), "Author", time.Date(2024, time.June, 1+i, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			if i%2 == 1 {
+				item.AddTag("tag-odd")
+			}
+			feed.AddOrUpdateItem(item)
+		}
+		return nil
+	})
+	return feed
+}
@@ -2,18 +2,25 @@ package helper
 
 import (
 	"context"
+	"time"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/metadata"
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
 )
 
 type SpyRssRepository struct {
-	FindBySourceFunc  func(ctx context.Context, source string) (rss.Rss, error)
-	FindAllFunc       func(ctx context.Context) ([]rss.Rss, error)
-	FindItemsFunc     func(ctx context.Context, rss rss.Rss) (rss.Rss, error)
-	FindItemsByPkFunc func(ctx context.Context, rss rss.Rss, guid rss.Guid) (rss.Rss, error)
-	SaveFunc          func(ctx context.Context, rss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error)
-	DeleteFunc        func(ctx context.Context, rss rss.Rss) error
+	FindBySourceFunc    func(ctx context.Context, source string) (rss.Rss, error)
+	FindAllFunc         func(ctx context.Context) ([]rss.Rss, error)
+	FindAllPagesFunc    func(ctx context.Context, fn func(rssFeeds []rss.Rss) bool) error
+	FindItemsFunc       func(ctx context.Context, rss rss.Rss) (rss.Rss, error)
+	FindItemsPagesFunc  func(ctx context.Context, rss rss.Rss, fn func(items []rss.Item) bool) error
+	FindItemsByPkFunc   func(ctx context.Context, rss rss.Rss, guid rss.Guid) (rss.Rss, error)
+	FindItemsPageFunc   func(ctx context.Context, rss rss.Rss, query rss.ItemQuery) (rss.ItemPage, error)
+	SaveFunc            func(ctx context.Context, rss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error)
+	SaveFetchStatusFunc func(ctx context.Context, rss rss.Rss, status rss.FetchStatus) error
+	ReserveRefreshFunc  func(ctx context.Context, rss rss.Rss, now time.Time, minInterval time.Duration) (bool, error)
+	DeleteFunc          func(ctx context.Context, rss rss.Rss) error
+	DeleteItemsFunc     func(ctx context.Context, rss rss.Rss, guids []rss.Guid) error
 }
 
 func (r *SpyRssRepository) FindBySource(ctx context.Context, source string) (rss.Rss, error) {
@@ -30,6 +37,13 @@ func (r *SpyRssRepository) FindAll(ctx context.Context) ([]rss.Rss, error) {
 	panic("FindAllFunc is not implemented")
 }
 
+func (r *SpyRssRepository) FindAllPages(ctx context.Context, fn func(rssFeeds []rss.Rss) bool) error {
+	if r.FindAllPagesFunc != nil {
+		return r.FindAllPagesFunc(ctx, fn)
+	}
+	panic("FindAllPagesFunc is not implemented")
+}
+
 func (r *SpyRssRepository) FindItems(ctx context.Context, rss rss.Rss) (rss.Rss, error) {
 	if r.FindItemsFunc != nil {
 		return r.FindItemsFunc(ctx, rss)
@@ -37,6 +51,13 @@ func (r *SpyRssRepository) FindItems(ctx context.Context, rss rss.Rss) (rss.Rss,
 	panic("FindItemsFunc is not implemented")
 }
 
+func (r *SpyRssRepository) FindItemsPages(ctx context.Context, rss rss.Rss, fn func(items []rss.Item) bool) error {
+	if r.FindItemsPagesFunc != nil {
+		return r.FindItemsPagesFunc(ctx, rss, fn)
+	}
+	panic("FindItemsPagesFunc is not implemented")
+}
+
 func (r *SpyRssRepository) FindItemsByPk(ctx context.Context, rss rss.Rss, guid rss.Guid) (rss.Rss, error) {
 	if r.FindItemsByPkFunc != nil {
 		return r.FindItemsByPkFunc(ctx, rss, guid)
@@ -44,6 +65,13 @@ func (r *SpyRssRepository) FindItemsByPk(ctx context.Context, rss rss.Rss, guid
 	panic("FindItemsByPkFunc is not implemented")
 }
 
+func (r *SpyRssRepository) FindItemsPage(ctx context.Context, rss rss.Rss, query rss.ItemQuery) (rss.ItemPage, error) {
+	if r.FindItemsPageFunc != nil {
+		return r.FindItemsPageFunc(ctx, rss, query)
+	}
+	panic("FindItemsPageFunc is not implemented")
+}
+
 func (r *SpyRssRepository) Save(ctx context.Context, rss rss.Rss, updateBy metadata.UserMeta) (rss.Rss, error) {
 	if r.SaveFunc != nil {
 		return r.SaveFunc(ctx, rss, updateBy)
@@ -51,9 +79,30 @@ func (r *SpyRssRepository) Save(ctx context.Context, rss rss.Rss, updateBy metad
 	panic("SaveFunc is not implemented")
 }
 
+func (r *SpyRssRepository) SaveFetchStatus(ctx context.Context, rss rss.Rss, status rss.FetchStatus) error {
+	if r.SaveFetchStatusFunc != nil {
+		return r.SaveFetchStatusFunc(ctx, rss, status)
+	}
+	panic("SaveFetchStatusFunc is not implemented")
+}
+
+func (r *SpyRssRepository) ReserveRefresh(ctx context.Context, rss rss.Rss, now time.Time, minInterval time.Duration) (bool, error) {
+	if r.ReserveRefreshFunc != nil {
+		return r.ReserveRefreshFunc(ctx, rss, now, minInterval)
+	}
+	panic("ReserveRefreshFunc is not implemented")
+}
+
 func (r *SpyRssRepository) Delete(ctx context.Context, rss rss.Rss) error {
 	if r.DeleteFunc != nil {
 		return r.DeleteFunc(ctx, rss)
 	}
 	panic("DeleteFunc is not implemented")
 }
+
+func (r *SpyRssRepository) DeleteItems(ctx context.Context, rss rss.Rss, guids []rss.Guid) error {
+	if r.DeleteItemsFunc != nil {
+		return r.DeleteItemsFunc(ctx, rss, guids)
+	}
+	panic("DeleteItemsFunc is not implemented")
+}
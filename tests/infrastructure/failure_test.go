@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "unknown error", err: errors.New("invalid character 'x' looking for beginning of value"), expected: false},
+		{name: "deadline exceeded", err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), expected: true},
+		{name: "conflict", err: fmt.Errorf("save: %w", &rss.ConflictError{Source: "www.example.com", Version: 2}), expected: true},
+		{name: "permanent deadline exceeded", err: infrastructure.Permanent(context.DeadlineExceeded), expected: false},
+		{name: "retryable unknown error", err: fmt.Errorf("fetch: %w", infrastructure.Retryable(errors.New("503 Service Unavailable"))), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			retryable := infrastructure.IsRetryable(tt.err)
+
+			// Assert
+			assert.Equal(t, tt.expected, retryable)
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	t.Run("should keep the wrapped error reachable", func(t *testing.T) {
+		// Arrange
+		cause := errors.New("not found")
+
+		// Act
+		err := infrastructure.Permanent(cause)
+
+		// Assert
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, "not found", err.Error())
+	})
+
+	t.Run("should return nil for nil", func(t *testing.T) {
+		// Act
+		err := infrastructure.Permanent(nil)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
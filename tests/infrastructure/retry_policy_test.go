@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+var errThrottled = errors.New("throttled")
+
+func isThrottled(err error) bool {
+	return errors.Is(err, errThrottled)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Run("should double the backoff up to the max delay", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+		// Act
+		backoffs := []time.Duration{policy.Backoff(1), policy.Backoff(2), policy.Backoff(3), policy.Backoff(4), policy.Backoff(5), policy.Backoff(100)}
+
+		// Assert
+		assert.Equal(t, []time.Duration{
+			100 * time.Millisecond,
+			200 * time.Millisecond,
+			400 * time.Millisecond,
+			800 * time.Millisecond,
+			time.Second,
+			time.Second,
+		}, backoffs)
+	})
+
+	t.Run("should keep the jittered delay within the backoff", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+		for i := 0; i < 100; i++ {
+			// Act
+			delay := policy.Delay(3)
+
+			// Assert
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, 400*time.Millisecond)
+		}
+	})
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	t.Run("should retry retryable errors until the operation succeeds", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+		logger := helper.MockLogger{}
+		calls := 0
+
+		// Act
+		err := policy.Do(context.Background(), &logger, "PutItem", isThrottled, func() error {
+			calls++
+			if calls < 3 {
+				return errThrottled
+			}
+			return nil
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should return the last error after max attempts", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+		logger := helper.MockLogger{}
+		calls := 0
+
+		// Act
+		err := policy.Do(context.Background(), &logger, "PutItem", isThrottled, func() error {
+			calls++
+			return errThrottled
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errThrottled)
+		assert.ErrorIs(t, err, infrastructure.ErrRetriesExhausted)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should not retry errors that are not retryable", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+		logger := helper.MockLogger{}
+		calls := 0
+
+		// Act
+		err := policy.Do(context.Background(), &logger, "PutItem", isThrottled, func() error {
+			calls++
+			return errors.New("validation error")
+		})
+
+		// Assert
+		assert.EqualError(t, err, "validation error")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should stop retrying once the max elapsed time would be exceeded", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{MaxAttempts: 100, BaseDelay: time.Hour, MaxDelay: time.Hour, MaxElapsedTime: time.Millisecond}
+		logger := helper.MockLogger{}
+		calls := 0
+
+		// Act
+		err := policy.Do(context.Background(), &logger, "PutItem", isThrottled, func() error {
+			calls++
+			return errThrottled
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errThrottled)
+		assert.ErrorIs(t, err, infrastructure.ErrRetriesExhausted)
+		assert.LessOrEqual(t, calls, 2)
+	})
+
+	t.Run("should return the error of the context with the last error when it is done", func(t *testing.T) {
+		// Arrange
+		policy := infrastructure.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+		logger := helper.MockLogger{}
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+
+		// Act
+		err := policy.Do(ctx, &logger, "PutItem", isThrottled, func() error {
+			calls++
+			cancel()
+			return errThrottled
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errThrottled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, infrastructure.ErrRetriesExhausted)
+		assert.Equal(t, 1, calls)
+	})
+}
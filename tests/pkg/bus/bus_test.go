@@ -0,0 +1,147 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/infrastructure"
+	"github.com/YamazakiNorihito/workday/pkg/bus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBus() *bus.Bus {
+	return bus.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestBus_Publish(t *testing.T) {
+	t.Run("should deliver the message to every subscriber of the topic", func(t *testing.T) {
+		// Arrange
+		sut := newBus()
+		var mu sync.Mutex
+		var received []string
+		record := func(name string) bus.Handler {
+			return func(ctx context.Context, logger infrastructure.Logger, message string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				received = append(received, name+":"+message)
+				return nil
+			}
+		}
+		sut.Subscribe("topic", 1, record("first"))
+		sut.Subscribe("topic", 1, record("second"))
+		sut.Subscribe("other", 1, record("other"))
+
+		// Act
+		err := sut.Topic("topic").Publish(context.Background(), "hello")
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"first:hello", "second:hello"}, received)
+	})
+
+	t.Run("should wait for the messages published by subscribers", func(t *testing.T) {
+		// Arrange
+		sut := newBus()
+		var delivered atomic.Bool
+		sut.Subscribe("first", 1, func(ctx context.Context, logger infrastructure.Logger, message string) error {
+			return sut.Publish(ctx, "second", message)
+		})
+		sut.Subscribe("second", 1, func(ctx context.Context, logger infrastructure.Logger, message string) error {
+			time.Sleep(10 * time.Millisecond)
+			delivered.Store(true)
+			return nil
+		})
+
+		// Act
+		err := sut.Publish(context.Background(), "first", "hello")
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, delivered.Load())
+	})
+
+	t.Run("should not run more handlers at once than the concurrency", func(t *testing.T) {
+		// Arrange
+		sut := newBus()
+		var running, maxRunning atomic.Int32
+		sut.Subscribe("topic", 2, func(ctx context.Context, logger infrastructure.Logger, message string) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+
+		// Act
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, sut.Publish(context.Background(), "topic", "hello"))
+		}
+		sut.Wait()
+
+		// Assert
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+	})
+
+	t.Run("should keep delivering after a handler fails", func(t *testing.T) {
+		// Arrange
+		sut := newBus()
+		var calls atomic.Int32
+		sut.Subscribe("topic", 1, func(ctx context.Context, logger infrastructure.Logger, message string) error {
+			calls.Add(1)
+			return errors.New("failed")
+		})
+
+		// Act
+		assert.NoError(t, sut.Publish(context.Background(), "topic", "first"))
+		assert.NoError(t, sut.Publish(context.Background(), "topic", "second"))
+		sut.Wait()
+
+		// Assert
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should deliver with a context that outlives the publisher", func(t *testing.T) {
+		// Arrange
+		sut := newBus()
+		var ctxErr error
+		sut.Subscribe("topic", 1, func(ctx context.Context, logger infrastructure.Logger, message string) error {
+			ctxErr = ctx.Err()
+			return nil
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// Act
+		err := sut.Publish(ctx, "topic", "hello")
+		sut.Wait()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, ctxErr)
+	})
+
+	t.Run("should refuse messages once closed", func(t *testing.T) {
+		// Arrange
+		sut := newBus()
+		sut.Close()
+
+		// Act
+		err := sut.Publish(context.Background(), "topic", "hello")
+
+		// Assert
+		assert.ErrorIs(t, err, bus.ErrClosed)
+	})
+}
@@ -0,0 +1,50 @@
+package claimcheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	t.Run("should return what was put under the key", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		sut := claimcheck.NewFileStore(t.TempDir())
+
+		// Act
+		putErr := sut.Put(ctx, "write/claim-1.json", []byte(`{"source":"www.example.com"}`))
+		data, getErr := sut.Get(ctx, "write/claim-1.json")
+
+		// Assert
+		assert.NoError(t, putErr)
+		assert.NoError(t, getErr)
+		assert.Equal(t, `{"source":"www.example.com"}`, string(data))
+	})
+
+	t.Run("should return ErrNotFound for a key that was never put", func(t *testing.T) {
+		// Arrange
+		sut := claimcheck.NewFileStore(t.TempDir())
+
+		// Act
+		_, err := sut.Get(context.Background(), "write/unknown.json")
+
+		// Assert
+		assert.ErrorIs(t, err, claimcheck.ErrNotFound)
+	})
+
+	t.Run("should reject keys outside the directory", func(t *testing.T) {
+		// Arrange
+		sut := claimcheck.NewFileStore(t.TempDir())
+
+		for _, key := range []string{"", "../escape.json", "/etc/passwd"} {
+			// Act
+			err := sut.Put(context.Background(), key, []byte("data"))
+
+			// Assert
+			assert.Error(t, err, key)
+		}
+	})
+}
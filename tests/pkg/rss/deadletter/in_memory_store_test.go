@@ -0,0 +1,47 @@
+package deadletter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/deadletter"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	t.Run("should return the saved dead letters oldest first", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		sut := deadletter.NewInMemoryStore()
+		newer := message.DeadLetter{Stage: message.StageWrite, MessageID: "message-2", Message: "{}", Reason: "reason", FailedAt: time.Date(2024, time.July, 2, 0, 0, 0, 0, time.UTC)}
+		older := message.DeadLetter{Stage: message.StageClean, MessageID: "message-1", Message: "{}", Reason: "reason", FailedAt: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)}
+		helper.MustSucceed(t, func() error { return sut.Save(ctx, newer) })
+		helper.MustSucceed(t, func() error { return sut.Save(ctx, older) })
+
+		// Act
+		deadLetters, err := sut.FindAll(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []message.DeadLetter{older, newer}, deadLetters)
+	})
+
+	t.Run("should return ErrNotFound once the dead letter is deleted", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		sut := deadletter.NewInMemoryStore()
+		helper.MustSucceed(t, func() error {
+			return sut.Save(ctx, message.DeadLetter{Stage: message.StageWrite, MessageID: "message-1", FailedAt: time.Now().UTC()})
+		})
+		helper.MustSucceed(t, func() error { return sut.Delete(ctx, "message-1") })
+
+		// Act
+		_, err := sut.Find(ctx, "message-1")
+
+		// Assert
+		assert.ErrorIs(t, err, deadletter.ErrNotFound)
+	})
+}
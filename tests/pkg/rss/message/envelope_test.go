@@ -0,0 +1,88 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	t.Run("should carry the trace of the context", func(t *testing.T) {
+		// Arrange
+		ctx := message.ContextWithTrace(context.Background(), message.Trace{CorrelationID: "correlation-1", Origin: message.StageSubscribe})
+
+		// Act
+		envelope, err := message.NewEnvelope(ctx, message.TypeWrite, message.Write{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, message.TypeWrite, envelope.Type)
+		assert.Equal(t, message.EnvelopeVersion, envelope.Version)
+		assert.NotEmpty(t, envelope.MessageID)
+		assert.Equal(t, "correlation-1", envelope.CorrelationID)
+		assert.Equal(t, message.StageSubscribe, envelope.Origin)
+		assert.False(t, envelope.CreatedAt.IsZero())
+	})
+
+	t.Run("should start a new correlation ID without a trace", func(t *testing.T) {
+		// Act
+		envelope, err := message.NewEnvelope(context.Background(), message.TypeDelete, message.Delete{Source: "www.example.com"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, envelope.MessageID, envelope.CorrelationID)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("should decode the payload of an envelope", func(t *testing.T) {
+		// Arrange
+		envelope, _ := message.NewEnvelope(context.Background(), message.TypeDelete, message.Delete{Source: "www.example.com"})
+		envelopeJson, _ := json.Marshal(envelope)
+
+		// Act
+		var deleteMessage message.Delete
+		decoded, err := message.Decode(string(envelopeJson), message.TypeDelete, &deleteMessage)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "www.example.com", deleteMessage.Source)
+		assert.Equal(t, envelope.CorrelationID, decoded.CorrelationID)
+	})
+
+	t.Run("should decode a legacy message without an envelope", func(t *testing.T) {
+		// Act
+		var subscribeMessage message.Subscribe
+		decoded, err := message.Decode(`{"source":"www.example.com","feed_url":"http://www.example.com/feed","language":"ja"}`, message.TypeSubscribe, &subscribeMessage)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, decoded.Version)
+		assert.Equal(t, "http://www.example.com/feed", subscribeMessage.FeedURL)
+	})
+
+	t.Run("should reject a message of another type", func(t *testing.T) {
+		// Arrange
+		envelope, _ := message.NewEnvelope(context.Background(), message.TypeDelete, message.Delete{Source: "www.example.com"})
+		envelopeJson, _ := json.Marshal(envelope)
+
+		// Act
+		var writeMessage message.Write
+		_, err := message.Decode(string(envelopeJson), message.TypeWrite, &writeMessage)
+
+		// Assert
+		assert.ErrorContains(t, err, "unexpected message type")
+	})
+
+	t.Run("should reject a newer envelope version", func(t *testing.T) {
+		// Act
+		var deleteMessage message.Delete
+		_, err := message.Decode(`{"type":"delete","version":2,"payload":{"source":"www.example.com"}}`, message.TypeDelete, &deleteMessage)
+
+		// Assert
+		assert.ErrorContains(t, err, "unsupported message version 2")
+	})
+}
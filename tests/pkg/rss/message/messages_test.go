@@ -0,0 +1,250 @@
+package message
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/claimcheck"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRss returns a feed with itemCount items whose descriptions are random, so
+// the feed does not shrink much when compressed.
+func newRss(t *testing.T, itemCount int) rss.Rss {
+	var feed rss.Rss
+	helper.MustSucceed(t, func() error {
+		var err error
+		feed, err = rss.New("ダミーニュースのフィード", "www.example.com", "http://www.example.com/feed", "", "ja", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < itemCount; i++ {
+			noise := make([]byte, 2048)
+			if _, err := rand.Read(noise); err != nil {
+				return err
+			}
+			item, err := rss.NewItem(rss.Guid{Value: fmt.Sprintf("http://www.example.com/dummy-guid%d", i)}, fmt.Sprintf("ダミー記事%d", i), fmt.Sprintf("http://www.example.com/dummy-article%d", i), base64.StdEncoding.EncodeToString(noise), "item@dummy.com", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				return err
+			}
+			feed.AddOrUpdateItem(item)
+		}
+		return nil
+	})
+	return feed
+}
+
+func TestNewWriteMessageWithClaimCheck(t *testing.T) {
+	t.Run("should send a small feed inline", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 1)
+		store := claimcheck.NewFileStore(t.TempDir())
+
+		// Act
+		writeMessage, err := message.NewWriteMessageWithClaimCheck(context.Background(), feed, store)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, writeMessage.ClaimCheck)
+		assert.False(t, writeMessage.Compressed)
+		assert.Equal(t, feed.Source, writeMessage.RssFeed.Source)
+	})
+
+	t.Run("should send a feed too large for SNS as a claim check", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		feed := newRss(t, 300)
+		store := claimcheck.NewFileStore(t.TempDir())
+
+		// Act
+		writeMessage, err := message.NewWriteMessageWithClaimCheck(ctx, feed, store)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, writeMessage.ClaimCheck)
+		writeMessageJson, err := json.Marshal(writeMessage)
+		assert.NoError(t, err)
+		assert.Less(t, len(writeMessageJson), message.MaxMessageSize)
+
+		resolved, err := message.ResolveWrite(ctx, writeMessage, store)
+		assert.NoError(t, err)
+		assert.Equal(t, feed.Source, resolved.RssFeed.Source)
+		assert.Len(t, resolved.RssFeed.Items, 300)
+	})
+
+	t.Run("should compress the feed without a store", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 300)
+
+		// Act
+		writeMessage, err := message.NewWriteMessageWithClaimCheck(context.Background(), feed, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, writeMessage.ClaimCheck)
+		assert.True(t, writeMessage.Compressed)
+	})
+}
+
+func TestResolveWrite(t *testing.T) {
+	t.Run("should return an inline feed as it is", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 1)
+
+		// Act
+		resolved, err := message.ResolveWrite(context.Background(), message.Write{RssFeed: feed}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, feed.Source, resolved.RssFeed.Source)
+		assert.Len(t, resolved.RssFeed.Items, 1)
+	})
+
+	t.Run("should decompress a compressed feed", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 300)
+		writeMessage, err := message.NewWriteMessage(feed)
+		assert.NoError(t, err)
+		assert.True(t, writeMessage.Compressed)
+
+		// Act
+		resolved, err := message.ResolveWrite(context.Background(), writeMessage, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, resolved.RssFeed.Items, 300)
+	})
+
+	t.Run("should fail on a claim check without a store", func(t *testing.T) {
+		// Act
+		_, err := message.ResolveWrite(context.Background(), message.Write{ClaimCheck: "write/claim-1.json"}, nil)
+
+		// Assert
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail on a claim check that is not in the store", func(t *testing.T) {
+		// Arrange
+		store := claimcheck.NewFileStore(t.TempDir())
+
+		// Act
+		_, err := message.ResolveWrite(context.Background(), message.Write{ClaimCheck: "write/claim-1.json"}, store)
+
+		// Assert
+		assert.ErrorIs(t, err, claimcheck.ErrNotFound)
+	})
+}
+
+func TestSplitRss(t *testing.T) {
+	t.Run("should split a large feed into parts with disjoint items under the size", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 500)
+		maxSize := 64 * 1024
+
+		// Act
+		parts, err := message.SplitRss(feed, maxSize)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Greater(t, len(parts), 1)
+
+		guids := map[rss.Guid]bool{}
+		for i, part := range parts {
+			partJson, err := json.Marshal(part)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(partJson), maxSize)
+			assert.Equal(t, feed.ID, part.ID)
+			assert.Equal(t, feed.Source, part.Source)
+			assert.Equal(t, rss.NewFetchParts(parts[0].Parts.FetchID, i+1, len(parts)), part.Parts)
+			assert.NotEmpty(t, part.Items)
+			for guid := range part.Items {
+				assert.False(t, guids[guid], guid.Value)
+				guids[guid] = true
+			}
+		}
+		assert.Len(t, guids, 500)
+	})
+
+	t.Run("should return a feed that fits as the only part", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 2)
+
+		// Act
+		parts, err := message.SplitRss(feed, message.MaxMessageSize)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, parts, 1)
+		assert.Len(t, parts[0].Items, 2)
+		assert.Nil(t, parts[0].Parts)
+	})
+}
+
+func TestNewWriteMessages(t *testing.T) {
+	t.Run("should number the parts of a large feed and send none of them compressed", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 500)
+
+		// Act
+		writeMessages, err := message.NewWriteMessages(context.Background(), feed, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Greater(t, len(writeMessages), 1)
+
+		itemCount := 0
+		for i, writeMessage := range writeMessages {
+			assert.Equal(t, i+1, writeMessage.Part)
+			assert.Equal(t, len(writeMessages), writeMessage.Parts)
+			assert.False(t, writeMessage.Compressed)
+			assert.Empty(t, writeMessage.ClaimCheck)
+
+			envelope, err := message.NewEnvelope(context.Background(), message.TypeWrite, writeMessage)
+			assert.NoError(t, err)
+			envelopeJson, err := json.Marshal(envelope)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(envelopeJson), message.MaxMessageSize)
+			itemCount += len(writeMessage.RssFeed.Items)
+		}
+		assert.Equal(t, 500, itemCount)
+	})
+
+	t.Run("should send a small feed as one message without part numbers", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 1)
+
+		// Act
+		writeMessages, err := message.NewWriteMessages(context.Background(), feed, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, writeMessages, 1)
+		assert.Zero(t, writeMessages[0].Part)
+		assert.Zero(t, writeMessages[0].Parts)
+	})
+
+	t.Run("should not split again a part passed on by a stage", func(t *testing.T) {
+		// Arrange
+		feed := newRss(t, 500)
+		feed.Parts = rss.NewFetchParts("fetch", 2, 3)
+
+		// Act
+		writeMessages, err := message.NewWriteMessages(context.Background(), feed, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, writeMessages, 1)
+		assert.Equal(t, 2, writeMessages[0].Part)
+		assert.Equal(t, 3, writeMessages[0].Parts)
+		assert.True(t, writeMessages[0].Compressed)
+	})
+}
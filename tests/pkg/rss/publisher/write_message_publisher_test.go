@@ -2,11 +2,13 @@ package publisher
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/YamazakiNorihito/workday/internal/domain/rss"
+	"github.com/YamazakiNorihito/workday/pkg/rss/message"
 	"github.com/YamazakiNorihito/workday/pkg/rss/publisher"
 	"github.com/YamazakiNorihito/workday/tests/helper"
 	"github.com/stretchr/testify/assert"
@@ -68,13 +70,16 @@ func TestWriterMessagePublisher_Publish(t *testing.T) {
 				  "description": "これはダミー記事1の概要です。詳細はリンクをクリックしてください。",
 				  "author": "item1@dummy.com",
 				  "pubDate": "2024-07-03T12:00:00Z",
-				  "tags": []
+				  "tags": [],
+				  "contentHash": "%s"
 				}
 			  },
 			  "item_filter":{
 			  	"include_keywords":["go","golang"],
 				"exclude_keywords":["python","ruby"]
 			  },
+			  "retention": {},
+			  "status": "active",
 			  "create_by": {
 				"id": "",
 				"name": ""
@@ -87,8 +92,12 @@ func TestWriterMessagePublisher_Publish(t *testing.T) {
 			  "update_at": "0001-01-01T00:00:00Z"
 			},
 			"compressed": false
-		  }`, test_rss.ID.String())
+		  }`, test_rss.ID.String(), test_rss.Items[rss.Guid{Value: "http://www.example.com/dummy-guid1"}].ContentHash)
 
-		assert.JSONEq(t, expectedJSON, messageClient.Messages[0])
+		var envelope message.Envelope
+		assert.NoError(t, json.Unmarshal([]byte(messageClient.Messages[0]), &envelope))
+		assert.Equal(t, message.TypeWrite, envelope.Type)
+		assert.Equal(t, message.EnvelopeVersion, envelope.Version)
+		assert.JSONEq(t, expectedJSON, string(envelope.Payload))
 	})
 }
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/YamazakiNorihito/workday/pkg/scheduler"
+	"github.com/YamazakiNorihito/workday/tests/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	t.Run("should add the interval", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, time.July, 1, 10, 7, 0, 0, time.UTC)
+
+		// Act
+		next := scheduler.Every(15 * time.Minute).Next(now)
+
+		// Assert
+		assert.Equal(t, time.Date(2024, time.July, 1, 10, 22, 0, 0, time.UTC), next)
+	})
+
+	t.Run("should run later today before the time of day", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, time.July, 1, 10, 0, 0, 0, time.UTC)
+
+		// Act
+		next := scheduler.DailyAt(18, 30, time.UTC).Next(now)
+
+		// Assert
+		assert.Equal(t, time.Date(2024, time.July, 1, 18, 30, 0, 0, time.UTC), next)
+	})
+
+	t.Run("should run tomorrow at or after the time of day", func(t *testing.T) {
+		// Arrange
+		schedule := scheduler.DailyAt(18, 30, time.UTC)
+
+		// Act
+		atTime := schedule.Next(time.Date(2024, time.July, 31, 18, 30, 0, 0, time.UTC))
+		afterTime := schedule.Next(time.Date(2024, time.July, 31, 20, 0, 0, 0, time.UTC))
+
+		// Assert
+		assert.Equal(t, time.Date(2024, time.August, 1, 18, 30, 0, 0, time.UTC), atTime)
+		assert.Equal(t, time.Date(2024, time.August, 1, 18, 30, 0, 0, time.UTC), afterTime)
+	})
+
+	t.Run("should use the time of day in the location", func(t *testing.T) {
+		// Arrange
+		tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+		now := time.Date(2024, time.July, 1, 10, 0, 0, 0, time.UTC)
+
+		// Act
+		next := scheduler.DailyAt(3, 0, tokyo).Next(now)
+
+		// Assert
+		assert.Equal(t, time.Date(2024, time.July, 1, 18, 0, 0, 0, time.UTC), next.UTC())
+	})
+}
+
+func TestScheduler_Run(t *testing.T) {
+	t.Run("should run the jobs on their schedule until the context is done", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithCancel(context.Background())
+		var runs, failures atomic.Int32
+		sut := scheduler.New(&helper.MockLogger{},
+			scheduler.Job{Name: "succeeding", Schedule: scheduler.Every(time.Millisecond), Run: func(ctx context.Context) error {
+				if runs.Add(1) == 3 {
+					cancel()
+				}
+				return nil
+			}},
+			scheduler.Job{Name: "failing", Schedule: scheduler.Every(time.Millisecond), Run: func(ctx context.Context) error {
+				failures.Add(1)
+				return errors.New("failed")
+			}},
+		)
+
+		// Act
+		sut.Run(ctx)
+
+		// Assert
+		assert.Equal(t, int32(3), runs.Load())
+		assert.Positive(t, failures.Load())
+	})
+
+	t.Run("should not run a job before its first scheduled time", func(t *testing.T) {
+		// Arrange
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		var runs atomic.Int32
+		sut := scheduler.New(&helper.MockLogger{},
+			scheduler.Job{Name: "daily", Schedule: scheduler.Every(time.Hour), Run: func(ctx context.Context) error {
+				runs.Add(1)
+				return nil
+			}},
+		)
+
+		// Act
+		sut.Run(ctx)
+
+		// Assert
+		assert.Zero(t, runs.Load())
+	})
+}